@@ -5,7 +5,9 @@
 //	// creates a string with a red foreground color
 //	color.Red("uh oh")
 //
-// Colors can be globally enabled or disabled by using SetEnabled.
+// By default, colors are only enabled if stdout is a terminal, so that colors are not
+// written to pipes or log files. Colors can be globally enabled or disabled by using SetEnabled.
+// EnabledFor can be used to determine if colors should be used when writing to a specific writer.
 // If you wish to control colors in a local scope and not affect the global state,
 // create a Colorer instance.
 //
@@ -14,15 +16,50 @@
 //	c.SetEnabled(false)
 //	s := c.Red("uh oh") // Will not be colored
 //
+// NewColorer creates a Colorer that is only enabled if a specific writer supports colors.
+//
+// Text styles like bold and underline are also supported, and can be combined with colors.
+//
+//	// creates a bold string with a red foreground color
+//	color.Bold(color.Red("uh oh"))
+//
+// Combinations of colors and text styles can be created once and reused with a Style.
+//
+//	warn := color.NewStyle(color.FgYellow, color.AttrBold)
+//	warn.Sprint("careful")
+//
+// Colors from the 256 color palette and 24-bit RGB colors are also supported
+// using Color256 and RGB. If the terminal does not support them, the closest
+// supported color is used instead.
+//
+//	color.RGB(255, 136, 0)("orange")
+//
+// Gradient and Rainbow color each rune of a string differently, which is useful for banners.
+//
 // This package also supports the NO_COLOR environment variable.
 // If NO_COLOR is set with any value, colors will be disabled.
 // See https://no-color.org for more details.
+//
+// Colors can be forced on, for example when piping output to 'less -R', by setting
+// the FORCE_COLOR or CLICOLOR_FORCE environment variables. Whether colors are enabled
+// is determined by the following, in order of precedence:
+//
+//  1. If NO_COLOR is set, colors are disabled.
+//  2. If SetEnabled was called, its value is used.
+//  3. If FORCE_COLOR or CLICOLOR_FORCE is set, colors are enabled.
+//  4. Colors are enabled if stdout is a terminal.
+//
+// On Windows, virtual terminal processing is enabled for the console the first
+// time it is checked, since escape sequences are not processed otherwise.
+// If it cannot be enabled, the console is not treated as a terminal.
 package color
 
 import (
+	"io"
 	"os"
 	"strconv"
 	"strings"
+	"sync/atomic"
 )
 
 type ansiCode int
@@ -40,9 +77,36 @@ const (
 	fgReset
 )
 
+const (
+	styleBold      ansiCode = 1
+	styleDim       ansiCode = 2
+	styleItalic    ansiCode = 3
+	styleUnderline ansiCode = 4
+	styleReverse   ansiCode = 7
+
+	// Bold and dim share the same reset code, since it resets the intensity.
+	intensityReset ansiCode = 22
+	italicReset    ansiCode = 23
+	underlineReset ansiCode = 24
+	reverseReset   ansiCode = 27
+)
+
 var (
 	noColor = os.Getenv("NO_COLOR") != "" // value doesn't matter, only if it's set
-	shared  Colorer
+	shared  = Colorer{global: true}
+)
+
+// The state of the shared Colorer used by the package level functions is stored
+// atomically, since it can be modified and used concurrently by multiple goroutines.
+var (
+	globalEnabled atomic.Int32 // one of enabledAuto, enabledOn or enabledOff
+	globalLevel   atomic.Int32 // level + 1, or 0 if the detected level is used
+)
+
+const (
+	enabledAuto int32 = iota // enablement is detected from stdout
+	enabledOn
+	enabledOff
 )
 
 // IsNoColorEnvSet returns true if the NO_COLOR environment variable is set, regardless of its value.
@@ -59,14 +123,84 @@ func IsNoColorEnvSet() bool {
 // Colors are enabled by default, unless NO_COLOR is set.
 type Colorer struct {
 	disabled bool // disabled so the zero value is enabled
+	level    ColorLevel
+	levelSet bool // whether level was set, otherwise the detected level is used
+	global   bool // whether this is the shared Colorer, which uses the global state instead
+}
+
+// NewColorer returns a Colorer for creating strings that will be written to w.
+// Colors are only enabled if EnabledFor(w) returns true, which allows coloring
+// output for one writer independently of the global state and other writers.
+func NewColorer(w io.Writer) *Colorer {
+	c := &Colorer{}
+	c.SetEnabled(EnabledFor(w))
+	return c
 }
 
 // SetEnabled sets whether color is enabled or disabled.
 // Note that if NO_COLOR is set this will have no effect.
 func (c *Colorer) SetEnabled(e bool) {
+	if c.global {
+		globalEnabled.Store(enabledState(e))
+		return
+	}
 	c.disabled = !e
 }
 
+func enabledState(e bool) int32 {
+	if e {
+		return enabledOn
+	}
+	return enabledOff
+}
+
+// enabled reports whether c should create colored strings.
+func (c *Colorer) enabled() bool {
+	// NO_COLOR always takes precedence.
+	if noColor {
+		return false
+	}
+	if !c.global {
+		return !c.disabled
+	}
+	switch globalEnabled.Load() {
+	case enabledOn:
+		return true
+	case enabledOff:
+		return false
+	}
+	return forceColor || stdoutIsTerminal()
+}
+
+// SetLevel sets the level of color support used by c. Colors that are not supported
+// by the level are downgraded to the closest supported color. By default, the level
+// is detected from the environment, see Level for details.
+func (c *Colorer) SetLevel(l ColorLevel) {
+	if c.global {
+		globalLevel.Store(int32(l) + 1)
+		return
+	}
+	c.level = l
+	c.levelSet = true
+}
+
+// Style applies st to s, using the enablement and color level of c
+// instead of the global state.
+func (c *Colorer) Style(st Style, s string) string {
+	return st.applyFor(c, s)
+}
+
+func (c *Colorer) colorLevel() ColorLevel {
+	if c.global {
+		if l := globalLevel.Load(); l > 0 {
+			return ColorLevel(l - 1)
+		}
+	} else if c.levelSet {
+		return c.level
+	}
+	return detectedLevel()
+}
+
 // Black creates a black colored string.
 func (c *Colorer) Black(s string) string {
 	return c.apply(s, fgBlack, fgReset)
@@ -107,48 +241,212 @@ func (c *Colorer) White(s string) string {
 	return c.apply(s, fgWhite, fgReset)
 }
 
+// Bold creates a bold string.
+func (c *Colorer) Bold(s string) string {
+	return c.apply(s, styleBold, intensityReset)
+}
+
+// Dim creates a dim, also known as faint, string.
+func (c *Colorer) Dim(s string) string {
+	return c.apply(s, styleDim, intensityReset)
+}
+
+// Italic creates an italic string.
+func (c *Colorer) Italic(s string) string {
+	return c.apply(s, styleItalic, italicReset)
+}
+
+// Underline creates an underlined string.
+func (c *Colorer) Underline(s string) string {
+	return c.apply(s, styleUnderline, underlineReset)
+}
+
+// Reverse creates a string with the foreground and background colors swapped.
+func (c *Colorer) Reverse(s string) string {
+	return c.apply(s, styleReverse, reverseReset)
+}
+
 func (c *Colorer) apply(s string, start, end ansiCode) string {
-	// NO_COLOR always takes precedence.
-	if noColor || c.disabled {
+	if !c.enabled() {
 		return s
 	}
+	return applySeq(s, start.seq(), end)
+}
 
-	const prefix = "\x1b["
+// applyParams colors s using the SGR parameters in start, for example "38;5;208".
+func (c *Colorer) applyParams(s, start string, end ansiCode) string {
+	if !c.enabled() {
+		return s
+	}
+	return applySeq(s, "\x1b["+start+"m", end)
+}
+
+// applySeq wraps s with the escape sequence start and the sequence of reset.
+func applySeq(s, start string, reset ansiCode) string {
+	// Fast path, nothing to restore if s has no escape sequences.
+	if strings.IndexByte(s, '\x1b') == -1 {
+		return start + s + reset.seq()
+	}
 	var sb strings.Builder
-	// Build out reset for the end
-	sb.WriteString(prefix)
-	sb.WriteString(strconv.Itoa(int(end)))
-	sb.WriteByte('m')
-	reset := sb.String()
-	sb.Reset()
-
-	// Build colored string.
-	// We also want to check if there are any occurrences of reset
-	// in s and remove them so that the color isn't messed up.
-	sb.WriteString(prefix)
-	sb.WriteString(strconv.Itoa(int(start)))
-	sb.WriteByte('m')
+	sb.Grow(len(start) + len(s) + len(reset.seq()))
+	sb.WriteString(start)
+	writeNested(&sb, s, start, []ansiCode{reset})
+	sb.WriteString(reset.seq())
+	return sb.String()
+}
 
+// writeNested writes s to sb, which is being wrapped with start. Any SGR sequence in s
+// that contains one of the codes in resets, or a full reset, is from a string nested
+// inside s, so start is written after it to restore the outer style once the nested
+// one ends. For example:
+//
+//	color.Red("a " + color.Blue("b") + " c")
+//
+// results in "b" being blue, and "a" and "c" being red.
+func writeNested(sb *strings.Builder, s, start string, resets []ansiCode) {
 	// We are only dealing with ASCII so it's safe to look at individual bytes.
 	j := 0
+	restore := false // whether start needs to be written before the next text
 	for i := 0; i < len(s); i++ {
-		if s[i] == '\x1b' && strings.HasPrefix(s[i:], reset) {
+		if s[i] != '\x1b' {
+			continue
+		}
+		end, ok := sgrResets(s, i, resets)
+		if !ok {
+			continue
+		}
+		if i > j {
+			if restore {
+				sb.WriteString(start)
+			}
 			sb.WriteString(s[j:i])
-			i += len(reset) - 1 // -1 to account for i++
-			j = i + 1
+		} else if restore && isReset(s[i:end], resets) {
+			// Collapse consecutive resets.
+			i = end - 1
+			j = end
+			continue
 		}
+		// A plain reset at the end of s is redundant since the outer
+		// style is reset right after.
+		if end < len(s) || !isReset(s[i:end], resets) {
+			sb.WriteString(s[i:end])
+		}
+		restore = true
+		i = end - 1 // -1 to account for i++
+		j = end
 	}
-	sb.WriteString(s[j:])
-	sb.WriteString(reset)
-	return sb.String()
+	// Nothing needs to be restored for a reset at the end of s since the
+	// outer style is reset right after.
+	if j < len(s) {
+		if restore {
+			sb.WriteString(start)
+		}
+		sb.WriteString(s[j:])
+	}
+}
+
+// sgrResets reports whether an SGR sequence, like "\x1b[22;39m", starts at s[i] and
+// contains one of the codes in resets or a full reset. It also returns the end of the
+// sequence.
+func sgrResets(s string, i int, resets []ansiCode) (int, bool) {
+	if i+2 > len(s) || s[i+1] != '[' {
+		return 0, false
+	}
+	end := i + 2
+	for end < len(s) && (isDigit(s[end]) || s[end] == ';' || s[end] == ':') {
+		end++
+	}
+	if end == len(s) || s[end] != 'm' {
+		return 0, false
+	}
+	params := strings.Split(s[i+2:end], ";")
+	found := false
+	for k := 0; k < len(params); k++ {
+		p := params[k]
+		if p == "" || p == "0" {
+			found = true
+			break
+		}
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			// Colon separated sub-parameters, e.g. "38:5:208", are never resets.
+			continue
+		}
+		switch n {
+		case 38, 48, 58:
+			// Skip the arguments of extended colors so that color
+			// indices are not mistaken for reset codes.
+			if k+1 < len(params) {
+				switch params[k+1] {
+				case "5":
+					k += 2
+				case "2":
+					k += 4
+				}
+			}
+			continue
+		}
+		if containsCode(resets, ansiCode(n)) {
+			found = true
+			break
+		}
+	}
+	return end + 1, found
+}
+
+// isReset reports whether seq is exactly the sequence of one of the codes in resets.
+func isReset(seq string, resets []ansiCode) bool {
+	for _, code := range resets {
+		if seq == code.seq() {
+			return true
+		}
+	}
+	return false
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// seqs contains the escape sequence of each ansiCode, so they do not
+// need to be built every time a string is colored.
+var seqs = func() (seqs [108]string) {
+	for i := range seqs {
+		seqs[i] = "\x1b[" + strconv.Itoa(i) + "m"
+	}
+	return
+}()
+
+// seq returns the escape sequence for c.
+func (c ansiCode) seq() string {
+	if c >= 0 && int(c) < len(seqs) {
+		return seqs[c]
+	}
+	return "\x1b[" + strconv.Itoa(int(c)) + "m"
 }
 
 // SetEnabled sets whether color is enabled or disabled.
 // Note that if NO_COLOR is set this will have no effect.
+// It is safe to call SetEnabled concurrently with other functions in this package.
 func SetEnabled(e bool) {
 	shared.SetEnabled(e)
 }
 
+// WithEnabled calls fn with colors enabled or disabled according to e, and then
+// restores the previous state, even if fn panics.
+//
+//	color.WithEnabled(false, func() {
+//		fmt.Println(color.Red("not colored"))
+//	})
+//
+// Note that the state is global, so other goroutines that
+// use this package while fn is running are also affected.
+func WithEnabled(e bool, fn func()) {
+	prev := globalEnabled.Swap(enabledState(e))
+	defer globalEnabled.Store(prev)
+	fn()
+}
+
 // Black creates a black colored string.
 func Black(s string) string {
 	return shared.Black(s)
@@ -188,3 +486,28 @@ func Cyan(s string) string {
 func White(s string) string {
 	return shared.White(s)
 }
+
+// Bold creates a bold string.
+func Bold(s string) string {
+	return shared.Bold(s)
+}
+
+// Dim creates a dim, also known as faint, string.
+func Dim(s string) string {
+	return shared.Dim(s)
+}
+
+// Italic creates an italic string.
+func Italic(s string) string {
+	return shared.Italic(s)
+}
+
+// Underline creates an underlined string.
+func Underline(s string) string {
+	return shared.Underline(s)
+}
+
+// Reverse creates a string with the foreground and background colors swapped.
+func Reverse(s string) string {
+	return shared.Reverse(s)
+}
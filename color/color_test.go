@@ -1,6 +1,7 @@
 package color_test
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/TouchBistro/goutils/color"
@@ -74,15 +75,67 @@ func TestColors(t *testing.T) {
 	}
 }
 
-func TestStripReset(t *testing.T) {
+func TestStyles(t *testing.T) {
+	color.SetEnabled(true)
+	tests := []struct {
+		name    string
+		styleFn func(string) string
+		want    string
+	}{
+		{"Bold", color.Bold, "\x1b[1mfoo bar\x1b[22m"},
+		{"Dim", color.Dim, "\x1b[2mfoo bar\x1b[22m"},
+		{"Italic", color.Italic, "\x1b[3mfoo bar\x1b[23m"},
+		{"Underline", color.Underline, "\x1b[4mfoo bar\x1b[24m"},
+		{"Reverse", color.Reverse, "\x1b[7mfoo bar\x1b[27m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.styleFn("foo bar"); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStylesCompose(t *testing.T) {
+	color.SetEnabled(true)
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"bold red", color.Bold(color.Red("foo")), "\x1b[1m\x1b[31mfoo\x1b[39m\x1b[22m"},
+		{"red underline", color.Red(color.Underline("foo")), "\x1b[31m\x1b[4mfoo\x1b[24m\x1b[39m"},
+		{"bold dim", color.Bold(color.Dim("foo")), "\x1b[1m\x1b[2mfoo\x1b[22m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNestedReset(t *testing.T) {
 	color.SetEnabled(true)
 	tests := []struct {
 		name string
 		in   string
 		want string
 	}{
-		{"single reset", "foo \x1b[39mbar", "\x1b[31mfoo bar\x1b[39m"},
-		{"multiple resets", "foo \x1b[39m\x1b[39mbar", "\x1b[31mfoo bar\x1b[39m"},
+		{"single reset", "foo \x1b[39mbar", "\x1b[31mfoo \x1b[39m\x1b[31mbar\x1b[39m"},
+		{"multiple resets", "foo \x1b[39m\x1b[39mbar", "\x1b[31mfoo \x1b[39m\x1b[31mbar\x1b[39m"},
+		{"reset at end", "foo \x1b[39m", "\x1b[31mfoo \x1b[39m"},
+		{"nested color", "a " + color.Blue("b") + " c", "\x1b[31ma \x1b[34mb\x1b[39m\x1b[31m c\x1b[39m"},
+		{"nested color at end", "a " + color.Blue("b"), "\x1b[31ma \x1b[34mb\x1b[39m"},
+		{
+			"nested style",
+			"a " + color.NewStyle(color.FgBlue, color.AttrBold).Sprint("b") + " c",
+			"\x1b[31ma \x1b[34;1mb\x1b[22;39m\x1b[31m c\x1b[39m",
+		},
+		{"full reset", "foo \x1b[0mbar", "\x1b[31mfoo \x1b[0m\x1b[31mbar\x1b[39m"},
+		{"extended color index", "foo \x1b[38;5;39mbar", "\x1b[31mfoo \x1b[38;5;39mbar\x1b[39m"},
 	}
 	for _, tt := range tests {
 		got := color.Red(tt.in)
@@ -116,14 +169,58 @@ func TestColorerDisabled(t *testing.T) {
 
 func BenchmarkRed(b *testing.B) {
 	color.SetEnabled(true)
-	b.Run("no strip", func(b *testing.B) {
+	b.Run("not nested", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
 			color.Red("foo bar")
 		}
 	})
-	b.Run("strip", func(b *testing.B) {
+	b.Run("nested", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
 			color.Red("foo \x1b[39m\x1b[39mbar")
 		}
 	})
 }
+
+func TestWithEnabled(t *testing.T) {
+	color.SetEnabled(true)
+	color.WithEnabled(false, func() {
+		if got, want := color.Red("foo"), "foo"; got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+		color.WithEnabled(true, func() {
+			if got, want := color.Red("foo"), "\x1b[31mfoo\x1b[39m"; got != want {
+				t.Errorf("got %q, want %q", got, want)
+			}
+		})
+		if got, want := color.Red("foo"), "foo"; got != want {
+			t.Errorf("got %q, want %q after nested WithEnabled", got, want)
+		}
+	})
+	if got, want := color.Red("foo"), "\x1b[31mfoo\x1b[39m"; got != want {
+		t.Errorf("got %q, want %q after WithEnabled", got, want)
+	}
+}
+
+func TestSetEnabledConcurrent(t *testing.T) {
+	defer color.SetEnabled(true)
+	defer color.SetLevel(color.LevelTrueColor)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(e bool) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				color.SetEnabled(e)
+				color.SetLevel(color.Level256)
+			}
+		}(i%2 == 0)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = color.Red("foo")
+				_ = color.RGB(255, 0, 0)("foo")
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,121 @@
+package color
+
+// Color256 returns a function that creates strings colored with color n from
+// the 256 color palette. If the terminal only supports the basic colors,
+// the closest basic color is used instead.
+func (c *Colorer) Color256(n uint8) func(string) string {
+	return c.attrFunc(Fg256(n))
+}
+
+// RGB returns a function that creates strings colored with the 24-bit color
+// specified by r, g and b. If the terminal does not support 24-bit colors,
+// the closest color from the 256 color palette or the basic colors is used instead.
+func (c *Colorer) RGB(r, g, b uint8) func(string) string {
+	return c.attrFunc(FgRGB(r, g, b))
+}
+
+// attrFunc returns a function that applies the foreground color a.
+func (c *Colorer) attrFunc(a Attribute) func(string) string {
+	return func(s string) string {
+		l := c.colorLevel()
+		if l == LevelNone {
+			return s
+		}
+		return c.applyParams(s, a.params(l), fgReset)
+	}
+}
+
+// Color256 returns a function that creates strings colored with color n from
+// the 256 color palette. See Colorer.Color256.
+func Color256(n uint8) func(string) string {
+	return shared.Color256(n)
+}
+
+// RGB returns a function that creates strings colored with the 24-bit color
+// specified by r, g and b. See Colorer.RGB.
+func RGB(r, g, b uint8) func(string) string {
+	return shared.RGB(r, g, b)
+}
+
+// basicColors are the RGB values of the 16 basic colors as used by xterm.
+// The index is the offset of the color from fgBlack, with the bright colors
+// offset from fgBrightBlack.
+var basicColors = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// fgBrightBlack is the code of the first bright foreground color.
+const fgBrightBlack ansiCode = 90
+
+// nearest16 returns the code of the basic foreground color closest to r, g, b.
+func nearest16(r, g, b uint8) ansiCode {
+	best, bestDist := 0, -1
+	for i, c := range basicColors {
+		if d := dist(r, g, b, c[0], c[1], c[2]); bestDist < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	if best < 8 {
+		return fgBlack + ansiCode(best)
+	}
+	return fgBrightBlack + ansiCode(best-8)
+}
+
+// cubeLevels are the values of each component in the 6x6x6 color cube
+// that makes up colors 16-231 of the 256 color palette.
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// rgbTo256 returns the color from the 256 color palette closest to r, g, b.
+// Only the color cube and the grayscale ramp are considered, since the
+// first 16 colors vary between terminals.
+func rgbTo256(r, g, b uint8) uint8 {
+	ri, gi, bi := cubeIndex(r), cubeIndex(g), cubeIndex(b)
+	cube := 16 + 36*ri + 6*gi + bi
+	cubeDist := dist(r, g, b, cubeLevels[ri], cubeLevels[gi], cubeLevels[bi])
+
+	// Grayscale ramp is 232-255 with values 8, 18, ..., 238.
+	avg := (int(r) + int(g) + int(b)) / 3
+	gray := 0
+	if avg > 8 {
+		gray = min(23, (avg-3)/10)
+	}
+	gv := uint8(8 + 10*gray)
+	if dist(r, g, b, gv, gv, gv) < cubeDist {
+		return uint8(232 + gray)
+	}
+	return uint8(cube)
+}
+
+// cubeIndex returns the index of the cube level closest to v.
+func cubeIndex(v uint8) int {
+	if v < 48 {
+		return 0
+	}
+	if v < 115 {
+		return 1
+	}
+	return (int(v) - 35) / 40
+}
+
+// rgbFrom256 returns the RGB value of color n from the 256 color palette.
+func rgbFrom256(n uint8) (r, g, b uint8) {
+	switch {
+	case n < 16:
+		c := basicColors[n]
+		return c[0], c[1], c[2]
+	case n < 232:
+		n -= 16
+		return cubeLevels[n/36], cubeLevels[n/6%6], cubeLevels[n%6]
+	}
+	v := 8 + 10*(n-232)
+	return v, v, v
+}
+
+// dist returns the squared euclidean distance between two colors.
+func dist(r1, g1, b1, r2, g2, b2 uint8) int {
+	dr, dg, db := int(r1)-int(r2), int(g1)-int(g2), int(b1)-int(b2)
+	return dr*dr + dg*dg + db*db
+}
@@ -0,0 +1,83 @@
+package color_test
+
+import (
+	"testing"
+
+	"github.com/TouchBistro/goutils/color"
+)
+
+func TestExtendedColors(t *testing.T) {
+	tests := []struct {
+		name  string
+		level color.ColorLevel
+		fn    func(c *color.Colorer) func(string) string
+		want  string
+	}{
+		{
+			"256",
+			color.Level256,
+			func(c *color.Colorer) func(string) string { return c.Color256(208) },
+			"\x1b[38;5;208mfoo\x1b[39m",
+		},
+		{
+			"256 downgraded to 16",
+			color.Level16,
+			func(c *color.Colorer) func(string) string { return c.Color256(196) },
+			"\x1b[91mfoo\x1b[39m",
+		},
+		{
+			"256 basic downgraded to 16",
+			color.Level16,
+			func(c *color.Colorer) func(string) string { return c.Color256(2) },
+			"\x1b[32mfoo\x1b[39m",
+		},
+		{
+			"RGB",
+			color.LevelTrueColor,
+			func(c *color.Colorer) func(string) string { return c.RGB(255, 136, 0) },
+			"\x1b[38;2;255;136;0mfoo\x1b[39m",
+		},
+		{
+			"RGB downgraded to 256",
+			color.Level256,
+			func(c *color.Colorer) func(string) string { return c.RGB(255, 136, 0) },
+			"\x1b[38;5;208mfoo\x1b[39m",
+		},
+		{
+			"RGB gray downgraded to 256",
+			color.Level256,
+			func(c *color.Colorer) func(string) string { return c.RGB(128, 128, 128) },
+			"\x1b[38;5;244mfoo\x1b[39m",
+		},
+		{
+			"RGB downgraded to 16",
+			color.Level16,
+			func(c *color.Colorer) func(string) string { return c.RGB(10, 10, 200) },
+			"\x1b[34mfoo\x1b[39m",
+		},
+		{
+			"no color support",
+			color.LevelNone,
+			func(c *color.Colorer) func(string) string { return c.RGB(255, 136, 0) },
+			"foo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c color.Colorer
+			c.SetLevel(tt.level)
+			if got := tt.fn(&c)("foo"); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtendedColorsDisabled(t *testing.T) {
+	var c color.Colorer
+	c.SetEnabled(false)
+	c.SetLevel(color.LevelTrueColor)
+	if got, want := c.RGB(255, 136, 0)("foo"), "foo"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,37 @@
+package color
+
+import (
+	"fmt"
+	"io"
+)
+
+// Fprint formats using the default formats for its operands, like fmt.Fprint, and writes
+// the result to w with st applied. The style is only applied if EnabledFor(w) returns true,
+// regardless of whether colors are globally enabled. It returns the number of bytes
+// written and any write error encountered.
+func Fprint(w io.Writer, st Style, a ...any) (int, error) {
+	return io.WriteString(w, styleFor(w, st, fmt.Sprint(a...)))
+}
+
+// Fprintf formats according to a format specifier, like fmt.Fprintf, and writes the
+// result to w with st applied. The style is only applied if EnabledFor(w) returns true,
+// regardless of whether colors are globally enabled. It returns the number of bytes
+// written and any write error encountered.
+func Fprintf(w io.Writer, st Style, format string, a ...any) (int, error) {
+	return io.WriteString(w, styleFor(w, st, fmt.Sprintf(format, a...)))
+}
+
+// Fprintln formats using the default formats for its operands, like fmt.Fprintln, and
+// writes the result to w with st applied. The trailing newline is not styled.
+// See Fprint for when the style is applied.
+func Fprintln(w io.Writer, st Style, a ...any) (int, error) {
+	s := fmt.Sprintln(a...)
+	return io.WriteString(w, styleFor(w, st, s[:len(s)-1])+"\n")
+}
+
+// styleFor applies st to s if w supports colors.
+func styleFor(w io.Writer, st Style, s string) string {
+	var c Colorer
+	c.SetEnabled(EnabledFor(w))
+	return c.Style(st, s)
+}
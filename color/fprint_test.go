@@ -0,0 +1,55 @@
+package color_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TouchBistro/goutils/color"
+)
+
+func TestFprint(t *testing.T) {
+	// Colors are globally enabled, but should not be used for non-terminals.
+	color.SetEnabled(true)
+	st := color.NewStyle(color.FgRed, color.AttrBold)
+	tests := []struct {
+		name string
+		fn   func(sb *strings.Builder) (int, error)
+		want string
+	}{
+		{
+			"Fprint",
+			func(sb *strings.Builder) (int, error) { return color.Fprint(sb, st, "foo", 3) },
+			"foo3",
+		},
+		{
+			"Fprintf",
+			func(sb *strings.Builder) (int, error) { return color.Fprintf(sb, st, "%d files", 3) },
+			"3 files",
+		},
+		{
+			"Fprintln",
+			func(sb *strings.Builder) (int, error) { return color.Fprintln(sb, st, "foo", "bar") },
+			"foo bar\n",
+		},
+		{
+			"strip Writer",
+			func(sb *strings.Builder) (int, error) { return color.Fprintf(color.NewWriter(sb), st, "%s", "foo") },
+			"foo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			n, err := tt.fn(&sb)
+			if err != nil {
+				t.Fatalf("want nil error, got %v", err)
+			}
+			if got := sb.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if n != len(tt.want) {
+				t.Errorf("got n %d, want %d", n, len(tt.want))
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package color
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// RGBColor is a 24-bit color.
+type RGBColor struct {
+	R, G, B uint8
+}
+
+// Gradient creates a string where each rune is colored along a gradient from
+// the color from to the color to. If the terminal does not support 24-bit colors,
+// the closest supported color is used for each rune.
+func (c *Colorer) Gradient(s string, from, to RGBColor) string {
+	n := utf8.RuneCountInString(s)
+	return c.colorRunes(s, func(i int) RGBColor {
+		if n <= 1 {
+			return from
+		}
+		t := float64(i) / float64(n-1)
+		return RGBColor{
+			R: lerp(from.R, to.R, t),
+			G: lerp(from.G, to.G, t),
+			B: lerp(from.B, to.B, t),
+		}
+	})
+}
+
+// Rainbow creates a string where each rune is colored along the hues of a rainbow,
+// from red to violet. See Gradient for how colors are downgraded.
+func (c *Colorer) Rainbow(s string) string {
+	n := utf8.RuneCountInString(s)
+	return c.colorRunes(s, func(i int) RGBColor {
+		if n <= 1 {
+			return hue(0)
+		}
+		// Stop at violet instead of going all the way back around to red.
+		return hue(300 * float64(i) / float64(n-1))
+	})
+}
+
+// colorRunes colors each rune in s using the color returned by colorAt for the index of the rune.
+func (c *Colorer) colorRunes(s string, colorAt func(i int) RGBColor) string {
+	l := c.colorLevel()
+	if !c.enabled() || l == LevelNone || s == "" {
+		return s
+	}
+	var sb strings.Builder
+	i := 0
+	var prev string
+	for _, r := range s {
+		// Whitespace isn't visible so it doesn't need to be colored.
+		if !unicode.IsSpace(r) {
+			col := colorAt(i)
+			// Avoid repeating sequences for runes that downgrade to the same color.
+			if params := FgRGB(col.R, col.G, col.B).params(l); params != prev {
+				sb.WriteString("\x1b[")
+				sb.WriteString(params)
+				sb.WriteByte('m')
+				prev = params
+			}
+		}
+		sb.WriteRune(r)
+		i++
+	}
+	sb.WriteString(fgReset.seq())
+	return sb.String()
+}
+
+// lerp linearly interpolates between a and b.
+func lerp(a, b uint8, t float64) uint8 {
+	return uint8(float64(a) + (float64(b)-float64(a))*t + 0.5)
+}
+
+// hue returns the fully saturated color with the hue h in degrees.
+func hue(h float64) RGBColor {
+	x := func(n float64) uint8 {
+		k := n + h/60
+		for k >= 6 {
+			k -= 6
+		}
+		v := min(k, 4-k, 1)
+		return uint8(255*(1-max(v, 0)) + 0.5)
+	}
+	return RGBColor{R: x(5), G: x(3), B: x(1)}
+}
+
+// Gradient creates a string where each rune is colored along a gradient
+// from the color from to the color to. See Colorer.Gradient.
+func Gradient(s string, from, to RGBColor) string {
+	return shared.Gradient(s, from, to)
+}
+
+// Rainbow creates a string where each rune is colored along the hues
+// of a rainbow. See Colorer.Rainbow.
+func Rainbow(s string) string {
+	return shared.Rainbow(s)
+}
@@ -0,0 +1,65 @@
+package color_test
+
+import (
+	"testing"
+
+	"github.com/TouchBistro/goutils/color"
+)
+
+func TestGradient(t *testing.T) {
+	black := color.RGBColor{R: 0, G: 0, B: 0}
+	white := color.RGBColor{R: 255, G: 255, B: 255}
+	tests := []struct {
+		name  string
+		level color.ColorLevel
+		in    string
+		want  string
+	}{
+		{"empty", color.LevelTrueColor, "", ""},
+		{
+			"truecolor",
+			color.LevelTrueColor,
+			"abc",
+			"\x1b[38;2;0;0;0ma\x1b[38;2;128;128;128mb\x1b[38;2;255;255;255mc\x1b[39m",
+		},
+		{
+			"whitespace not colored",
+			color.LevelTrueColor,
+			"a c",
+			"\x1b[38;2;0;0;0ma \x1b[38;2;255;255;255mc\x1b[39m",
+		},
+		{
+			"256 fallback",
+			color.Level256,
+			"abc",
+			"\x1b[38;5;16ma\x1b[38;5;244mb\x1b[38;5;231mc\x1b[39m",
+		},
+		{"single rune", color.LevelTrueColor, "a", "\x1b[38;2;0;0;0ma\x1b[39m"},
+		{"no color support", color.LevelNone, "abc", "abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c color.Colorer
+			c.SetLevel(tt.level)
+			if got := c.Gradient(tt.in, black, white); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRainbow(t *testing.T) {
+	var c color.Colorer
+	c.SetLevel(color.LevelTrueColor)
+	got := c.Rainbow("abc")
+	want := "\x1b[38;2;255;0;0ma\x1b[38;2;0;255;128mb\x1b[38;2;255;0;255mc\x1b[39m"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	c.SetLevel(color.Level16)
+	got = c.Rainbow("abc")
+	want = "\x1b[91ma\x1b[36mb\x1b[95mc\x1b[39m"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,60 @@
+package color
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseHex parses a hex color of the form "#rrggbb" or the short form "#rgb".
+// The leading '#' is optional.
+func ParseHex(s string) (RGBColor, error) {
+	h := strings.TrimPrefix(s, "#")
+	if len(h) == 3 {
+		h = string([]byte{h[0], h[0], h[1], h[1], h[2], h[2]})
+	}
+	if len(h) != 6 {
+		return RGBColor{}, fmt.Errorf("color: invalid hex color %q", s)
+	}
+	var v [3]uint8
+	for i := range v {
+		hi, ok1 := hexDigit(h[2*i])
+		lo, ok2 := hexDigit(h[2*i+1])
+		if !ok1 || !ok2 {
+			return RGBColor{}, fmt.Errorf("color: invalid hex color %q", s)
+		}
+		v[i] = hi<<4 | lo
+	}
+	return RGBColor{R: v[0], G: v[1], B: v[2]}, nil
+}
+
+func hexDigit(c byte) (uint8, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// Hex returns a function that creates strings colored with the hex color s,
+// for example "#ff8800". It allows using colors specified by designers directly.
+// If the terminal does not support 24-bit colors, the closest color from the
+// 256 color palette or the basic colors is used instead.
+//
+// Hex panics if s is not a valid hex color, see ParseHex for the supported forms.
+func (c *Colorer) Hex(s string) func(string) string {
+	col, err := ParseHex(s)
+	if err != nil {
+		panic(err)
+	}
+	return c.RGB(col.R, col.G, col.B)
+}
+
+// Hex returns a function that creates strings colored with the hex color s.
+// See Colorer.Hex.
+func Hex(s string) func(string) string {
+	return shared.Hex(s)
+}
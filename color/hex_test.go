@@ -0,0 +1,63 @@
+package color_test
+
+import (
+	"testing"
+
+	"github.com/TouchBistro/goutils/color"
+)
+
+func TestParseHex(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    color.RGBColor
+		wantErr bool
+	}{
+		{"#ff8800", color.RGBColor{R: 255, G: 136, B: 0}, false},
+		{"FF8800", color.RGBColor{R: 255, G: 136, B: 0}, false},
+		{"#f80", color.RGBColor{R: 255, G: 136, B: 0}, false},
+		{"#000000", color.RGBColor{}, false},
+		{"#ff88", color.RGBColor{}, true},
+		{"#gg8800", color.RGBColor{}, true},
+		{"", color.RGBColor{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := color.ParseHex(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got err %v, want err %t", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHex(t *testing.T) {
+	tests := []struct {
+		level color.ColorLevel
+		want  string
+	}{
+		{color.LevelTrueColor, "\x1b[38;2;255;136;0mfoo\x1b[39m"},
+		{color.Level256, "\x1b[38;5;208mfoo\x1b[39m"},
+		{color.Level16, "\x1b[33mfoo\x1b[39m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			var c color.Colorer
+			c.SetLevel(tt.level)
+			if got := c.Hex("#ff8800")("foo"); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHexPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("want Hex to panic for invalid color")
+		}
+	}()
+	color.Hex("#nope")
+}
@@ -0,0 +1,102 @@
+package color
+
+import (
+	"os"
+	"strings"
+	"sync"
+)
+
+// ColorLevel is the level of color support of a terminal.
+type ColorLevel uint8
+
+const (
+	// LevelNone means colors are not supported.
+	LevelNone ColorLevel = iota
+	// Level16 means the 16 basic ANSI colors are supported.
+	Level16
+	// Level256 means the 256 color palette is supported.
+	Level256
+	// LevelTrueColor means 24-bit RGB colors are supported.
+	LevelTrueColor
+)
+
+func (l ColorLevel) String() string {
+	switch l {
+	case LevelNone:
+		return "none"
+	case Level16:
+		return "16"
+	case Level256:
+		return "256"
+	case LevelTrueColor:
+		return "truecolor"
+	}
+	return "unknown"
+}
+
+// detectedLevel returns the level of color support detected from the environment.
+// Detection only happens once, the first time it is needed.
+var detectedLevel = sync.OnceValue(detectLevel)
+
+// Level returns the level of color support used by the package level functions.
+// Unless SetLevel was called, it is the level detected from the environment.
+// Colors that are not supported by the level are downgraded to the closest supported color.
+//
+// The level is detected using the following, in order:
+//
+//  1. FORCE_COLOR set to 1, 2 or 3 sets the level to Level16, Level256 or LevelTrueColor.
+//  2. TERM set to dumb means colors are not supported.
+//  3. COLORTERM set to truecolor or 24bit means 24-bit colors are supported.
+//  4. Terminals known to support 24-bit or 256 colors, based on TERM_PROGRAM and WT_SESSION.
+//  5. TERM containing 256color means the 256 color palette is supported.
+//  6. On Windows, consoles with virtual terminal processing support 24-bit colors.
+//
+// Otherwise, the basic colors are assumed to be supported.
+func Level() ColorLevel {
+	return shared.colorLevel()
+}
+
+// SetLevel sets the level of color support used by the package level functions.
+func SetLevel(l ColorLevel) {
+	shared.SetLevel(l)
+}
+
+// detectLevel determines the level of color support using the environment.
+func detectLevel() ColorLevel {
+	switch os.Getenv("FORCE_COLOR") {
+	case "1":
+		return Level16
+	case "2":
+		return Level256
+	case "3":
+		return LevelTrueColor
+	}
+	term := os.Getenv("TERM")
+	if term == "dumb" {
+		return LevelNone
+	}
+	switch os.Getenv("COLORTERM") {
+	case "truecolor", "24bit":
+		return LevelTrueColor
+	}
+	if os.Getenv("WT_SESSION") != "" {
+		// Windows Terminal
+		return LevelTrueColor
+	}
+	switch os.Getenv("TERM_PROGRAM") {
+	case "iTerm.app", "vscode", "WezTerm":
+		return LevelTrueColor
+	case "Apple_Terminal":
+		return Level256
+	}
+	if strings.HasSuffix(term, "-direct") || strings.Contains(term, "truecolor") {
+		return LevelTrueColor
+	}
+	if strings.Contains(term, "256color") {
+		return Level256
+	}
+	if l, ok := consoleLevel(); ok {
+		return l
+	}
+	return Level16
+}
@@ -0,0 +1,57 @@
+package color_test
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/TouchBistro/goutils/color"
+)
+
+func TestLevel(t *testing.T) {
+	// Environment variables are read on initialization, so run the test in a subprocess.
+	if want := os.Getenv("COLOR_TEST_WANT_LEVEL"); want != "" {
+		if got := color.Level().String(); got != want {
+			t.Errorf("got level %s, want %s", got, want)
+		}
+		return
+	}
+	tests := []struct {
+		name string
+		env  []string
+		want color.ColorLevel
+	}{
+		{"basic", []string{"TERM=xterm"}, color.Level16},
+		{"dumb", []string{"TERM=dumb"}, color.LevelNone},
+		{"256", []string{"TERM=xterm-256color"}, color.Level256},
+		{"COLORTERM", []string{"TERM=xterm-256color", "COLORTERM=truecolor"}, color.LevelTrueColor},
+		{"direct", []string{"TERM=xterm-direct"}, color.LevelTrueColor},
+		{"iTerm", []string{"TERM=xterm", "TERM_PROGRAM=iTerm.app"}, color.LevelTrueColor},
+		{"Windows Terminal", []string{"WT_SESSION=abc"}, color.LevelTrueColor},
+		{"FORCE_COLOR", []string{"TERM=dumb", "FORCE_COLOR=2"}, color.Level256},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command(os.Args[0], "-test.run=^TestLevel$")
+			// Clear any variables from the current environment that affect detection.
+			cmd.Env = append(os.Environ(), "TERM=", "COLORTERM=", "TERM_PROGRAM=", "WT_SESSION=", "FORCE_COLOR=")
+			cmd.Env = append(cmd.Env, tt.env...)
+			cmd.Env = append(cmd.Env, "COLOR_TEST_WANT_LEVEL="+tt.want.String())
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Errorf("subprocess failed: %v\n%s", err, out)
+			}
+		})
+	}
+}
+
+func TestSetLevel(t *testing.T) {
+	color.SetEnabled(true)
+	color.SetLevel(color.Level256)
+	defer color.SetLevel(color.LevelTrueColor)
+	if got := color.Level(); got != color.Level256 {
+		t.Errorf("got level %s, want %s", got, color.Level256)
+	}
+	if got, want := color.RGB(255, 136, 0)("foo"), "\x1b[38;5;208mfoo\x1b[39m"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
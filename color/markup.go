@@ -0,0 +1,86 @@
+package color
+
+import (
+	"fmt"
+	"strings"
+)
+
+// markupTags maps the tags supported by Sprintc to the function that applies them.
+var markupTags = map[string]func(c *Colorer, s string) string{
+	"black":     (*Colorer).Black,
+	"red":       (*Colorer).Red,
+	"green":     (*Colorer).Green,
+	"yellow":    (*Colorer).Yellow,
+	"blue":      (*Colorer).Blue,
+	"magenta":   (*Colorer).Magenta,
+	"cyan":      (*Colorer).Cyan,
+	"white":     (*Colorer).White,
+	"bold":      (*Colorer).Bold,
+	"dim":       (*Colorer).Dim,
+	"italic":    (*Colorer).Italic,
+	"underline": (*Colorer).Underline,
+	"reverse":   (*Colorer).Reverse,
+	"success":   (*Colorer).Success,
+	"warning":   (*Colorer).Warning,
+	"error":     (*Colorer).Error,
+	"info":      (*Colorer).Info,
+}
+
+// Sprintc formats according to a format specifier, like fmt.Sprintf, after replacing
+// markup tags in format with colors and styles. This makes messages with colors more
+// readable than concatenating the results of color functions.
+//
+//	c.Sprintc("deploy <green>succeeded</green> in <bold>%s</bold>", elapsed)
+//
+// The supported tags are the names of the color and style functions in lowercase,
+// for example <red> and <underline>, and the names of the theme styles: <success>,
+// <warning>, <error> and <info>. Tags can be nested, and a tag that is not closed
+// applies to the rest of format. Unknown tags are left as is.
+//
+// If colors are disabled, the tags are removed. Since the tags are replaced before
+// formatting, tags contained in args are not interpreted.
+func (c *Colorer) Sprintc(format string, a ...any) string {
+	s, _ := c.renderMarkup(format, 0, "")
+	return fmt.Sprintf(s, a...)
+}
+
+// Sprintc formats according to a format specifier after replacing
+// markup tags with colors and styles. See Colorer.Sprintc.
+func Sprintc(format string, a ...any) string {
+	return shared.Sprintc(format, a...)
+}
+
+// renderMarkup renders s starting at index i until the closing tag for tag is found.
+// It returns the rendered string and the index after the closing tag.
+func (c *Colorer) renderMarkup(s string, i int, tag string) (string, int) {
+	var sb strings.Builder
+	for i < len(s) {
+		j := strings.IndexByte(s[i:], '<')
+		if j == -1 {
+			sb.WriteString(s[i:])
+			return sb.String(), len(s)
+		}
+		sb.WriteString(s[i : i+j])
+		i += j
+		end := strings.IndexByte(s[i:], '>')
+		if end == -1 {
+			sb.WriteString(s[i:])
+			return sb.String(), len(s)
+		}
+		name := s[i+1 : i+end]
+		next := i + end + 1
+		if tag != "" && name == "/"+tag {
+			return sb.String(), next
+		}
+		if fn, ok := markupTags[name]; ok {
+			inner, n := c.renderMarkup(s, next, name)
+			sb.WriteString(fn(c, inner))
+			i = n
+			continue
+		}
+		// Not a known tag, write it as is.
+		sb.WriteString(s[i:next])
+		i = next
+	}
+	return sb.String(), i
+}
@@ -0,0 +1,53 @@
+package color_test
+
+import (
+	"testing"
+
+	"github.com/TouchBistro/goutils/color"
+)
+
+func TestSprintc(t *testing.T) {
+	var c color.Colorer
+	c.SetLevel(color.LevelTrueColor)
+	tests := []struct {
+		name   string
+		format string
+		args   []any
+		want   string
+	}{
+		{"no tags", "foo bar", nil, "foo bar"},
+		{
+			"tags",
+			"deploy <green>succeeded</green> in <bold>%s</bold>",
+			[]any{"3s"},
+			"deploy \x1b[32msucceeded\x1b[39m in \x1b[1m3s\x1b[22m",
+		},
+		{
+			"nested",
+			"<red>a <blue>b</blue> c</red>",
+			nil,
+			"\x1b[31ma \x1b[34mb\x1b[39m\x1b[31m c\x1b[39m",
+		},
+		{"unclosed", "<red>foo", nil, "\x1b[31mfoo\x1b[39m"},
+		{"unknown tag", "<foo>bar</foo> <3", nil, "<foo>bar</foo> <3"},
+		{"mismatched close", "<red>a</bold>b</red>", nil, "\x1b[31ma</bold>b\x1b[39m"},
+		{"theme", "<error>failed</error>", nil, "\x1b[31mfailed\x1b[39m"},
+		{"args not interpreted", "name: %s", []any{"<red>x</red>"}, "name: <red>x</red>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.Sprintc(tt.format, tt.args...); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSprintcDisabled(t *testing.T) {
+	color.SetEnabled(false)
+	defer color.SetEnabled(true)
+	got := color.Sprintc("deploy <green>succeeded</green> in <bold>%ds</bold>", 3)
+	if want := "deploy succeeded in 3s"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,100 @@
+package color
+
+import "strings"
+
+// Strip returns s with all ANSI escape sequences removed, including SGR sequences
+// used for colors and styles. This is useful for writing colored output to
+// destinations that do not support colors, like log files.
+func Strip(s string) string {
+	i := strings.IndexByte(s, '\x1b')
+	if i == -1 {
+		return s
+	}
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for i != -1 {
+		sb.WriteString(s[:i])
+		s = s[i+escapeLen(s[i:]):]
+		i = strings.IndexByte(s, '\x1b')
+	}
+	sb.WriteString(s)
+	return sb.String()
+}
+
+// StripBytes is like Strip but operates on b. The escape sequences are
+// removed in place, and the returned slice shares the underlying array of b.
+func StripBytes(b []byte) []byte {
+	n := 0
+	for i := 0; i < len(b); {
+		if b[i] != '\x1b' {
+			b[n] = b[i]
+			n++
+			i++
+			continue
+		}
+		i += escapeLen(b[i:])
+	}
+	return b[:n]
+}
+
+// EscapeLen returns the length of the ANSI escape sequence at the start of s,
+// or 0 if s does not start with one. If the sequence is incomplete, len(s) is returned.
+// A CSI sequence that is broken by a byte that can't be part of it ends before that byte.
+// This can be used to skip over escape sequences when processing colored text.
+func EscapeLen(s string) int {
+	if len(s) == 0 || s[0] != '\x1b' {
+		return 0
+	}
+	return escapeLen(s)
+}
+
+// EscapeLenBytes is like EscapeLen but operates on a byte slice.
+func EscapeLenBytes(b []byte) int {
+	if len(b) == 0 || b[0] != '\x1b' {
+		return 0
+	}
+	return escapeLen(b)
+}
+
+// escapeLen returns the length of the escape sequence at the start of s,
+// which must start with ESC. If the sequence is incomplete, the rest of s is consumed.
+func escapeLen[T string | []byte](s T) int {
+	n, _ := scanEscape(s)
+	return n
+}
+
+// scanEscape returns the length of the escape sequence at the start of s, which must
+// start with ESC. If s ends before the sequence is complete, it returns len(s) and false.
+func scanEscape[T string | []byte](s T) (int, bool) {
+	if len(s) < 2 {
+		return len(s), false
+	}
+	switch s[1] {
+	case '[':
+		// CSI: ESC [ parameters intermediates final, where the final byte is in 0x40-0x7e.
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1, true
+			}
+			if s[i] < 0x20 || s[i] > 0x7e {
+				// The sequence is broken, only remove what was part of it
+				// so that the following text, like a newline, is kept.
+				return i, true
+			}
+		}
+		return len(s), false
+	case ']':
+		// OSC: ESC ] ... terminated by BEL or ST (ESC \).
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1, true
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2, true
+			}
+		}
+		return len(s), false
+	}
+	// Two character sequence, for example ESC c.
+	return 2, true
+}
@@ -0,0 +1,70 @@
+package color_test
+
+import (
+	"testing"
+
+	"github.com/TouchBistro/goutils/color"
+)
+
+func TestStrip(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"no escapes", "foo bar", "foo bar"},
+		{"color", "\x1b[31mfoo\x1b[39m bar", "foo bar"},
+		{"multiple params", "\x1b[38;2;255;136;0;1mfoo\x1b[22;39m", "foo"},
+		{"cursor movement", "foo\x1b[2K\x1b[1Gbar", "foobar"},
+		{"OSC hyperlink", "\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\", "link"},
+		{"OSC BEL", "\x1b]0;title\afoo", "foo"},
+		{"two character sequence", "\x1bcfoo", "foo"},
+		{"incomplete sequence", "foo\x1b[31", "foo"},
+		{"broken sequence", "foo\x1b[31\nbar", "foo\nbar"},
+		{"trailing escape", "foo\x1b", "foo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := color.Strip(tt.in); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if got := string(color.StripBytes([]byte(tt.in))); got != tt.want {
+				t.Errorf("StripBytes: got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripColored(t *testing.T) {
+	color.SetEnabled(true)
+	color.SetLevel(color.LevelTrueColor)
+	s := color.Bold(color.Red("foo")) + " " + color.NewStyle(color.FgBlue, color.AttrUnderline).Sprint("bar")
+	if got, want := color.Strip(s), "foo bar"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestEscapeLen(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want int
+	}{
+		{"empty", "", 0},
+		{"no escape", "foo\x1b[31m", 0},
+		{"sgr", "\x1b[31mfoo", 5},
+		{"osc", "\x1b]0;title\afoo", 10},
+		{"incomplete", "\x1b[31", 4},
+		{"broken", "\x1b[31\nfoo", 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := color.EscapeLen(tt.in); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+			if got := color.EscapeLenBytes([]byte(tt.in)); got != tt.want {
+				t.Errorf("EscapeLenBytes: got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package color
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Attribute is a text attribute that can be used to create a Style,
+// such as a foreground color, background color, or text style.
+type Attribute uint32
+
+// The kind of an Attribute is stored in the high byte, and its value in the lower bytes.
+const (
+	attrKindBasic Attribute = iota << 24
+	attrKindFg256
+	attrKindBg256
+	attrKindFgRGB
+	attrKindBgRGB
+
+	attrKindMask  Attribute = 0xff << 24
+	attrValueMask Attribute = 1<<24 - 1
+)
+
+// Foreground colors.
+const (
+	FgBlack   = Attribute(fgBlack)
+	FgRed     = Attribute(fgRed)
+	FgGreen   = Attribute(fgGreen)
+	FgYellow  = Attribute(fgYellow)
+	FgBlue    = Attribute(fgBlue)
+	FgMagenta = Attribute(fgMagenta)
+	FgCyan    = Attribute(fgCyan)
+	FgWhite   = Attribute(fgWhite)
+)
+
+// Background colors.
+const (
+	BgBlack Attribute = iota + 40
+	BgRed
+	BgGreen
+	BgYellow
+	BgBlue
+	BgMagenta
+	BgCyan
+	BgWhite
+)
+
+// Text styles.
+const (
+	AttrBold      = Attribute(styleBold)
+	AttrDim       = Attribute(styleDim)
+	AttrItalic    = Attribute(styleItalic)
+	AttrUnderline = Attribute(styleUnderline)
+	AttrReverse   = Attribute(styleReverse)
+)
+
+// bgReset is the code that resets the background color.
+const bgReset ansiCode = 49
+
+// Fg256 returns an Attribute for the foreground color n from the 256 color palette.
+func Fg256(n uint8) Attribute {
+	return attrKindFg256 | Attribute(n)
+}
+
+// Bg256 returns an Attribute for the background color n from the 256 color palette.
+func Bg256(n uint8) Attribute {
+	return attrKindBg256 | Attribute(n)
+}
+
+// FgRGB returns an Attribute for the 24-bit foreground color specified by r, g and b.
+func FgRGB(r, g, b uint8) Attribute {
+	return attrKindFgRGB | Attribute(r)<<16 | Attribute(g)<<8 | Attribute(b)
+}
+
+// BgRGB returns an Attribute for the 24-bit background color specified by r, g and b.
+func BgRGB(r, g, b uint8) Attribute {
+	return attrKindBgRGB | Attribute(r)<<16 | Attribute(g)<<8 | Attribute(b)
+}
+
+func (a Attribute) rgb() (r, g, b uint8) {
+	return uint8(a >> 16), uint8(a >> 8), uint8(a)
+}
+
+// params returns the SGR parameters for a, downgraded to be supported by level l.
+func (a Attribute) params(l ColorLevel) string {
+	v := a & attrValueMask
+	switch a & attrKindMask {
+	case attrKindFg256, attrKindBg256:
+		bg := a&attrKindMask == attrKindBg256
+		if l == Level16 {
+			return strconv.Itoa(int(nearest16(rgbFrom256(uint8(v))) + bgOffset(bg)))
+		}
+		if bg {
+			return "48;5;" + strconv.Itoa(int(v))
+		}
+		return "38;5;" + strconv.Itoa(int(v))
+	case attrKindFgRGB, attrKindBgRGB:
+		bg := a&attrKindMask == attrKindBgRGB
+		r, g, b := a.rgb()
+		switch l {
+		case Level16:
+			return strconv.Itoa(int(nearest16(r, g, b) + bgOffset(bg)))
+		case Level256:
+			if bg {
+				return "48;5;" + strconv.Itoa(int(rgbTo256(r, g, b)))
+			}
+			return "38;5;" + strconv.Itoa(int(rgbTo256(r, g, b)))
+		}
+		prefix := "38;2;"
+		if bg {
+			prefix = "48;2;"
+		}
+		return prefix + strconv.Itoa(int(r)) + ";" + strconv.Itoa(int(g)) + ";" + strconv.Itoa(int(b))
+	}
+	return strconv.Itoa(int(v))
+}
+
+// reset returns the code that resets a. ok is false if a is not a valid attribute.
+func (a Attribute) reset() (code ansiCode, ok bool) {
+	switch a & attrKindMask {
+	case attrKindFg256, attrKindFgRGB:
+		return fgReset, true
+	case attrKindBg256, attrKindBgRGB:
+		return bgReset, true
+	case attrKindBasic:
+	default:
+		return 0, false
+	}
+	switch c := ansiCode(a); {
+	case c >= 30 && c <= 37, c >= 90 && c <= 97:
+		return fgReset, true
+	case c >= 40 && c <= 47, c >= 100 && c <= 107:
+		return bgReset, true
+	case c == styleBold, c == styleDim:
+		return intensityReset, true
+	case c == styleItalic:
+		return italicReset, true
+	case c == styleUnderline:
+		return underlineReset, true
+	case c == styleReverse:
+		return reverseReset, true
+	}
+	return 0, false
+}
+
+// bgOffset returns the offset from a foreground color code to its background color code.
+func bgOffset(bg bool) ansiCode {
+	if bg {
+		return 10
+	}
+	return 0
+}
+
+// Style is a combination of attributes, such as foreground and background colors
+// and text styles, that can be applied to strings. Styles can be created once and
+// reused, which avoids having to nest color functions.
+//
+//	warn := color.NewStyle(color.FgYellow, color.AttrBold)
+//	fmt.Println(warn.Sprint("careful"))
+//
+// A Style is immutable and can be safely used concurrently.
+// The zero value is a Style with no attributes, which leaves strings unchanged.
+type Style struct {
+	attrs  []Attribute
+	level  ColorLevel // level the escape sequences were created for
+	start  string     // escape sequence to apply the style
+	end    string     // escape sequence to reset the style
+	resets []ansiCode // codes that reset the style
+}
+
+// NewStyle returns a Style with the given attributes. Colors that are not supported
+// by the terminal are downgraded to the closest supported color. Values that are not
+// valid attributes are ignored.
+func NewStyle(attrs ...Attribute) Style {
+	return newStyle(shared.colorLevel(), attrs)
+}
+
+// newStyle returns a Style with the valid attributes in attrs, with escape sequences for level l.
+// attrs is copied, so the caller can modify it afterwards.
+func newStyle(l ColorLevel, attrs []Attribute) Style {
+	var codes []ansiCode
+	valid := make([]Attribute, 0, len(attrs))
+	for _, a := range attrs {
+		code, ok := a.reset()
+		if !ok {
+			continue
+		}
+		valid = append(valid, a)
+		if !containsCode(codes, code) {
+			codes = append(codes, code)
+		}
+	}
+	st := Style{attrs: valid, level: l}
+	if len(valid) == 0 || l == LevelNone {
+		return st
+	}
+	const prefix = "\x1b["
+	var start strings.Builder
+	start.WriteString(prefix)
+	for i, a := range valid {
+		if i > 0 {
+			start.WriteByte(';')
+		}
+		start.WriteString(a.params(l))
+	}
+	start.WriteByte('m')
+
+	var end strings.Builder
+	end.WriteString(prefix)
+	// Reset in the reverse order of application.
+	for i := len(codes) - 1; i >= 0; i-- {
+		if i < len(codes)-1 {
+			end.WriteByte(';')
+		}
+		end.WriteString(strconv.Itoa(int(codes[i])))
+	}
+	end.WriteByte('m')
+	st.start = start.String()
+	st.end = end.String()
+	st.resets = codes
+	return st
+}
+
+// Add returns a new Style with the attributes of st followed by attrs.
+// st is not modified, which allows extending styles conditionally.
+//
+//	st := color.NewStyle(color.FgCyan)
+//	if important {
+//		st = st.Add(color.AttrBold, color.AttrUnderline)
+//	}
+func (st Style) Add(attrs ...Attribute) Style {
+	all := make([]Attribute, 0, len(st.attrs)+len(attrs))
+	all = append(all, st.attrs...)
+	all = append(all, attrs...)
+	return newStyle(st.level, all)
+}
+
+func containsCode(codes []ansiCode, code ansiCode) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Sprint formats using the default formats for its operands, like fmt.Sprint,
+// and applies the style to the resulting string.
+func (st Style) Sprint(a ...any) string {
+	return st.apply(fmt.Sprint(a...))
+}
+
+// Sprintf formats according to a format specifier, like fmt.Sprintf,
+// and applies the style to the resulting string.
+func (st Style) Sprintf(format string, a ...any) string {
+	return st.apply(fmt.Sprintf(format, a...))
+}
+
+func (st Style) apply(s string) string {
+	return st.applyFor(&shared, s)
+}
+
+// applyFor applies the style to s using the enablement and color level of c.
+func (st Style) applyFor(c *Colorer, s string) string {
+	if !c.enabled() {
+		return s
+	}
+	if l := c.colorLevel(); l != st.level {
+		st = newStyle(l, st.attrs)
+	}
+	if st.start == "" {
+		return s
+	}
+	var sb strings.Builder
+	sb.Grow(len(st.start) + len(s) + len(st.end))
+	sb.WriteString(st.start)
+	writeNested(&sb, s, st.start, st.resets)
+	sb.WriteString(st.end)
+	return sb.String()
+}
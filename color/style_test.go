@@ -0,0 +1,145 @@
+package color_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TouchBistro/goutils/color"
+)
+
+func TestStyle(t *testing.T) {
+	color.SetEnabled(true)
+	color.SetLevel(color.LevelTrueColor)
+	tests := []struct {
+		name  string
+		style color.Style
+		in    string
+		want  string
+	}{
+		{"no attributes", color.NewStyle(), "foo", "foo"},
+		{"zero value", color.Style{}, "foo", "foo"},
+		{"color", color.NewStyle(color.FgRed), "foo", "\x1b[31mfoo\x1b[39m"},
+		{
+			"color and styles",
+			color.NewStyle(color.FgRed, color.BgWhite, color.AttrBold, color.AttrUnderline),
+			"foo",
+			"\x1b[31;47;1;4mfoo\x1b[24;22;49;39m",
+		},
+		{"invalid attribute", color.NewStyle(color.FgRed, color.Attribute(99)), "foo", "\x1b[31mfoo\x1b[39m"},
+		{"only invalid attributes", color.NewStyle(color.Attribute(0), color.Attribute(0x7f<<24)), "foo", "foo"},
+		{
+			"shared reset",
+			color.NewStyle(color.AttrBold, color.AttrDim),
+			"foo",
+			"\x1b[1;2mfoo\x1b[22m",
+		},
+		{
+			"restores after nested",
+			color.NewStyle(color.FgCyan, color.AttrBold),
+			"foo " + color.Red("bar") + " baz",
+			"\x1b[36;1mfoo \x1b[31mbar\x1b[39m\x1b[36;1m baz\x1b[22;39m",
+		},
+		{
+			"restores after nested style",
+			color.NewStyle(color.FgCyan, color.AttrUnderline),
+			"foo " + color.NewStyle(color.FgRed, color.AttrBold).Sprint("bar") + " baz",
+			"\x1b[36;4mfoo \x1b[31;1mbar\x1b[22;39m\x1b[36;4m baz\x1b[24;39m",
+		},
+		{
+			"nested style sharing no resets",
+			color.NewStyle(color.AttrUnderline),
+			"foo " + color.NewStyle(color.FgRed).Sprint("bar") + " baz",
+			"\x1b[4mfoo \x1b[31mbar\x1b[39m baz\x1b[24m",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.style.Sprint(tt.in); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStyleSprintf(t *testing.T) {
+	color.SetEnabled(true)
+	color.SetLevel(color.LevelTrueColor)
+	s := color.NewStyle(color.FgGreen)
+	if got, want := s.Sprintf("%d files", 3), "\x1b[32m3 files\x1b[39m"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	color.SetEnabled(false)
+	defer color.SetEnabled(true)
+	if got, want := s.Sprintf("%d files", 3), "3 files"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestColorerStyle(t *testing.T) {
+	st := color.NewStyle(color.FgRGB(255, 136, 0), color.AttrBold)
+	tests := []struct {
+		name    string
+		enabled bool
+		level   color.ColorLevel
+		want    string
+	}{
+		{"truecolor", true, color.LevelTrueColor, "\x1b[38;2;255;136;0;1mfoo\x1b[22;39m"},
+		{"256", true, color.Level256, "\x1b[38;5;208;1mfoo\x1b[22;39m"},
+		{"no color support", true, color.LevelNone, "foo"},
+		{"disabled", false, color.LevelTrueColor, "foo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c color.Colorer
+			c.SetEnabled(tt.enabled)
+			c.SetLevel(tt.level)
+			if got := c.Style(st, "foo"); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewColorer(t *testing.T) {
+	color.SetEnabled(true)
+	color.SetLevel(color.LevelTrueColor)
+	var sb strings.Builder
+	c := color.NewColorer(&sb)
+	if got, want := c.Red("foo"), "foo"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	// Make sure package functions were not affected
+	if got, want := color.Red("foo"), "\x1b[31mfoo\x1b[39m"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestNewStyleCopiesAttributes(t *testing.T) {
+	color.SetEnabled(true)
+	color.SetLevel(color.LevelTrueColor)
+	attrs := []color.Attribute{color.FgRed}
+	st := color.NewStyle(attrs...)
+	attrs[0] = color.FgBlue
+	// Force the escape sequences to be recreated from the attributes.
+	color.SetLevel(color.Level256)
+	if got, want := st.Sprint("foo"), "\x1b[31mfoo\x1b[39m"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestStyleAdd(t *testing.T) {
+	color.SetEnabled(true)
+	color.SetLevel(color.LevelTrueColor)
+	base := color.NewStyle(color.FgCyan)
+	st := base.Add(color.AttrBold, color.AttrUnderline)
+	if got, want := st.Sprint("foo"), "\x1b[36;1;4mfoo\x1b[24;22;39m"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	// Make sure base was not modified
+	if got, want := base.Sprint("foo"), "\x1b[36mfoo\x1b[39m"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := (color.Style{}).Add(color.FgRed).Sprint("foo"), "\x1b[31mfoo\x1b[39m"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
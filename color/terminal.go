@@ -0,0 +1,58 @@
+package color
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+var forceColor = isForceColor()
+
+// stdoutIsTerminal reports whether stdout is a terminal. It is used by the shared Colorer
+// so that colors are not written to pipes and log files by default.
+// It is only checked once, the first time colors are used.
+var stdoutIsTerminal = sync.OnceValue(func() bool {
+	return isTerminal(os.Stdout)
+})
+
+// isForceColor reports whether the FORCE_COLOR or CLICOLOR_FORCE environment variables
+// are set to force colors. A value of "0" or "false" for FORCE_COLOR, or "0" for
+// CLICOLOR_FORCE, is treated the same as being unset.
+func isForceColor() bool {
+	if v := os.Getenv("FORCE_COLOR"); v != "" && v != "0" && v != "false" {
+		return true
+	}
+	v := os.Getenv("CLICOLOR_FORCE")
+	return v != "" && v != "0"
+}
+
+// IsForceColorEnvSet returns true if colors are forced using the FORCE_COLOR
+// or CLICOLOR_FORCE environment variables. See https://force-color.org for more details.
+func IsForceColorEnvSet() bool {
+	return forceColor
+}
+
+// EnabledFor reports whether colors should be used when writing to w.
+// It returns true if NO_COLOR is not set and either w is a terminal or colors
+// are forced using FORCE_COLOR or CLICOLOR_FORCE. If w is a *Writer,
+// it returns true if the Writer preserves colors.
+func EnabledFor(w io.Writer) bool {
+	if cw, ok := w.(*Writer); ok {
+		return !cw.strip
+	}
+	return !noColor && (forceColor || isTerminal(w))
+}
+
+// isTerminal reports whether w is a terminal that supports escape sequences.
+// Only *os.File values can be terminals, any other writer will return false.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok || f == nil {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0 && enableVirtualTerminal(f)
+}
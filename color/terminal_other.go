@@ -0,0 +1,17 @@
+//go:build !windows
+
+package color
+
+import "os"
+
+// enableVirtualTerminal ensures the terminal f can process escape sequences.
+// Terminals on platforms other than windows always can.
+func enableVirtualTerminal(f *os.File) bool {
+	return true
+}
+
+// consoleLevel returns the level of color support of the console.
+// It is only used on windows where the console can be queried.
+func consoleLevel() (ColorLevel, bool) {
+	return 0, false
+}
@@ -0,0 +1,67 @@
+package color_test
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TouchBistro/goutils/color"
+)
+
+func TestEnabledFor(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out.log"))
+	if err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	defer f.Close()
+	tests := []struct {
+		name string
+		w    io.Writer
+	}{
+		{"regular file", f},
+		{"non-file writer", &strings.Builder{}},
+		{"nil file", (*os.File)(nil)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if color.EnabledFor(tt.w) {
+				t.Error("want colors to be disabled for non-terminal")
+			}
+		})
+	}
+}
+
+func TestForceColor(t *testing.T) {
+	// Environment variables are read on initialization, so run the test in a subprocess.
+	if os.Getenv("COLOR_TEST_SUBPROCESS") == "1" {
+		if !color.IsForceColorEnvSet() {
+			t.Fatal("want force color to be set")
+		}
+		if !color.EnabledFor(&strings.Builder{}) {
+			t.Error("want colors to be enabled for non-terminal")
+		}
+		if got, want := color.Red("foo"), "\x1b[31mfoo\x1b[39m"; got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+		return
+	}
+	tests := []struct {
+		name string
+		env  string
+	}{
+		{"FORCE_COLOR", "FORCE_COLOR=1"},
+		{"CLICOLOR_FORCE", "CLICOLOR_FORCE=1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command(os.Args[0], "-test.run=^TestForceColor$")
+			cmd.Env = append(os.Environ(), "COLOR_TEST_SUBPROCESS=1", "NO_COLOR=", tt.env)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Errorf("subprocess failed: %v\n%s", err, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+//go:build windows
+
+package color
+
+import (
+	"os"
+	"sync"
+	"syscall"
+)
+
+// enableVirtualTerminalProcessing is the console mode flag that makes the
+// console process escape sequences. It is supported since Windows 10.
+const enableVirtualTerminalProcessing = 0x0004
+
+var (
+	kernel32           = syscall.NewLazyDLL("kernel32.dll")
+	procSetConsoleMode = kernel32.NewProc("SetConsoleMode")
+
+	// vtEnabled caches the result of enabling virtual terminal processing for each handle,
+	// so it is only attempted once.
+	vtEnabled sync.Map // map[syscall.Handle]bool
+)
+
+// enableVirtualTerminal ensures the console f can process escape sequences by enabling
+// virtual terminal processing. It returns false if it cannot be enabled, for example on
+// versions of Windows before Windows 10, in which case colors should not be used.
+func enableVirtualTerminal(f *os.File) bool {
+	h := syscall.Handle(f.Fd())
+	if ok, loaded := vtEnabled.Load(h); loaded {
+		return ok.(bool)
+	}
+	ok := setVirtualTerminalMode(h)
+	vtEnabled.Store(h, ok)
+	return ok
+}
+
+func setVirtualTerminalMode(h syscall.Handle) bool {
+	var mode uint32
+	if err := syscall.GetConsoleMode(h, &mode); err != nil {
+		return false
+	}
+	if mode&enableVirtualTerminalProcessing != 0 {
+		return true
+	}
+	if err := procSetConsoleMode.Find(); err != nil {
+		return false
+	}
+	r, _, _ := procSetConsoleMode.Call(uintptr(h), uintptr(mode|enableVirtualTerminalProcessing))
+	return r != 0
+}
+
+// consoleLevel returns the level of color support of the console. Consoles that support
+// virtual terminal processing, which was added in Windows 10, also support 24-bit colors.
+func consoleLevel() (ColorLevel, bool) {
+	if isTerminal(os.Stdout) {
+		return LevelTrueColor, true
+	}
+	return 0, false
+}
@@ -0,0 +1,102 @@
+package color
+
+import (
+	"io"
+	"sync/atomic"
+)
+
+// Theme is a palette of styles for semantic kinds of output. It allows keeping
+// consistent semantics across an application while customizing the colors used.
+// A zero value Style in a Theme leaves strings unchanged.
+type Theme struct {
+	Success Style
+	Warning Style
+	Error   Style
+	Info    Style
+}
+
+// DefaultTheme returns the default theme, which uses green for success, yellow for
+// warnings, red for errors and cyan for info.
+func DefaultTheme() Theme {
+	return Theme{
+		Success: NewStyle(FgGreen),
+		Warning: NewStyle(FgYellow),
+		Error:   NewStyle(FgRed),
+		Info:    NewStyle(FgCyan),
+	}
+}
+
+var theme atomic.Pointer[Theme]
+
+func init() {
+	t := DefaultTheme()
+	theme.Store(&t)
+}
+
+// SetTheme sets the theme used by Success, Warning, Error and Info.
+// It is safe to call SetTheme concurrently.
+func SetTheme(t Theme) {
+	theme.Store(&t)
+}
+
+// CurrentTheme returns the theme used by Success, Warning, Error and Info.
+func CurrentTheme() Theme {
+	return *theme.Load()
+}
+
+// Palette provides semantic colors based on the current theme. It allows other packages
+// to color their output consistently without having to determine whether colors should
+// be used themselves. Colorer implements Palette.
+type Palette interface {
+	Success(s string) string
+	Warning(s string) string
+	Error(s string) string
+	Info(s string) string
+}
+
+// For returns a Palette for output written to w. Colors are only used if
+// EnabledFor(w) returns true, which respects NO_COLOR, FORCE_COLOR and whether
+// w is a terminal.
+func For(w io.Writer) Palette {
+	return NewColorer(w)
+}
+
+// Success creates a string styled to indicate success using the current theme.
+func (c *Colorer) Success(s string) string {
+	return c.Style(theme.Load().Success, s)
+}
+
+// Warning creates a string styled to indicate a warning using the current theme.
+func (c *Colorer) Warning(s string) string {
+	return c.Style(theme.Load().Warning, s)
+}
+
+// Error creates a string styled to indicate an error using the current theme.
+func (c *Colorer) Error(s string) string {
+	return c.Style(theme.Load().Error, s)
+}
+
+// Info creates a string styled to indicate information using the current theme.
+func (c *Colorer) Info(s string) string {
+	return c.Style(theme.Load().Info, s)
+}
+
+// Success creates a string styled to indicate success.
+func Success(s string) string {
+	return shared.Success(s)
+}
+
+// Warning creates a string styled to indicate a warning.
+func Warning(s string) string {
+	return shared.Warning(s)
+}
+
+// Error creates a string styled to indicate an error.
+func Error(s string) string {
+	return shared.Error(s)
+}
+
+// Info creates a string styled to indicate information.
+func Info(s string) string {
+	return shared.Info(s)
+}
@@ -0,0 +1,65 @@
+package color_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TouchBistro/goutils/color"
+)
+
+func TestTheme(t *testing.T) {
+	color.SetEnabled(true)
+	color.SetLevel(color.LevelTrueColor)
+	tests := []struct {
+		name string
+		fn   func(string) string
+		want string
+	}{
+		{"Success", color.Success, "\x1b[32mfoo\x1b[39m"},
+		{"Warning", color.Warning, "\x1b[33mfoo\x1b[39m"},
+		{"Error", color.Error, "\x1b[31mfoo\x1b[39m"},
+		{"Info", color.Info, "\x1b[36mfoo\x1b[39m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fn("foo"); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetTheme(t *testing.T) {
+	color.SetEnabled(true)
+	color.SetLevel(color.LevelTrueColor)
+	defer color.SetTheme(color.DefaultTheme())
+	th := color.DefaultTheme()
+	th.Error = color.NewStyle(color.FgMagenta, color.AttrBold)
+	th.Info = color.Style{}
+	color.SetTheme(th)
+	if got, want := color.Error("foo"), "\x1b[35;1mfoo\x1b[22;39m"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := color.Info("foo"), "foo"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := color.Success("foo"), "\x1b[32mfoo\x1b[39m"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestFor(t *testing.T) {
+	color.SetEnabled(true)
+	var sb strings.Builder
+	p := color.For(&sb)
+	if got, want := p.Error("foo"), "foo"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	var c color.Colorer
+	c.SetLevel(color.LevelTrueColor)
+	var cp color.Palette = &c
+	if got, want := cp.Success("foo"), "\x1b[32mfoo\x1b[39m"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,121 @@
+package color
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// Width returns the number of columns s occupies when displayed in a terminal.
+// Escape sequences are ignored, wide runes like CJK characters and most emoji
+// count as two columns, and zero width runes like combining marks count as zero.
+// This is useful for aligning colored text.
+func Width(s string) int {
+	w := 0
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' {
+			i += escapeLen(s[i:])
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		w += RuneWidth(r)
+		i += size
+	}
+	return w
+}
+
+// WidthBytes is like Width but operates on a byte slice.
+func WidthBytes(b []byte) int {
+	w := 0
+	for i := 0; i < len(b); {
+		if b[i] == '\x1b' {
+			i += escapeLen(b[i:])
+			continue
+		}
+		r, size := utf8.DecodeRune(b[i:])
+		w += RuneWidth(r)
+		i += size
+	}
+	return w
+}
+
+// RuneWidth returns the number of columns r occupies when displayed in a terminal.
+// See Width for details.
+func RuneWidth(r rune) int {
+	switch {
+	case r < 0x20 || r == 0x7f:
+		// Control characters are not printed.
+		return 0
+	case r < 0x300:
+		// Fast path for ASCII and Latin.
+		return 1
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) || r == 0x200b:
+		return 0
+	case unicode.Is(wideRunes, r):
+		return 2
+	}
+	return 1
+}
+
+// wideRunes contains the runes that are displayed using two columns. It is based on
+// the wide and fullwidth runes from Unicode Standard Annex #11, East Asian Width.
+var wideRunes = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x1100, Hi: 0x115f, Stride: 1}, // Hangul Jamo
+		{Lo: 0x231a, Hi: 0x231b, Stride: 1},
+		{Lo: 0x2329, Hi: 0x232a, Stride: 1},
+		{Lo: 0x23e9, Hi: 0x23ec, Stride: 1},
+		{Lo: 0x23f0, Hi: 0x23f3, Stride: 3},
+		{Lo: 0x25fd, Hi: 0x25fe, Stride: 1},
+		{Lo: 0x2614, Hi: 0x2615, Stride: 1},
+		{Lo: 0x2648, Hi: 0x2653, Stride: 1},
+		{Lo: 0x267f, Hi: 0x2693, Stride: 20},
+		{Lo: 0x26a1, Hi: 0x26a1, Stride: 1},
+		{Lo: 0x26aa, Hi: 0x26ab, Stride: 1},
+		{Lo: 0x26bd, Hi: 0x26be, Stride: 1},
+		{Lo: 0x26c4, Hi: 0x26c5, Stride: 1},
+		{Lo: 0x26ce, Hi: 0x26d4, Stride: 6},
+		{Lo: 0x26ea, Hi: 0x26ea, Stride: 1},
+		{Lo: 0x26f2, Hi: 0x26f3, Stride: 1},
+		{Lo: 0x26f5, Hi: 0x26fa, Stride: 5},
+		{Lo: 0x26fd, Hi: 0x26fd, Stride: 1},
+		{Lo: 0x2705, Hi: 0x2705, Stride: 1},
+		{Lo: 0x270a, Hi: 0x270b, Stride: 1},
+		{Lo: 0x2728, Hi: 0x2728, Stride: 1},
+		{Lo: 0x274c, Hi: 0x274e, Stride: 2},
+		{Lo: 0x2753, Hi: 0x2755, Stride: 1},
+		{Lo: 0x2757, Hi: 0x2757, Stride: 1},
+		{Lo: 0x2795, Hi: 0x2797, Stride: 1},
+		{Lo: 0x27b0, Hi: 0x27bf, Stride: 15},
+		{Lo: 0x2b1b, Hi: 0x2b1c, Stride: 1},
+		{Lo: 0x2b50, Hi: 0x2b55, Stride: 5},
+		{Lo: 0x2e80, Hi: 0x303e, Stride: 1}, // CJK Radicals, Kangxi, CJK Symbols and Punctuation
+		{Lo: 0x3041, Hi: 0x33ff, Stride: 1}, // Hiragana, Katakana, Bopomofo, Hangul Compatibility Jamo, CJK Compatibility
+		{Lo: 0x3400, Hi: 0x4dbf, Stride: 1}, // CJK Unified Ideographs Extension A
+		{Lo: 0x4e00, Hi: 0x9fff, Stride: 1}, // CJK Unified Ideographs
+		{Lo: 0xa000, Hi: 0xa4cf, Stride: 1}, // Yi
+		{Lo: 0xa960, Hi: 0xa97f, Stride: 1}, // Hangul Jamo Extended-A
+		{Lo: 0xac00, Hi: 0xd7a3, Stride: 1}, // Hangul Syllables
+		{Lo: 0xf900, Hi: 0xfaff, Stride: 1}, // CJK Compatibility Ideographs
+		{Lo: 0xfe10, Hi: 0xfe19, Stride: 1}, // Vertical Forms
+		{Lo: 0xfe30, Hi: 0xfe6f, Stride: 1}, // CJK Compatibility Forms, Small Form Variants
+		{Lo: 0xff00, Hi: 0xff60, Stride: 1}, // Fullwidth Forms
+		{Lo: 0xffe0, Hi: 0xffe6, Stride: 1},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x16fe0, Hi: 0x16fe4, Stride: 1},
+		{Lo: 0x17000, Hi: 0x18aff, Stride: 1}, // Tangut
+		{Lo: 0x1b000, Hi: 0x1b2ff, Stride: 1}, // Kana Supplement, Nushu
+		{Lo: 0x1f004, Hi: 0x1f004, Stride: 1},
+		{Lo: 0x1f0cf, Hi: 0x1f0cf, Stride: 1},
+		{Lo: 0x1f18e, Hi: 0x1f18e, Stride: 1},
+		{Lo: 0x1f191, Hi: 0x1f19a, Stride: 1},
+		{Lo: 0x1f200, Hi: 0x1f2ff, Stride: 1}, // Enclosed Ideographic Supplement
+		{Lo: 0x1f300, Hi: 0x1f64f, Stride: 1}, // Miscellaneous Symbols and Pictographs, Emoticons
+		{Lo: 0x1f680, Hi: 0x1f6ff, Stride: 1}, // Transport and Map Symbols
+		{Lo: 0x1f7e0, Hi: 0x1f7eb, Stride: 1},
+		{Lo: 0x1f900, Hi: 0x1f9ff, Stride: 1}, // Supplemental Symbols and Pictographs
+		{Lo: 0x1fa70, Hi: 0x1faff, Stride: 1}, // Symbols and Pictographs Extended-A
+		{Lo: 0x20000, Hi: 0x2fffd, Stride: 1}, // CJK Unified Ideographs Extension B-F
+		{Lo: 0x30000, Hi: 0x3fffd, Stride: 1}, // CJK Unified Ideographs Extension G
+	},
+}
@@ -0,0 +1,38 @@
+package color_test
+
+import (
+	"testing"
+
+	"github.com/TouchBistro/goutils/color"
+)
+
+func TestWidth(t *testing.T) {
+	color.SetEnabled(true)
+	tests := []struct {
+		name string
+		in   string
+		want int
+	}{
+		{"empty", "", 0},
+		{"ASCII", "foo bar", 7},
+		{"colored", color.Red("foo") + " " + color.Bold("bar"), 7},
+		{"accents", "héllo", 5},
+		{"combining mark", "he\u0301llo", 5},
+		{"CJK", "日本語", 6},
+		{"fullwidth", "ＡＢ", 4},
+		{"emoji", "ok ✅ 🚀", 8},
+		{"zero width joiner", "a\u200db", 2},
+		{"control characters", "a\tb\x00", 2},
+		{"mixed", "\x1b[31m日本\x1b[39m foo", 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := color.Width(tt.in); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+			if got := color.WidthBytes([]byte(tt.in)); got != tt.want {
+				t.Errorf("WidthBytes: got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
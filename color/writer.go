@@ -0,0 +1,122 @@
+package color
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// Writer is an io.Writer that removes ANSI escape sequences from data written to it
+// if the underlying writer does not support colors. This allows the same colored
+// output to be written to multiple destinations, like a terminal and a log file.
+//
+//	w := io.MultiWriter(color.NewWriter(os.Stderr), color.NewWriter(logFile))
+//
+// Escape sequences split across multiple writes are handled correctly, as long as
+// they are not longer than a few kilobytes. Call Flush once all output has been written.
+// A Writer is safe for concurrent use.
+type Writer struct {
+	mu      sync.Mutex
+	w       io.Writer
+	strip   bool
+	pending []byte // incomplete escape sequence from the previous write
+}
+
+// maxPending is the maximum length of an incomplete escape sequence that is kept until the
+// next write. Longer sequences are assumed to be stray escape characters that will never be
+// terminated, so only their start is removed and the data after it is written as plain text,
+// so that all following output isn't held back.
+const maxPending = 4096
+
+// NewWriter returns a Writer that writes to w.
+// Escape sequences are removed if EnabledFor(w) returns false.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w, strip: !EnabledFor(w)}
+}
+
+// Write writes p to the underlying writer, removing escape
+// sequences if the underlying writer does not support colors.
+func (w *Writer) Write(p []byte) (int, error) {
+	if !w.strip {
+		return w.w.Write(p)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	// Write as much as possible directly from p, only use a buffer if there
+	// is an escape sequence or pending data from the previous write.
+	if len(w.pending) == 0 && bytes.IndexByte(p, '\x1b') == -1 {
+		return w.w.Write(p)
+	}
+	buf := make([]byte, 0, len(w.pending)+len(p))
+	buf = append(buf, w.pending...)
+	buf = append(buf, p...)
+	w.pending = w.pending[:0]
+	buf = w.stripEscapes(buf, false)
+	if _, err := w.w.Write(buf); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush writes any data that was held back because it follows the start of an escape
+// sequence that has not been terminated yet. The start of the sequence is dropped and
+// the data after it is written as plain text. Flush should be called once all output
+// has been written, since otherwise the end of an incomplete sequence is lost.
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.pending) == 0 {
+		return nil
+	}
+	buf := w.stripEscapes(w.pending, true)
+	w.pending = w.pending[:0]
+	if len(buf) == 0 {
+		return nil
+	}
+	_, err := w.w.Write(buf)
+	return err
+}
+
+// stripEscapes removes escape sequences from buf in place and returns the remaining data.
+// An incomplete sequence at the end of buf is saved in w.pending to be removed on the next
+// write, unless flush is true or it is longer than maxPending, in which case it is broken
+// and only its start is removed.
+func (w *Writer) stripEscapes(buf []byte, flush bool) []byte {
+	n := 0
+	for i := 0; i < len(buf); {
+		if buf[i] != '\x1b' {
+			buf[n] = buf[i]
+			n++
+			i++
+			continue
+		}
+		l, ok := scanEscape(buf[i:])
+		if !ok && (flush || l > maxPending) {
+			i += escapeStartLen(buf[i:])
+			continue
+		}
+		if !ok {
+			// Save the incomplete sequence so it can be removed on the next write.
+			w.pending = append(w.pending, buf[i:]...)
+			break
+		}
+		i += l
+	}
+	return buf[:n]
+}
+
+// escapeStartLen returns the length of the start of the incomplete escape sequence at
+// the start of b, which is ESC and the byte that follows it, as well as the parameters
+// of a CSI sequence.
+func escapeStartLen(b []byte) int {
+	if len(b) < 2 {
+		return len(b)
+	}
+	i := 2
+	if b[1] == '[' {
+		for i < len(b) && b[i] >= 0x20 && b[i] <= 0x3f {
+			i++
+		}
+	}
+	return i
+}
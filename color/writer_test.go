@@ -0,0 +1,56 @@
+package color_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TouchBistro/goutils/color"
+)
+
+func TestWriterStrips(t *testing.T) {
+	tests := []struct {
+		name   string
+		writes []string
+		want   string
+	}{
+		{"no escapes", []string{"foo ", "bar"}, "foo bar"},
+		{"single write", []string{"\x1b[31mfoo\x1b[39m bar"}, "foo bar"},
+		{"split sequence", []string{"\x1b[3", "1mfoo\x1b", "[39m bar"}, "foo bar"},
+		{"split OSC", []string{"\x1b]8;;https://example.com\x1b", "\\link"}, "link"},
+		{"broken CSI", []string{"foo\x1b[3", "\nbar"}, "foo\nbar"},
+		{
+			"unterminated OSC",
+			[]string{"foo \x1b]", strings.Repeat("x", 5000), "\x1b[31mbar\x1b[39m"},
+			"foo " + strings.Repeat("x", 5000) + "bar",
+		},
+		{
+			"unterminated OSC flushed",
+			[]string{"foo \x1b]", strings.Repeat("x", 5000)},
+			"foo " + strings.Repeat("x", 5000),
+		},
+		{"trailing ESC", []string{"foo\x1b"}, "foo"},
+		{"trailing CSI", []string{"foo\x1b[31;"}, "foo"},
+		{"trailing OSC", []string{"foo\x1b]8;;bar"}, "foo8;;bar"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			w := color.NewWriter(&sb)
+			for _, s := range tt.writes {
+				n, err := w.Write([]byte(s))
+				if err != nil {
+					t.Fatalf("want nil error, got %v", err)
+				}
+				if n != len(s) {
+					t.Errorf("got n %d, want %d", n, len(s))
+				}
+			}
+			if err := w.Flush(); err != nil {
+				t.Fatalf("want nil error, got %v", err)
+			}
+			if got := sb.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
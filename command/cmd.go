@@ -0,0 +1,25 @@
+package command
+
+import "context"
+
+// Cmd describes a single invocation of a program, which allows it to be passed around
+// and run later, for example by RunWithRetry.
+type Cmd struct {
+	// Name is the name or path of the program to run.
+	Name string
+	// Args are the arguments to pass to the program.
+	Args []string
+	// Opts configures how the program is run, see New.
+	Opts []Option
+}
+
+// Run runs the command. See Command.Exec for details.
+func (c *Cmd) Run(ctx context.Context) error {
+	return New(c.Opts...).exec(ctx, "command.Cmd.Run", c.Name, c.Args)
+}
+
+// String returns the command line of the command, quoted so that it can be copied
+// and run in the platform shell, see Quote.
+func (c *Cmd) String() string {
+	return Quote(append([]string{c.Name}, c.Args...)...)
+}
@@ -0,0 +1,27 @@
+package command_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/TouchBistro/goutils/command"
+)
+
+func TestCmdRun(t *testing.T) {
+	buf := &bytes.Buffer{}
+	cmd := &command.Cmd{Name: "echo", Args: []string{"Hello", "world"}, Opts: []command.Option{command.WithStdout(buf)}}
+	if err := cmd.Run(context.Background()); err != nil {
+		t.Errorf("want nil error, got %v", err)
+	}
+	if want := "Hello world\n"; buf.String() != want {
+		t.Errorf("got stdout %q, want %q", buf.String(), want)
+	}
+}
+
+func TestCmdString(t *testing.T) {
+	cmd := &command.Cmd{Name: "git", Args: []string{"commit", "-m", "a message"}}
+	if got, want := cmd.String(), "git commit -m 'a message'"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -4,11 +4,15 @@
 package command
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
-	"strings"
+	"time"
+
+	"github.com/TouchBistro/goutils/errors"
 )
 
 // Exists checks if the command exists on the system by seeing if it's in the user's PATH.
@@ -20,11 +24,27 @@ func Exists(command string) bool {
 // Command manages the configuration of a command
 // that will be run in a child process.
 type Command struct {
-	stdin  io.Reader
-	stdout io.Writer
-	stderr io.Writer
-	env    map[string]string
-	dir    string
+	stdin    io.Reader
+	stdout   io.Writer
+	stderr   io.Writer
+	env      map[string]string
+	extraEnv map[string]string
+	dir      string
+
+	captureStdout io.Writer
+	captureStderr io.Writer
+	processGroup  bool
+	pty           bool
+	prefix        string
+	ready         *ReadyCheck // only used by Start
+	shell         Shell       // only used by RunShell
+	timeout       time.Duration
+	budget        *Budget
+	lookup        func(string) (string, bool) // used to expand variables if set
+	keepArgs      bool                        // don't expand variables in arguments, set by RunShell
+	dryRun        io.Writer
+	lineFunc      func(Stream, string)
+	prepare       []func(*exec.Cmd) // called before the process is started
 }
 
 // New creates a command instance from the given options.
@@ -63,6 +83,26 @@ func WithStderr(stderr io.Writer) Option {
 	}
 }
 
+// CaptureStdout collects everything the command writes to stdout in buf.
+// Unlike WithStdout, it can be combined with WithStdout to both stream the
+// output live and inspect it after the command has completed.
+//
+// Note that if stdout is also set to a file like os.Stdout, the command writes to
+// a pipe instead of the file, so it cannot detect if it is writing to a terminal.
+func CaptureStdout(buf *bytes.Buffer) Option {
+	return func(c *Command) {
+		c.captureStdout = buf
+	}
+}
+
+// CaptureStderr collects everything the command writes to stderr in buf.
+// See CaptureStdout for details.
+func CaptureStderr(buf *bytes.Buffer) Option {
+	return func(c *Command) {
+		c.captureStderr = buf
+	}
+}
+
 // WithEnv sets the environment variables for the process
 // the command will be run in.
 func WithEnv(env map[string]string) Option {
@@ -71,6 +111,16 @@ func WithEnv(env map[string]string) Option {
 	}
 }
 
+// WithExtraEnv adds environment variables to the process the command will be run in.
+// Unlike WithEnv, the variables are added to the environment instead of replacing it.
+// The environment is the one set by WithEnv, or the environment of the current process
+// if WithEnv is not used. Variables in env take precedence over existing ones.
+func WithExtraEnv(env map[string]string) Option {
+	return func(c *Command) {
+		c.extraEnv = env
+	}
+}
+
 // WithDir sets the directory the command should be run in.
 func WithDir(dir string) Option {
 	return func(c *Command) {
@@ -78,39 +128,216 @@ func WithDir(dir string) Option {
 	}
 }
 
+// WithProcessGroup runs the command in a new process group, so that any processes
+// started by the command can be terminated along with it. If the context passed to
+// Exec becomes done, the entire process group is killed instead of just the command.
+// This prevents child processes from lingering, for example if a script was cancelled.
+//
+// On Unix the process group is created using setpgid. On Windows a job object is used.
+// Note that on Unix, a command in a separate process group cannot read from the
+// terminal, so this should not be used for interactive commands.
+func WithProcessGroup() Option {
+	return func(c *Command) {
+		c.processGroup = true
+	}
+}
+
 // Exec executes the named program with the given arguments.
 //
 // The provided context can be used to kill the process if the context
-// becomes done before the program completes on its own.
+// becomes done before the program completes on its own. In that case, the returned
+// error also wraps the context's error. See WithProcessGroup for also killing any
+// processes started by the program, and WithTimeout and WithBudget for limiting how
+// long the program can run.
+//
+// If the program fails to run or exits with a non-zero status, the returned error is an
+// *errors.Error with the kind KindFailed that wraps an *Error, which contains the exit code
+// and the end of what the program wrote to stderr. See Error for details.
+//
+// If dry run mode is enabled, the program is not run, see WithDryRun.
 func (c *Command) Exec(ctx context.Context, name string, args ...string) error {
+	return c.exec(ctx, "command.Command.Exec", name, args)
+}
+
+// exec runs the named program and waits for it to complete, see Exec for details.
+// op is the operation used for the returned error, which is the exported function
+// that was called.
+func (c *Command) exec(ctx context.Context, op errors.Op, name string, args []string) error {
+	r, err := c.start(ctx, op, name, args)
+	if err != nil || r == nil {
+		return err
+	}
+	return r.wait()
+}
+
+// run is a process started by Command.start.
+type run struct {
+	ctx     context.Context
+	op      errors.Op
+	cmd     *exec.Cmd
+	name    string
+	args    []string
+	stderr  *tailBuffer
+	pty     *pty
+	pg      *processGroup // nil if WithProcessGroup was not used
+	cleanup []func()      // called in reverse order once the process has exited
+}
+
+// start starts the named program with the given arguments, see Exec for details.
+// If dry run mode is enabled, the program is not run and the returned run is nil.
+// op is the operation used for errors, see exec.
+func (c *Command) start(ctx context.Context, op errors.Op, name string, args []string) (*run, error) {
+	if c.lookup != nil {
+		cc, expandedArgs, err := c.expandVariables(args)
+		if err != nil {
+			return nil, wrapError(op, &Error{Name: name, Args: args, ExitCode: -1, Err: err})
+		}
+		c, args = cc, expandedArgs
+	}
+	if w := c.dryRunWriter(); w != nil {
+		if _, err := io.WriteString(w, c.commandLine(name, args)+"\n"); err != nil {
+			return nil, wrapError(op, &Error{Name: name, Args: args, ExitCode: -1, Err: err})
+		}
+		return nil, nil
+	}
+	ctx, done, err := c.withDeadline(ctx)
+	if err != nil {
+		return nil, wrapError(op, &Error{Name: name, Args: args, ExitCode: -1, Err: err})
+	}
 	cmd := exec.CommandContext(ctx, name, args...)
 	if c.stdin != nil {
 		cmd.Stdin = c.stdin
 	}
-	if c.stdout != nil {
-		cmd.Stdout = c.stdout
+	preparePlatform(cmd)
+	// Capture the end of stderr so it can be included in the error. Files are passed to the
+	// process directly instead, so that it can still detect if it is writing to a terminal.
+	r := &run{ctx: ctx, op: op, cmd: cmd, name: name, args: args, stderr: &tailBuffer{max: maxStderrLen}}
+	outLines, errLines, flushLines := c.lineWriters()
+	stdoutW, stderrW, flushPrefix := c.prefixWriters()
+	r.cleanup = append(r.cleanup, done, flushLines, flushPrefix)
+	if f, ok := stderrW.(*os.File); ok && c.captureStderr == nil && errLines == nil {
+		cmd.Stderr = f
+	} else {
+		cmd.Stderr = multiWriter(stderrW, c.captureStderr, errLines, r.stderr)
 	}
-	if c.stderr != nil {
-		cmd.Stderr = c.stderr
-	}
-	if c.env != nil {
-		for k, v := range c.env {
-			cmd.Env = append(cmd.Env, k+"="+v)
-		}
+	if w := multiWriter(stdoutW, c.captureStdout, outLines); w != nil {
+		cmd.Stdout = w
 	}
+	cmd.Env = c.environ()
 	if c.dir != "" {
 		cmd.Dir = c.dir
 	}
+	// Don't wait forever for output to be closed if the process started a background
+	// process that inherited stdout or stderr and outlives it.
+	cmd.WaitDelay = waitDelay
+
+	if c.pty {
+		// Everything the process writes goes to the PTY, so it can only be relayed as stdout.
+		p, err := newPTY(cmd, c.stdin, multiWriter(stdoutW, c.captureStdout, outLines, r.stderr))
+		if err != nil {
+			r.finish()
+			return nil, r.error(err)
+		}
+		r.pty = p
+		r.cleanup = append(r.cleanup, p.close)
+	}
+	for _, fn := range c.prepare {
+		fn(cmd)
+	}
+	if c.processGroup {
+		r.pg = &processGroup{}
+		r.pg.prepare(cmd)
+		r.cleanup = append(r.cleanup, r.pg.close)
+	}
+	if err := cmd.Start(); err != nil {
+		r.finish()
+		return nil, r.error(err)
+	}
+	if r.pg != nil {
+		r.pg.started(cmd)
+	}
+	if r.pty != nil {
+		r.pty.started()
+	}
+	return r, nil
+}
 
-	if err := cmd.Run(); err != nil {
-		argsStr := strings.Join(args, " ")
-		return fmt.Errorf("command: failed to run '%s %s': %w", name, argsStr, err)
+// wait waits for the process to exit and releases any resources used by it.
+func (r *run) wait() error {
+	err := r.cmd.Wait()
+	if r.pty != nil {
+		r.pty.wait()
+	}
+	r.finish()
+	if errors.Is(err, exec.ErrWaitDelay) {
+		// The process exited successfully, only its output was not closed.
+		err = nil
+	}
+	if err != nil {
+		return r.error(err)
 	}
 	return nil
 }
 
+// finish calls the cleanup functions of r.
+func (r *run) finish() {
+	for i := len(r.cleanup) - 1; i >= 0; i-- {
+		r.cleanup[i]()
+	}
+}
+
+// error returns the error for the process failing to run because of err.
+func (r *run) error(err error) error {
+	if ctxErr := r.ctx.Err(); ctxErr != nil {
+		// Make it possible to check if the command was killed because of the context,
+		// and why, for example because of WithBudget.
+		if cause := context.Cause(r.ctx); cause != ctxErr {
+			err = fmt.Errorf("%w (%w: %w)", err, cause, ctxErr)
+		} else {
+			err = fmt.Errorf("%w (%w)", err, ctxErr)
+		}
+	}
+	exitCode := -1
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		exitCode = exitErr.ExitCode()
+	}
+	return wrapError(r.op, &Error{Name: r.name, Args: r.args, ExitCode: exitCode, Stderr: string(r.stderr.buf), Err: err})
+}
+
+// waitDelay is how long to wait for the output of a process to be closed after it exits.
+const waitDelay = time.Second
+
+// environ returns the environment for the process, or nil if the
+// environment of the current process should be used.
+func (c *Command) environ() []string {
+	if c.env == nil && c.extraEnv == nil {
+		return nil
+	}
+	var env []string
+	if c.env == nil {
+		env = os.Environ()
+	}
+	for k, v := range c.env {
+		env = append(env, k+"="+v)
+	}
+	// exec.Cmd uses the last value if a variable is present multiple times,
+	// so adding the extra variables after overrides existing ones.
+	for k, v := range c.extraEnv {
+		env = append(env, k+"="+v)
+	}
+	return env
+}
+
 // Exec executes the named program with the given arguments.
 // This is a shorthand for when the default command options wish to be used.
 func Exec(ctx context.Context, name string, args ...string) error {
 	return New().Exec(ctx, name, args...)
 }
+
+// Run runs the named program with the given arguments, configured using opts.
+// It is a shorthand for creating a Command with New and calling Exec
+// when a context is not needed. See Exec for details.
+func Run(name string, args []string, opts ...Option) error {
+	return New(opts...).exec(context.Background(), "command.Run", name, args)
+}
@@ -3,8 +3,11 @@ package command_test
 import (
 	"bytes"
 	"context"
+	"os"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/TouchBistro/goutils/command"
 )
@@ -89,3 +92,74 @@ func TestExecError(t *testing.T) {
 		t.Error("want non-nil error, got nil")
 	}
 }
+
+func TestExecWithExtraEnv(t *testing.T) {
+	t.Setenv("GOUTILS_INHERITED", "inherited")
+	t.Setenv("GOUTILS_OVERRIDDEN", "old")
+	buf := &bytes.Buffer{}
+	cmd := command.New(
+		command.WithStdout(buf),
+		command.WithExtraEnv(map[string]string{
+			"FOO":                "BAR",
+			"GOUTILS_OVERRIDDEN": "new",
+		}),
+	)
+	err := cmd.Exec(context.Background(), "sh", "-c", "echo $FOO $GOUTILS_INHERITED $GOUTILS_OVERRIDDEN")
+	if err != nil {
+		t.Errorf("want nil error, got %v", err)
+	}
+	want := "BAR inherited new\n"
+	if buf.String() != want {
+		t.Errorf("got stdout %q, want %q", buf.String(), want)
+	}
+}
+
+func TestExecWithEnvAndExtraEnv(t *testing.T) {
+	t.Setenv("GOUTILS_INHERITED", "inherited")
+	buf := &bytes.Buffer{}
+	cmd := command.New(
+		command.WithStdout(buf),
+		command.WithEnv(map[string]string{"FOO": "BAR", "BAZ": "old"}),
+		command.WithExtraEnv(map[string]string{"BAZ": "new"}),
+	)
+	// Use an absolute path since PATH is not set.
+	err := cmd.Exec(context.Background(), "/bin/sh", "-c", "echo $FOO $BAZ $GOUTILS_INHERITED")
+	if err != nil {
+		t.Errorf("want nil error, got %v", err)
+	}
+	want := "BAR new\n"
+	if buf.String() != want {
+		t.Errorf("got stdout %q, want %q", buf.String(), want)
+	}
+}
+
+func TestRun(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := command.Run("echo", []string{"Hello", "world"}, command.WithStdout(buf))
+	if err != nil {
+		t.Errorf("want nil error, got %v", err)
+	}
+	if want := "Hello world\n"; buf.String() != want {
+		t.Errorf("got stdout %q, want %q", buf.String(), want)
+	}
+}
+
+func TestExecBackgroundProcess(t *testing.T) {
+	// The background process inherits stdout and stderr and outlives the shell,
+	// Exec must not wait for it to exit.
+	buf := &bytes.Buffer{}
+	cmd := command.New(command.WithStdout(buf))
+	start := time.Now()
+	err := cmd.Exec(context.Background(), "sh", "-c", "sleep 30 & echo $!")
+	if err != nil {
+		t.Errorf("want nil error, got %v", err)
+	}
+	if pid, err := strconv.Atoi(strings.TrimSpace(buf.String())); err == nil {
+		if p, err := os.FindProcess(pid); err == nil {
+			p.Kill()
+		}
+	}
+	if d := time.Since(start); d > 10*time.Second {
+		t.Errorf("Exec took %s, want it to return after the shell exits", d)
+	}
+}
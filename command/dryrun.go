@@ -0,0 +1,85 @@
+package command
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/TouchBistro/goutils/text"
+)
+
+// dryRun holds the writer set by SetDryRun, or nil if dry run is disabled.
+var dryRun atomic.Pointer[io.Writer]
+
+// SetDryRun enables dry run mode for all commands by setting w as the writer that command
+// lines are written to instead of being run. Passing nil disables dry run mode.
+// See WithDryRun for details. It is safe to call SetDryRun concurrently.
+func SetDryRun(w io.Writer) {
+	if w == nil {
+		dryRun.Store(nil)
+		return
+	}
+	dryRun.Store(&w)
+}
+
+// WithDryRun enables dry run mode for the command. Instead of running the program, Exec
+// writes the command line to w and returns nil. This allows users to preview what would be
+// run, for example before running destructive operations.
+//
+// The command line is fully resolved, variables expanded by WithExpandVariables are
+// replaced and the directory and environment variables set on the command are included.
+// It is quoted so that it can be copied and run in a shell, for example:
+//
+//	cd /tmp/repo && GIT_AUTHOR_NAME='Jane Doe' git commit -m 'a message'
+//
+// WithDryRun takes precedence over SetDryRun. Passing nil has no effect.
+func WithDryRun(w io.Writer) Option {
+	return func(c *Command) {
+		c.dryRun = w
+	}
+}
+
+// dryRunWriter returns the writer to use for dry run mode, or nil if it is disabled.
+func (c *Command) dryRunWriter() io.Writer {
+	if c.dryRun != nil {
+		return c.dryRun
+	}
+	if w := dryRun.Load(); w != nil {
+		return *w
+	}
+	return nil
+}
+
+// commandLine returns the shell command line that is equivalent to running name with args.
+func (c *Command) commandLine(name string, args []string) string {
+	var sb strings.Builder
+	if c.dir != "" {
+		sb.WriteString("cd ")
+		sb.WriteString(text.ShellQuote([]string{c.dir}))
+		sb.WriteString(" && ")
+	}
+	if c.env != nil {
+		// The environment is replaced, not added to.
+		sb.WriteString("env -i ")
+		writeEnv(&sb, c.env)
+	}
+	writeEnv(&sb, c.extraEnv)
+	sb.WriteString(text.ShellQuote(append([]string{name}, args...)))
+	return sb.String()
+}
+
+// writeEnv writes env as shell variable assignments in sorted order, each followed by a space.
+func writeEnv(sb *strings.Builder, env map[string]string) {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		sb.WriteString(k)
+		sb.WriteByte('=')
+		sb.WriteString(text.ShellQuote([]string{env[k]}))
+		sb.WriteByte(' ')
+	}
+}
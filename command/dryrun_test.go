@@ -0,0 +1,86 @@
+package command_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TouchBistro/goutils/command"
+)
+
+func TestWithDryRun(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []command.Option
+		args []string
+		want string
+	}{
+		{"plain", nil, []string{"git", "commit", "-m", "a message"}, "git commit -m 'a message'\n"},
+		{"dir", []command.Option{command.WithDir("/tmp/my repo")}, []string{"ls"}, "cd '/tmp/my repo' && ls\n"},
+		{
+			"extra env",
+			[]command.Option{command.WithExtraEnv(map[string]string{"B": "two words", "A": "1"})},
+			[]string{"make"},
+			"A=1 B='two words' make\n",
+		},
+		{"env", []command.Option{command.WithEnv(map[string]string{"PATH": "/bin"})}, []string{"ls"}, "env -i PATH=/bin ls\n"},
+		{
+			"expanded",
+			[]command.Option{command.WithExpandVariables(testLookup)},
+			[]string{"echo", "hello ${NAME}"},
+			"echo 'hello world'\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			cmd := command.New(append(tt.opts, command.WithDryRun(buf))...)
+			if err := cmd.Exec(context.Background(), tt.args[0], tt.args[1:]...); err != nil {
+				t.Fatalf("want nil error, got %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("got %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWithDryRunDoesNotRun(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	buf := &bytes.Buffer{}
+	cmd := command.New(command.WithDryRun(buf))
+	if err := cmd.Exec(context.Background(), "touch", file); err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	if _, err := os.Stat(file); !os.IsNotExist(err) {
+		t.Errorf("got stat error %v, want the file not to exist", err)
+	}
+}
+
+func TestSetDryRun(t *testing.T) {
+	buf := &bytes.Buffer{}
+	command.SetDryRun(buf)
+	t.Cleanup(func() { command.SetDryRun(nil) })
+
+	if err := command.Exec(context.Background(), "rm", "-rf", "/tmp/nope"); err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	perCommand := &bytes.Buffer{}
+	if err := command.New(command.WithDryRun(perCommand)).Exec(context.Background(), "echo", "hi"); err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	if want := "rm -rf /tmp/nope\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+	if want := "echo hi\n"; perCommand.String() != want {
+		t.Errorf("got %q, want %q", perCommand.String(), want)
+	}
+
+	command.SetDryRun(nil)
+	out, err := command.Output(context.Background(), "echo", "hi")
+	if err != nil || string(out) != "hi\n" {
+		t.Errorf("got %q, %v, want the command to run after disabling dry run", out, err)
+	}
+}
@@ -0,0 +1,34 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrElevationUnavailable is returned by RunElevated if there is no way
+// to run commands with elevated privileges on the system.
+var ErrElevationUnavailable = errors.New("elevation unavailable")
+
+// RunElevated runs cmd with administrator privileges. This is useful for tools that
+// occasionally need them, for example to edit /etc/hosts.
+//
+// On Unix, cmd is run using sudo, or doas if sudo is not installed. If the current process
+// is already running as root, cmd is run as is. Before the first elevated command, sudo -v
+// is run to prompt for a password if one is needed. sudo caches the credentials, so later
+// commands do not prompt again until they expire. Note that depending on their configuration,
+// sudo and doas may remove environment variables set using WithEnv or WithExtraEnv.
+//
+// On Windows, cmd is run using Start-Process with the RunAs verb, which shows a UAC prompt
+// if required. The program runs in a new window, so its output cannot be captured and
+// WithDir has no effect, only its exit code is reported.
+//
+// If there is no way to elevate, the returned error wraps ErrElevationUnavailable.
+// Otherwise, see Exec for details on the returned error.
+func RunElevated(ctx context.Context, cmd *Cmd) error {
+	elevated, err := elevate(ctx, cmd)
+	if err != nil {
+		return fmt.Errorf("command: failed to run '%s' elevated: %w", cmd, err)
+	}
+	return New(elevated.Opts...).exec(ctx, "command.RunElevated", elevated.Name, elevated.Args)
+}
@@ -0,0 +1,10 @@
+//go:build !unix && !windows
+
+package command
+
+import "context"
+
+// elevate is not supported on this platform.
+func elevate(ctx context.Context, cmd *Cmd) (*Cmd, error) {
+	return nil, ErrElevationUnavailable
+}
@@ -0,0 +1,50 @@
+package command_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/TouchBistro/goutils/command"
+)
+
+func TestRunElevatedDryRun(t *testing.T) {
+	want := "echo 'a b'\n"
+	if os.Geteuid() != 0 {
+		switch {
+		case command.Exists("sudo"):
+			want = "sudo -- " + want
+		case command.Exists("doas"):
+			want = "doas -- " + want
+		default:
+			t.Skip("no elevation tool available")
+		}
+	}
+	buf := &bytes.Buffer{}
+	cmd := &command.Cmd{Name: "echo", Args: []string{"a b"}, Opts: []command.Option{command.WithDryRun(buf)}}
+	if err := command.RunElevated(context.Background(), cmd); err != nil {
+		t.Fatalf("got err %v, want nil", err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestRunElevated(t *testing.T) {
+	if os.Geteuid() != 0 {
+		// Only run if elevating does not require a password.
+		if err := exec.Command("sudo", "-n", "true").Run(); err != nil {
+			t.Skip("sudo requires a password")
+		}
+	}
+	buf := &bytes.Buffer{}
+	cmd := &command.Cmd{Name: "id", Args: []string{"-u"}, Opts: []command.Option{command.CaptureStdout(buf)}}
+	if err := command.RunElevated(context.Background(), cmd); err != nil {
+		t.Fatalf("got err %v, want nil", err)
+	}
+	if got := buf.String(); got != "0\n" {
+		t.Errorf("got uid %q, want %q", got, "0\n")
+	}
+}
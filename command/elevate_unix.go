@@ -0,0 +1,59 @@
+//go:build unix
+
+package command
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"sync"
+)
+
+// findElevator returns the name of the program used to run commands as root.
+var findElevator = sync.OnceValues(func() (string, error) {
+	for _, name := range []string{"sudo", "doas"} {
+		if _, err := exec.LookPath(name); err == nil {
+			return name, nil
+		}
+	}
+	return "", ErrElevationUnavailable
+})
+
+var (
+	sudoMu        sync.Mutex
+	sudoValidated bool
+)
+
+// elevate returns a command that runs cmd as root.
+func elevate(ctx context.Context, cmd *Cmd) (*Cmd, error) {
+	if os.Geteuid() == 0 {
+		return cmd, nil
+	}
+	name, err := findElevator()
+	if err != nil {
+		return nil, err
+	}
+	if name == "sudo" && New(cmd.Opts...).dryRunWriter() == nil {
+		if err := validateSudo(ctx); err != nil {
+			return nil, err
+		}
+	}
+	args := append([]string{"--", cmd.Name}, cmd.Args...)
+	return &Cmd{Name: name, Args: args, Opts: cmd.Opts}, nil
+}
+
+// validateSudo prompts for the password of the user if sudo requires one, so that the prompt
+// is shown once up front instead of in the middle of the output of a command.
+func validateSudo(ctx context.Context) error {
+	sudoMu.Lock()
+	defer sudoMu.Unlock()
+	if sudoValidated {
+		return nil
+	}
+	c := New(WithStdin(os.Stdin), WithStdout(os.Stdout), WithStderr(os.Stderr))
+	if err := c.exec(ctx, "command.RunElevated", "sudo", []string{"-v"}); err != nil {
+		return err
+	}
+	sudoValidated = true
+	return nil
+}
@@ -0,0 +1,38 @@
+//go:build windows
+
+package command
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"syscall"
+	"unicode/utf16"
+)
+
+// elevate returns a command that runs cmd as an administrator.
+func elevate(ctx context.Context, cmd *Cmd) (*Cmd, error) {
+	var sb strings.Builder
+	sb.WriteString("$p = Start-Process -FilePath ")
+	writePowerShellQuoted(&sb, cmd.Name)
+	if len(cmd.Args) > 0 {
+		// Start-Process passes the arguments to the program as a single command line.
+		args := make([]string, len(cmd.Args))
+		for i, arg := range cmd.Args {
+			args[i] = syscall.EscapeArg(arg)
+		}
+		sb.WriteString(" -ArgumentList ")
+		writePowerShellQuoted(&sb, strings.Join(args, " "))
+	}
+	sb.WriteString(" -Verb RunAs -Wait -PassThru; exit $p.ExitCode")
+
+	// Pass the script encoded, so it does not need to be quoted on the command line.
+	script := utf16.Encode([]rune(sb.String()))
+	b := make([]byte, 2*len(script))
+	for i, c := range script {
+		b[2*i] = byte(c)
+		b[2*i+1] = byte(c >> 8)
+	}
+	args := []string{"-NoProfile", "-NonInteractive", "-EncodedCommand", base64.StdEncoding.EncodeToString(b)}
+	return &Cmd{Name: "powershell.exe", Args: args, Opts: cmd.Opts}, nil
+}
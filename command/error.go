@@ -0,0 +1,104 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/TouchBistro/goutils/errors"
+)
+
+const (
+	// maxStderrLen is the maximum number of bytes of stderr that are included in an Error.
+	maxStderrLen = 4096
+	// maxStderrLines is the maximum number of lines of stderr that are included in the
+	// fields of the errors.Error returned by Exec.
+	maxStderrLines = 10
+)
+
+// KindFailed is the kind of the errors returned when a command fails to run
+// or exits with a non-zero status.
+var KindFailed errors.Kind = kind("command failed")
+
+type kind string
+
+func (k kind) Kind() string {
+	return string(k)
+}
+
+// Error contains the details of a command that failed to run or exited with a non-zero status.
+//
+// Exec returns an *errors.Error with the kind KindFailed that wraps an Error, so the
+// details are available both as fields for logging and to errors.As. The fields are:
+//
+//   - command: the command line, quoted for the platform shell, see Quote
+//   - exit_code: the exit code of the process, see ExitCode
+//   - stderr: the last 10 lines of Stderr, if any
+//
+// The exit code of the process is also returned by errors.ExitCode for the error.
+type Error struct {
+	// Name is the name of the program that was run.
+	Name string
+	// Args are the arguments the program was run with.
+	Args []string
+	// ExitCode is the exit code of the process. It is -1 if the process
+	// did not exit normally, for example if it could not be started.
+	ExitCode int
+	// Stderr contains the end of what the process wrote to stderr, up to the last 4 KiB.
+	// It is captured even if stderr was set using WithStderr, unless it was set to an
+	// *os.File such as os.Stderr, in which case the process writes to the file directly.
+	Stderr string
+	// Err is the underlying error returned by os/exec.
+	Err error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("failed to run '%s %s': %v", e.Name, strings.Join(e.Args, " "), e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// wrapError wraps e in an *errors.Error for op with details about the failure as fields.
+func wrapError(op errors.Op, e *Error) error {
+	fields := map[string]any{
+		"command":   Quote(append([]string{e.Name}, e.Args...)...),
+		"exit_code": e.ExitCode,
+	}
+	if stderr := lastLines(e.Stderr, maxStderrLines); stderr != "" {
+		fields["stderr"] = stderr
+	}
+	return errors.Wrap(e, errors.Meta{Kind: KindFailed, Op: op, Fields: fields})
+}
+
+// lastLines returns the last n lines of s without the trailing newline.
+func lastLines(s string, n int) string {
+	s = strings.TrimRight(s, "\r\n")
+	i := len(s)
+	for ; n > 0; n-- {
+		i = strings.LastIndexByte(s[:i], '\n')
+		if i == -1 {
+			return s
+		}
+	}
+	return s[i+1:]
+}
+
+// tailBuffer is an io.Writer that keeps the last max bytes written to it.
+type tailBuffer struct {
+	max int
+	buf []byte
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if len(p) >= b.max {
+		b.buf = append(b.buf[:0], p[len(p)-b.max:]...)
+		return n, nil
+	}
+	if over := len(b.buf) + len(p) - b.max; over > 0 {
+		b.buf = b.buf[:copy(b.buf, b.buf[over:])]
+	}
+	b.buf = append(b.buf, p...)
+	return n, nil
+}
@@ -0,0 +1,191 @@
+package command_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/TouchBistro/goutils/command"
+	"github.com/TouchBistro/goutils/errors"
+)
+
+func TestError(t *testing.T) {
+	err := command.Exec(context.Background(), "sh", "-c", "echo oops >&2; exit 3")
+	var cmdErr *command.Error
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("got error %v, want *command.Error", err)
+	}
+	if cmdErr.ExitCode != 3 {
+		t.Errorf("got exit code %d, want 3", cmdErr.ExitCode)
+	}
+	if cmdErr.Stderr != "oops\n" {
+		t.Errorf("got stderr %q, want %q", cmdErr.Stderr, "oops\n")
+	}
+	if cmdErr.Name != "sh" {
+		t.Errorf("got name %q, want %q", cmdErr.Name, "sh")
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		t.Errorf("got error %v, want it to wrap *exec.ExitError", err)
+	}
+	want := "command failed: failed to run 'sh -c echo oops >&2; exit 3': exit status 3"
+	if err.Error() != want {
+		t.Errorf("got message %q, want %q", err.Error(), want)
+	}
+}
+
+func TestErrorFields(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantFields map[string]any
+	}{
+		{
+			"exit code",
+			[]string{"sh", "-c", "echo oops >&2; exit 3"},
+			map[string]any{"command": "sh -c 'echo oops >&2; exit 3'", "exit_code": 3, "stderr": "oops"},
+		},
+		{
+			"last lines",
+			[]string{"sh", "-c", "i=0; while [ $i -lt 20 ]; do echo line$i >&2; i=$((i+1)); done; exit 1"},
+			map[string]any{
+				"command":   "sh -c 'i=0; while [ $i -lt 20 ]; do echo line$i >&2; i=$((i+1)); done; exit 1'",
+				"exit_code": 1,
+				"stderr":    "line10\nline11\nline12\nline13\nline14\nline15\nline16\nline17\nline18\nline19",
+			},
+		},
+		{"no stderr", []string{"false"}, map[string]any{"command": "false", "exit_code": 1}},
+		{"not started", []string{"notacmd", "a b"}, map[string]any{"command": "notacmd 'a b'", "exit_code": -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := command.Exec(context.Background(), tt.args[0], tt.args[1:]...)
+			var e *errors.Error
+			if !errors.As(err, &e) {
+				t.Fatalf("got error %v, want *errors.Error", err)
+			}
+			if e.Kind != command.KindFailed {
+				t.Errorf("got kind %v, want %v", e.Kind, command.KindFailed)
+			}
+			if !reflect.DeepEqual(e.Fields, tt.wantFields) {
+				t.Errorf("got fields %v, want %v", e.Fields, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestErrorExitCode(t *testing.T) {
+	err := command.Exec(context.Background(), "sh", "-c", "exit 3")
+	if code := errors.ExitCode(err); code != 3 {
+		t.Errorf("got exit code %d, want 3", code)
+	}
+}
+
+func TestErrorStderrTee(t *testing.T) {
+	buf := &bytes.Buffer{}
+	cmd := command.New(command.WithStderr(buf))
+	err := cmd.Exec(context.Background(), "sh", "-c", "echo oops >&2; exit 1")
+	var cmdErr *command.Error
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("got error %v, want *command.Error", err)
+	}
+	if buf.String() != "oops\n" || cmdErr.Stderr != "oops\n" {
+		t.Errorf("got stderr %q and captured %q, want both to be %q", buf.String(), cmdErr.Stderr, "oops\n")
+	}
+}
+
+func TestErrorStderrFile(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "stderr"))
+	if err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	defer f.Close()
+	cmd := command.New(command.WithStderr(f))
+	err = cmd.Exec(context.Background(), "sh", "-c", "echo oops >&2; exit 1")
+	var cmdErr *command.Error
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("got error %v, want *command.Error", err)
+	}
+	// Files are written to directly, so stderr is not captured.
+	if cmdErr.Stderr != "" {
+		t.Errorf("got captured stderr %q, want it to be empty", cmdErr.Stderr)
+	}
+	b, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	if string(b) != "oops\n" {
+		t.Errorf("got stderr %q, want %q", b, "oops\n")
+	}
+}
+
+func TestErrorStderrTail(t *testing.T) {
+	// Write more than 4 KiB to stderr in multiple writes.
+	err := command.Exec(context.Background(), "sh", "-c", "i=0; while [ $i -lt 1000 ]; do echo line$i >&2; i=$((i+1)); done; exit 1")
+	var cmdErr *command.Error
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("got error %v, want *command.Error", err)
+	}
+	if len(cmdErr.Stderr) != 4096 {
+		t.Errorf("got %d bytes of stderr, want 4096", len(cmdErr.Stderr))
+	}
+	if !strings.HasSuffix(cmdErr.Stderr, "line998\nline999\n") {
+		t.Errorf("got stderr ending with %q, want the last lines", cmdErr.Stderr[len(cmdErr.Stderr)-20:])
+	}
+}
+
+func TestErrorNotStarted(t *testing.T) {
+	err := command.Exec(context.Background(), "notacmd")
+	var cmdErr *command.Error
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("got error %v, want *command.Error", err)
+	}
+	if cmdErr.ExitCode != -1 {
+		t.Errorf("got exit code %d, want -1", cmdErr.ExitCode)
+	}
+	if !errors.Is(err, exec.ErrNotFound) {
+		t.Errorf("got error %v, want it to wrap %v", err, exec.ErrNotFound)
+	}
+}
+
+func TestErrorOp(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		run  func() error
+		want errors.Op
+	}{
+		{"Exec", func() error { return command.Exec(ctx, "false") }, "command.Command.Exec"},
+		{"Output", func() error {
+			_, err := command.Output(ctx, "false")
+			return err
+		}, "command.Command.Output"},
+		{"CombinedOutput", func() error {
+			_, err := command.CombinedOutput(ctx, "false")
+			return err
+		}, "command.Command.CombinedOutput"},
+		{"Run", func() error { return command.Run("false", nil) }, "command.Run"},
+		{"Cmd.Run", func() error {
+			c := &command.Cmd{Name: "false"}
+			return c.Run(ctx)
+		}, "command.Cmd.Run"},
+		{"RunShell", func() error { return command.RunShell(ctx, "exit 1") }, "command.RunShell"},
+		{"Start", func() error {
+			_, err := command.Start(ctx, &command.Cmd{Name: "notacmd"})
+			return err
+		}, "command.Command.Start"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops := errors.Ops(tt.run())
+			if len(ops) != 1 || ops[0] != tt.want {
+				t.Errorf("got ops %v, want [%s]", ops, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package command
+
+import (
+	"errors"
+	"slices"
+	"sort"
+
+	"github.com/TouchBistro/goutils/text"
+)
+
+// WithExpandVariables expands ${VAR} references in the arguments and environment variable
+// values of the command using lookup, for example os.LookupEnv. This allows running command
+// templates defined in config files. See text.ExpandVariablesStrict for the supported syntax.
+//
+// Expansion is strict, if any variable is not defined and has no default value, the command
+// is not run and the returned error wraps a *text.MissingVariablesError.
+// The name of the program is not expanded.
+//
+// Since commands are not run in a shell, expanding a variable can never produce additional
+// arguments or change how the command is interpreted, even if the value contains spaces or
+// shell syntax. This makes it safe to use with values that are not trusted.
+//
+// With RunShell, only environment variable values are expanded and the script is left as is,
+// since a value expanded into the script would be interpreted by the shell. Reference the
+// environment variables from the script instead, for example "$NAME" in sh.
+func WithExpandVariables(lookup func(string) (string, bool)) Option {
+	return func(c *Command) {
+		c.lookup = lookup
+	}
+}
+
+// expandVariables returns a copy of c and args with all variables expanded using c.lookup.
+func (c *Command) expandVariables(args []string) (*Command, []string, error) {
+	var missing []string
+	expand := func(s string) string {
+		v, err := text.ExpandVariablesStringStrict(s, c.lookup)
+		var mErr *text.MissingVariablesError
+		if errors.As(err, &mErr) {
+			for _, name := range mErr.Names {
+				if !slices.Contains(missing, name) {
+					missing = append(missing, name)
+				}
+			}
+		}
+		return v
+	}
+	expandEnv := func(env map[string]string) map[string]string {
+		if env == nil {
+			return nil
+		}
+		// Expand in a consistent order so that missing variables are reported consistently.
+		keys := make([]string, 0, len(env))
+		for k := range env {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		expanded := make(map[string]string, len(env))
+		for _, k := range keys {
+			expanded[k] = expand(env[k])
+		}
+		return expanded
+	}
+
+	expandedArgs := args
+	if !c.keepArgs {
+		expandedArgs = make([]string, len(args))
+		for i, arg := range args {
+			expandedArgs[i] = expand(arg)
+		}
+	}
+	cc := *c
+	cc.env = expandEnv(c.env)
+	cc.extraEnv = expandEnv(c.extraEnv)
+	cc.lookup = nil
+	if len(missing) > 0 {
+		return nil, nil, &text.MissingVariablesError{Names: missing}
+	}
+	return &cc, expandedArgs, nil
+}
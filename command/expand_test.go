@@ -0,0 +1,62 @@
+package command_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/TouchBistro/goutils/command"
+	"github.com/TouchBistro/goutils/text"
+)
+
+func testLookup(name string) (string, bool) {
+	v, ok := map[string]string{
+		"NAME":   "world",
+		"SPACES": "a b; echo injected",
+		"TAG":    "v1",
+	}[name]
+	return v, ok
+}
+
+func TestWithExpandVariables(t *testing.T) {
+	buf := &bytes.Buffer{}
+	cmd := command.New(
+		command.WithStdout(buf),
+		command.WithExpandVariables(testLookup),
+		command.WithExtraEnv(map[string]string{"GREETING": "hello ${NAME}"}),
+	)
+	err := cmd.Exec(context.Background(), "sh", "-c", `printf '%s|' "$GREETING" "$@"`, "sh", "${SPACES}", "${TAG:+--push}", "${MISSING:-default}", "$${NAME}")
+	if err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	want := "hello world|a b; echo injected|--push|default|${NAME}|"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestWithExpandVariablesMissing(t *testing.T) {
+	buf := &bytes.Buffer{}
+	cmd := command.New(
+		command.WithStdout(buf),
+		command.WithExpandVariables(testLookup),
+		command.WithExtraEnv(map[string]string{"B": "${b}", "A": "${a}"}),
+	)
+	err := cmd.Exec(context.Background(), "echo", "${NAME}", "${c}", "${a}")
+	var cmdErr *command.Error
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("got error %v, want *command.Error", err)
+	}
+	var mErr *text.MissingVariablesError
+	if !errors.As(err, &mErr) {
+		t.Fatalf("got error %v, want *text.MissingVariablesError", err)
+	}
+	if want := []string{"c", "a", "b"}; !reflect.DeepEqual(mErr.Names, want) {
+		t.Errorf("got missing %v, want %v", mErr.Names, want)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("got stdout %q, want the command not to run", buf.String())
+	}
+}
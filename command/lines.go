@@ -0,0 +1,119 @@
+package command
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// maxLineLen is the maximum length of a line passed to a line function.
+// Longer lines are split into multiple lines.
+const maxLineLen = 64 * 1024
+
+// Stream identifies an output stream of a command.
+type Stream int
+
+const (
+	// Stdout is the standard output stream.
+	Stdout Stream = iota
+	// Stderr is the standard error stream.
+	Stderr
+)
+
+func (s Stream) String() string {
+	if s == Stderr {
+		return "stderr"
+	}
+	return "stdout"
+}
+
+// WithLineFunc calls fn with each line the command writes to stdout or stderr, along with
+// the stream it was written to. This allows showing live output of long running commands,
+// for example by logging each line, without interfering with other output like spinners.
+//
+// Lines do not include the trailing newline or carriage return. fn is never called
+// concurrently and all lines have been passed to fn by the time Exec returns.
+// Lines longer than 64 KiB are split into multiple lines.
+// It can be combined with WithStdout, WithStderr and the capture options.
+//
+// Note that if stdout or stderr is also set to a file like os.Stdout, the command writes
+// to a pipe instead of the file, so it cannot detect if it is writing to a terminal.
+func WithLineFunc(fn func(stream Stream, line string)) Option {
+	return func(c *Command) {
+		c.lineFunc = fn
+	}
+}
+
+// WithLineWriter writes each line the command writes to stdout or stderr to w.
+// Each line is written using a single call to w.Write and includes a trailing newline,
+// so lines from stdout and stderr are never mixed together. This is useful for writers
+// that expect whole lines, like the debug writer of a spinner. Errors from w are ignored.
+//
+// It replaces any function set by WithLineFunc. See WithLineFunc for details.
+func WithLineWriter(w io.Writer) Option {
+	return WithLineFunc(func(_ Stream, line string) {
+		io.WriteString(w, line+"\n")
+	})
+}
+
+// lineWriters returns writers that pass lines written to stdout and stderr to c.lineFunc,
+// along with a function that must be called once output is done to flush any partial lines.
+// If c.lineFunc is nil, the writers are nil.
+func (c *Command) lineWriters() (stdout, stderr io.Writer, flush func()) {
+	if c.lineFunc == nil {
+		return nil, nil, func() {}
+	}
+	mu := &sync.Mutex{}
+	outLines := &lineWriter{mu: mu, stream: Stdout, fn: c.lineFunc}
+	errLines := &lineWriter{mu: mu, stream: Stderr, fn: c.lineFunc}
+	return outLines, errLines, func() {
+		outLines.flush()
+		errLines.flush()
+	}
+}
+
+// lineWriter is an io.Writer that splits what is written to it into lines and calls fn with each one.
+type lineWriter struct {
+	mu     *sync.Mutex // shared by the writers of all streams so fn is not called concurrently
+	stream Stream
+	fn     func(Stream, string)
+	buf    []byte // partial line
+}
+
+func (lw *lineWriter) Write(p []byte) (int, error) {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	lw.buf = append(lw.buf, p...)
+	rest := lw.buf
+	for {
+		i := bytes.IndexByte(rest, '\n')
+		if i == -1 {
+			if len(rest) < maxLineLen {
+				break
+			}
+			i = maxLineLen
+			lw.emit(rest[:i])
+			rest = rest[i:]
+			continue
+		}
+		lw.emit(rest[:i])
+		rest = rest[i+1:]
+	}
+	lw.buf = lw.buf[:copy(lw.buf, rest)]
+	return len(p), nil
+}
+
+// flush passes any remaining partial line to fn.
+func (lw *lineWriter) flush() {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	if len(lw.buf) > 0 {
+		lw.emit(lw.buf)
+		lw.buf = lw.buf[:0]
+	}
+}
+
+func (lw *lineWriter) emit(line []byte) {
+	line = bytes.TrimSuffix(line, []byte{'\r'})
+	lw.fn(lw.stream, string(line))
+}
@@ -0,0 +1,95 @@
+package command_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/TouchBistro/goutils/command"
+)
+
+type streamLine struct {
+	stream command.Stream
+	line   string
+}
+
+func TestWithLineFunc(t *testing.T) {
+	var got []streamLine
+	stdout := &bytes.Buffer{}
+	cmd := command.New(
+		command.WithStdout(stdout),
+		command.WithLineFunc(func(stream command.Stream, line string) {
+			got = append(got, streamLine{stream, line})
+		}),
+	)
+	err := cmd.Exec(context.Background(), "sh", "-c", `echo one; sleep 0.1; echo two >&2; sleep 0.1; printf 'three\r\n'; printf 'partial'`)
+	if err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	want := []streamLine{
+		{command.Stdout, "one"},
+		{command.Stderr, "two"},
+		{command.Stdout, "three"},
+		{command.Stdout, "partial"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got lines %v, want %v", got, want)
+	}
+	if want := "one\nthree\r\npartial"; stdout.String() != want {
+		t.Errorf("got stdout %q, want %q", stdout.String(), want)
+	}
+}
+
+func TestWithLineFuncLongLine(t *testing.T) {
+	var got []string
+	cmd := command.New(command.WithLineFunc(func(_ command.Stream, line string) {
+		got = append(got, line)
+	}))
+	// 100000 bytes without a newline.
+	err := cmd.Exec(context.Background(), "sh", "-c", `i=0; while [ $i -lt 10000 ]; do printf 'abcdefghij'; i=$((i+1)); done`)
+	if err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	if len(got) != 2 || len(got[0]) != 64*1024 || len(got[0])+len(got[1]) != 100000 {
+		lens := make([]int, len(got))
+		for i, l := range got {
+			lens[i] = len(l)
+		}
+		t.Errorf("got lines of lengths %v, want the line to be split at 64 KiB", lens)
+	}
+}
+
+func TestWithLineWriter(t *testing.T) {
+	var buf bytes.Buffer
+	var writes int
+	w := writerFunc(func(p []byte) (int, error) {
+		writes++
+		return buf.Write(p)
+	})
+	cmd := command.New(command.WithLineWriter(w))
+	err := cmd.Exec(context.Background(), "sh", "-c", "echo one; echo two >&2")
+	if err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 || writes != 2 {
+		t.Errorf("got %q in %d writes, want 2 lines in 2 writes", buf.String(), writes)
+	}
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
+
+func TestStreamString(t *testing.T) {
+	if got := command.Stdout.String(); got != "stdout" {
+		t.Errorf("got %q, want %q", got, "stdout")
+	}
+	if got := command.Stderr.String(); got != "stderr" {
+		t.Errorf("got %q, want %q", got, "stderr")
+	}
+}
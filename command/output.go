@@ -0,0 +1,80 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+)
+
+// Output executes the named program with the given arguments and returns what it wrote to stdout.
+// If stdout was set using WithStdout, the output is also written to it as the program runs.
+// The output is returned even if an error occurred. See Exec for details.
+func (c *Command) Output(ctx context.Context, name string, args ...string) ([]byte, error) {
+	var buf bytes.Buffer
+	cc := *c
+	cc.stdout = multiWriter(c.stdout, c.captureStdout)
+	cc.captureStdout = &buf
+	err := cc.exec(ctx, "command.Command.Output", name, args)
+	return buf.Bytes(), err
+}
+
+// CombinedOutput executes the named program with the given arguments and returns what it
+// wrote to both stdout and stderr. Since stdout and stderr are read separately, output written
+// to both at nearly the same time may not be in the exact order it was written.
+// The output is returned even if an error occurred. See Output for details.
+func (c *Command) CombinedOutput(ctx context.Context, name string, args ...string) ([]byte, error) {
+	var buf bytes.Buffer
+	// stdout and stderr are written to concurrently, so the buffer must be synchronized.
+	w := &syncWriter{w: &buf}
+	cc := *c
+	cc.stdout = multiWriter(c.stdout, c.captureStdout)
+	cc.stderr = multiWriter(c.stderr, c.captureStderr)
+	cc.captureStdout = w
+	cc.captureStderr = w
+	err := cc.exec(ctx, "command.Command.CombinedOutput", name, args)
+	return buf.Bytes(), err
+}
+
+// Output executes the named program with the given arguments and returns what it wrote to stdout.
+// This is a shorthand for when the default command options wish to be used.
+func Output(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return New().Output(ctx, name, args...)
+}
+
+// CombinedOutput executes the named program with the given arguments and returns what it
+// wrote to both stdout and stderr.
+// This is a shorthand for when the default command options wish to be used.
+func CombinedOutput(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return New().CombinedOutput(ctx, name, args...)
+}
+
+// multiWriter returns a writer that writes to all non-nil writers in ws.
+// It returns nil if there are none.
+func multiWriter(ws ...io.Writer) io.Writer {
+	var nonNil []io.Writer
+	for _, w := range ws {
+		if w != nil {
+			nonNil = append(nonNil, w)
+		}
+	}
+	switch len(nonNil) {
+	case 0:
+		return nil
+	case 1:
+		return nonNil[0]
+	}
+	return io.MultiWriter(nonNil...)
+}
+
+// syncWriter is an io.Writer that can be written to from multiple goroutines.
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (sw *syncWriter) Write(p []byte) (int, error) {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	return sw.w.Write(p)
+}
@@ -0,0 +1,86 @@
+package command_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/TouchBistro/goutils/command"
+)
+
+func TestOutput(t *testing.T) {
+	out, err := command.Output(context.Background(), "sh", "-c", "echo out; echo err >&2")
+	if err != nil {
+		t.Errorf("want nil error, got %v", err)
+	}
+	if string(out) != "out\n" {
+		t.Errorf("got output %q, want %q", out, "out\n")
+	}
+}
+
+func TestOutputStreams(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	captured := &bytes.Buffer{}
+	cmd := command.New(command.WithStdout(stdout), command.CaptureStdout(captured))
+	out, err := cmd.Output(context.Background(), "echo", "hello")
+	if err != nil {
+		t.Errorf("want nil error, got %v", err)
+	}
+	for name, got := range map[string]string{"output": string(out), "stdout": stdout.String(), "captured": captured.String()} {
+		if got != "hello\n" {
+			t.Errorf("got %s %q, want %q", name, got, "hello\n")
+		}
+	}
+}
+
+func TestOutputError(t *testing.T) {
+	out, err := command.Output(context.Background(), "sh", "-c", "echo partial; echo failed >&2; exit 2")
+	var cmdErr *command.Error
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("got error %v, want *command.Error", err)
+	}
+	if string(out) != "partial\n" {
+		t.Errorf("got output %q, want %q", out, "partial\n")
+	}
+	if cmdErr.Stderr != "failed\n" {
+		t.Errorf("got stderr %q, want %q", cmdErr.Stderr, "failed\n")
+	}
+}
+
+func TestCombinedOutput(t *testing.T) {
+	out, err := command.CombinedOutput(context.Background(), "sh", "-c", "echo out; sleep 0.1; echo err >&2")
+	if err != nil {
+		t.Errorf("want nil error, got %v", err)
+	}
+	if string(out) != "out\nerr\n" {
+		t.Errorf("got output %q, want %q", out, "out\nerr\n")
+	}
+}
+
+func TestCaptureStdoutAndStderr(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := command.New(command.CaptureStdout(stdout), command.CaptureStderr(stderr))
+	err := cmd.Exec(context.Background(), "sh", "-c", "echo out; echo err >&2")
+	if err != nil {
+		t.Errorf("want nil error, got %v", err)
+	}
+	if stdout.String() != "out\n" {
+		t.Errorf("got stdout %q, want %q", stdout.String(), "out\n")
+	}
+	if stderr.String() != "err\n" {
+		t.Errorf("got stderr %q, want %q", stderr.String(), "err\n")
+	}
+}
+
+func TestCaptureLargeOutput(t *testing.T) {
+	out, err := command.CombinedOutput(context.Background(), "sh", "-c", "i=0; while [ $i -lt 2000 ]; do echo out$i; echo err$i >&2; i=$((i+1)); done")
+	if err != nil {
+		t.Errorf("want nil error, got %v", err)
+	}
+	if n := strings.Count(string(out), "\n"); n != 4000 {
+		t.Errorf("got %d lines, want 4000", n)
+	}
+}
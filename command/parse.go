@@ -0,0 +1,35 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/TouchBistro/goutils/text"
+)
+
+// ErrEmptyCommand is returned by Parse if the command string does not contain a program.
+var ErrEmptyCommand = errors.New("empty command")
+
+// Parse parses a command line like "git commit -m 'a message'" into a Cmd that runs
+// with opts. This allows command strings configured by users, for example in YAML files,
+// to be run safely without using a shell.
+//
+// The command line is split into words using the same rules as a POSIX shell, see
+// text.ShellSplit. The first word is the program and the rest are its arguments.
+// No expansions are performed, and shell operators like | and && are passed to the program
+// as arguments instead of being interpreted. Use RunShell if shell features are needed.
+// Note that backslashes outside of quotes escape the next character, so Windows paths
+// must be quoted, for example 'C:\tools\app.exe'.
+//
+// If s contains an unterminated quote, the returned error wraps text.ErrUnterminatedQuote.
+// If s does not contain any words, the returned error wraps ErrEmptyCommand.
+func Parse(s string, opts ...Option) (*Cmd, error) {
+	words, err := text.ShellSplit(s)
+	if err != nil {
+		return nil, fmt.Errorf("command: failed to parse %q: %w", s, err)
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("command: failed to parse %q: %w", s, ErrEmptyCommand)
+	}
+	return &Cmd{Name: words[0], Args: words[1:], Opts: opts}, nil
+}
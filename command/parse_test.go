@@ -0,0 +1,87 @@
+package command_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/TouchBistro/goutils/command"
+	"github.com/TouchBistro/goutils/errors"
+	"github.com/TouchBistro/goutils/text"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		wantName string
+		wantArgs []string
+	}{
+		{"single quotes", "git commit -m 'a message'", "git", []string{"commit", "-m", "a message"}},
+		{"double quotes", `echo "hello $USER" done`, "echo", []string{"hello $USER", "done"}},
+		{"no args", "  ls  ", "ls", []string{}},
+		{"operators", "cat a | grep b", "cat", []string{"a", "|", "grep", "b"}},
+		{"escapes", `touch my\ file`, "touch", []string{"my file"}},
+		{"multiline", "docker run \\\n  --rm alpine", "docker", []string{"run", "--rm", "alpine"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := command.Parse(tt.s)
+			if err != nil {
+				t.Fatalf("got err %v, want nil", err)
+			}
+			if cmd.Name != tt.wantName {
+				t.Errorf("got name %q, want %q", cmd.Name, tt.wantName)
+			}
+			if !reflect.DeepEqual(cmd.Args, tt.wantArgs) {
+				t.Errorf("got args %q, want %q", cmd.Args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		wantErr error
+	}{
+		{"empty", "", command.ErrEmptyCommand},
+		{"comment", "  # nothing to run", command.ErrEmptyCommand},
+		{"unterminated", "echo 'oops", text.ErrUnterminatedQuote},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := command.Parse(tt.s)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("got err %v, want %v", err, tt.wantErr)
+			}
+			if cmd != nil {
+				t.Errorf("got cmd %v, want nil", cmd)
+			}
+		})
+	}
+}
+
+func TestParseRun(t *testing.T) {
+	buf := &bytes.Buffer{}
+	cmd, err := command.Parse("printf '%s|' 'a b' c", command.CaptureStdout(buf))
+	if err != nil {
+		t.Fatalf("got err %v, want nil", err)
+	}
+	if err := cmd.Run(context.Background()); err != nil {
+		t.Fatalf("got err %v, want nil", err)
+	}
+	if got := buf.String(); got != "a b|c|" {
+		t.Errorf("got %q, want %q", got, "a b|c|")
+	}
+	// Parsing the string of a Cmd returns the same command.
+	parsed, err := command.Parse(cmd.String())
+	if err != nil {
+		t.Fatalf("got err %v, want nil", err)
+	}
+	if parsed.Name != cmd.Name || !reflect.DeepEqual(parsed.Args, cmd.Args) {
+		t.Errorf("got %v, want %v", parsed, cmd)
+	}
+}
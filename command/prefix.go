@@ -0,0 +1,80 @@
+package command
+
+import (
+	"io"
+	"sync"
+
+	"github.com/TouchBistro/goutils/color"
+)
+
+// WithPrefix prefixes each line the command writes to stdout or stderr with label, like
+// docker-compose does for the output of each service. This makes the output of commands
+// that run concurrently readable, for example when using RunAll.
+//
+// Each label is automatically assigned a color, which is the same for all commands that use
+// the label. Colors are only used if the writer supports them, see color.EnabledFor.
+// The prefix is only added to output written to the writers set by WithStdout and WithStderr,
+// not to captured output or lines passed to WithLineFunc. Each line is written using a single
+// call to Write. See WithLineFunc for details on how output is split into lines.
+func WithPrefix(label string) Option {
+	return func(c *Command) {
+		c.prefix = label
+	}
+}
+
+// prefixColors are the colors assigned to labels, in order.
+var prefixColors = []func(*color.Colorer, string) string{
+	(*color.Colorer).Cyan,
+	(*color.Colorer).Yellow,
+	(*color.Colorer).Green,
+	(*color.Colorer).Magenta,
+	(*color.Colorer).Blue,
+	(*color.Colorer).Red,
+}
+
+var (
+	labelColorsMu sync.Mutex
+	labelColors   = make(map[string]int) // index of the color assigned to each label
+)
+
+// labelColor returns the color assigned to label, assigning the next one if it has none.
+func labelColor(label string) func(*color.Colorer, string) string {
+	labelColorsMu.Lock()
+	defer labelColorsMu.Unlock()
+	i, ok := labelColors[label]
+	if !ok {
+		i = len(labelColors) % len(prefixColors)
+		labelColors[label] = i
+	}
+	return prefixColors[i]
+}
+
+// prefixWriters returns the writers for stdout and stderr, which add the prefix set by
+// WithPrefix to each line, along with a function that must be called once output is done
+// to flush any partial lines. If there is no prefix, c.stdout and c.stderr are returned.
+func (c *Command) prefixWriters() (stdout, stderr io.Writer, flush func()) {
+	if c.prefix == "" {
+		return c.stdout, c.stderr, func() {}
+	}
+	mu := &sync.Mutex{}
+	fn := labelColor(c.prefix)
+	stdout, flushOut := newPrefixWriter(mu, Stdout, c.stdout, c.prefix, fn)
+	stderr, flushErr := newPrefixWriter(mu, Stderr, c.stderr, c.prefix, fn)
+	return stdout, stderr, func() {
+		flushOut()
+		flushErr()
+	}
+}
+
+// newPrefixWriter returns a writer that writes each line to w prefixed with label colored
+// using fn, along with a function to flush any partial line. If w is nil, the writer is nil.
+func newPrefixWriter(mu *sync.Mutex, stream Stream, w io.Writer, label string, fn func(*color.Colorer, string) string) (io.Writer, func()) {
+	if w == nil {
+		return nil, func() {}
+	}
+	prefix := fn(color.NewColorer(w), label+" |") + " "
+	lw := &lineWriter{mu: mu, stream: stream, fn: func(_ Stream, line string) {
+		io.WriteString(w, prefix+line+"\n")
+	}}
+	return lw, lw.flush
+}
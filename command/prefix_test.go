@@ -0,0 +1,90 @@
+package command_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/TouchBistro/goutils/command"
+)
+
+func TestWithPrefix(t *testing.T) {
+	tests := []struct {
+		name       string
+		script     string
+		wantStdout string
+		wantStderr string
+	}{
+		{"lines", "echo one; echo two", "web | one\nweb | two\n", ""},
+		{"stderr", "echo out; echo err >&2", "web | out\n", "web | err\n"},
+		{"partial line", "printf 'a\\nb'", "web | a\nweb | b\n", ""},
+		{"crlf", "printf 'a\\r\\n'", "web | a\n", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			captured := &bytes.Buffer{}
+			cmd := command.New(
+				command.WithPrefix("web"),
+				command.WithStdout(stdout),
+				command.WithStderr(stderr),
+				command.CaptureStdout(captured),
+			)
+			if err := cmd.Exec(context.Background(), "sh", "-c", tt.script); err != nil {
+				t.Fatalf("got err %v, want nil", err)
+			}
+			if got := stdout.String(); got != tt.wantStdout {
+				t.Errorf("got stdout %q, want %q", got, tt.wantStdout)
+			}
+			if got := stderr.String(); got != tt.wantStderr {
+				t.Errorf("got stderr %q, want %q", got, tt.wantStderr)
+			}
+			// Captured output is not prefixed.
+			if strings.Contains(captured.String(), "web |") {
+				t.Errorf("got captured output %q, want it to not be prefixed", captured.String())
+			}
+		})
+	}
+}
+
+func TestWithPrefixRunAll(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := &syncBuffer{buf: buf}
+	script := "i=0; while [ $i -lt 50 ]; do echo line$i; i=$((i+1)); done"
+	var cmds []*command.Cmd
+	for _, label := range []string{"api", "web", "db"} {
+		cmds = append(cmds, &command.Cmd{
+			Name: "sh",
+			Args: []string{"-c", script},
+			Opts: []command.Option{command.WithPrefix(label), command.WithStdout(w)},
+		})
+	}
+	if err := command.RunAll(context.Background(), cmds, 3); err != nil {
+		t.Fatalf("got err %v, want nil", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 150 {
+		t.Fatalf("got %d lines, want 150", len(lines))
+	}
+	for _, line := range lines {
+		label, rest, ok := strings.Cut(line, " | ")
+		if !ok || (label != "api" && label != "web" && label != "db") || !strings.HasPrefix(rest, "line") {
+			t.Errorf("got line %q, want a prefixed line", line)
+		}
+	}
+}
+
+// syncBuffer is a bytes.Buffer that is safe to write to concurrently.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf *bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
@@ -0,0 +1,210 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// ErrNotReady is returned by Start if a process does not become ready, see WithReadyCheck.
+var ErrNotReady = errors.New("process not ready")
+
+// ReadyCheck determines when a process started with Start is ready, see WithReadyCheck.
+// If both Addr and Line are set, both must be satisfied.
+type ReadyCheck struct {
+	// Addr is a TCP address, like localhost:8080. If set, the process is ready
+	// once a connection to Addr can be established.
+	Addr string
+	// Line is a regular expression. If set, the process is ready once it writes a line
+	// to stdout or stderr that matches Line, for example "listening on".
+	Line *regexp.Regexp
+	// Timeout is how long to wait for the process to become ready. It defaults to 30 seconds.
+	Timeout time.Duration
+	// Interval is how often to try connecting to Addr. It defaults to 100 milliseconds.
+	Interval time.Duration
+}
+
+// WithReadyCheck sets the check used by Start to determine when the process is ready.
+// Start does not return until the check is satisfied. If the process exits or the check does
+// not succeed within its timeout, the process is killed and Start returns an error. If the process
+// failed, the error wraps the error returned by Exec, otherwise it wraps ErrNotReady.
+//
+// Checking for a line uses the same line splitting as WithLineFunc. See it for details.
+// WithReadyCheck has no effect on Exec.
+func WithReadyCheck(check ReadyCheck) Option {
+	return func(c *Command) {
+		c.ready = &check
+	}
+}
+
+// Process is a program started by Start that runs in the background.
+// It is safe to use a Process across multiple goroutines.
+type Process struct {
+	r    *run // nil in dry run mode
+	done chan struct{}
+	err  error
+}
+
+// Start starts cmd in the background and returns a handle to the running process.
+// This is useful for tools that need to launch sidecar processes, like a database
+// for tests, and tear them down later. See Command.Start for details.
+func Start(ctx context.Context, cmd *Cmd) (*Process, error) {
+	return New(cmd.Opts...).Start(ctx, cmd.Name, cmd.Args...)
+}
+
+// Start starts the named program with the given arguments in the background and returns
+// a handle to the running process. Wait or Stop must be called to release the resources
+// associated with the process once it is no longer needed.
+//
+// The process is killed if ctx becomes done, so ctx should live for as long as the process
+// should run. Use WithReadyCheck to wait for the process to be ready before Start returns.
+//
+// If the program fails to start, the returned error is the same as Exec would return.
+// If dry run mode is enabled, the program is not run and the returned Process has already exited.
+func (c *Command) Start(ctx context.Context, name string, args ...string) (*Process, error) {
+	var lineReady chan struct{}
+	if c.ready != nil && c.ready.Line != nil {
+		// Copy the command so it is not modified by adding the line function.
+		cc := *c
+		c = &cc
+		lineReady = make(chan struct{})
+		var once sync.Once
+		fn, re := c.lineFunc, c.ready.Line
+		c.lineFunc = func(stream Stream, line string) {
+			if fn != nil {
+				fn(stream, line)
+			}
+			if re.MatchString(line) {
+				once.Do(func() { close(lineReady) })
+			}
+		}
+	}
+	r, err := c.start(ctx, "command.Command.Start", name, args)
+	if err != nil {
+		return nil, err
+	}
+	p := &Process{r: r, done: make(chan struct{})}
+	if r == nil {
+		close(p.done)
+		return p, nil
+	}
+	go func() {
+		p.err = r.wait()
+		close(p.done)
+	}()
+	if c.ready != nil {
+		if err := p.waitReady(c.ready, lineReady); err != nil {
+			p.Signal(os.Kill)
+			<-p.done
+			return nil, fmt.Errorf("command: '%s' did not become ready: %w", &Cmd{Name: name, Args: args}, err)
+		}
+	}
+	return p, nil
+}
+
+// waitReady waits until check is satisfied. lineReady is closed once a line matches check.Line.
+func (p *Process) waitReady(check *ReadyCheck, lineReady <-chan struct{}) error {
+	timeout := check.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	exited := func() error {
+		if p.err != nil {
+			return p.err
+		}
+		return fmt.Errorf("%w: process exited", ErrNotReady)
+	}
+	if lineReady != nil {
+		select {
+		case <-lineReady:
+		case <-p.done:
+			return exited()
+		case <-timer.C:
+			return fmt.Errorf("%w: no line matched %q within %s", ErrNotReady, check.Line, timeout)
+		}
+	}
+	if check.Addr == "" {
+		return nil
+	}
+	interval := check.Interval
+	if interval <= 0 {
+		interval = 100 * time.Millisecond
+	}
+	for {
+		conn, err := net.DialTimeout("tcp", check.Addr, interval)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+		select {
+		case <-time.After(interval):
+		case <-p.done:
+			return exited()
+		case <-timer.C:
+			return fmt.Errorf("%w: %s was not open within %s: %w", ErrNotReady, check.Addr, timeout, err)
+		}
+	}
+}
+
+// Pid returns the process ID of the process. It returns 0 in dry run mode.
+func (p *Process) Pid() int {
+	if p.r == nil {
+		return 0
+	}
+	return p.r.cmd.Process.Pid
+}
+
+// Wait waits for the process to exit. It returns the same error as Exec would.
+// Wait can be called multiple times and always returns the same result.
+func (p *Process) Wait() error {
+	<-p.done
+	return p.err
+}
+
+// Signal sends sig to the process. If WithProcessGroup was used, sig is sent to all processes
+// in the group. If the process has already exited, Signal returns os.ErrProcessDone.
+func (p *Process) Signal(sig os.Signal) error {
+	select {
+	case <-p.done:
+		return os.ErrProcessDone
+	default:
+	}
+	if p.r.pg != nil {
+		return p.r.pg.signal(p.r.cmd, sig)
+	}
+	return p.r.cmd.Process.Signal(sig)
+}
+
+// Stop asks the process to exit by sending it SIGTERM, and kills it if it has not exited
+// after gracePeriod. Windows does not have SIGTERM, so taskkill is used to ask the process
+// to exit instead, and the process and any processes it started are killed after gracePeriod.
+// Stop blocks until the process has exited and returns nil, unless signaling it failed.
+// Use Wait to get the result of the process. Use WithProcessGroup to also stop any
+// processes started by the program, otherwise they may keep running.
+func (p *Process) Stop(gracePeriod time.Duration) error {
+	if err := p.terminate(); err != nil {
+		if errors.Is(err, os.ErrProcessDone) {
+			return nil
+		}
+		return err
+	}
+	timer := time.NewTimer(gracePeriod)
+	defer timer.Stop()
+	select {
+	case <-p.done:
+		return nil
+	case <-timer.C:
+	}
+	if err := p.kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return err
+	}
+	<-p.done
+	return nil
+}
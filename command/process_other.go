@@ -0,0 +1,15 @@
+//go:build !windows
+
+package command
+
+import "os"
+
+// terminate asks the process to exit.
+func (p *Process) terminate() error {
+	return p.Signal(stopSignal)
+}
+
+// kill kills the process immediately.
+func (p *Process) kill() error {
+	return p.Signal(os.Kill)
+}
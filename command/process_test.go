@@ -0,0 +1,221 @@
+package command_test
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TouchBistro/goutils/command"
+	"github.com/TouchBistro/goutils/errors"
+)
+
+func TestStart(t *testing.T) {
+	buf := &bytes.Buffer{}
+	p, err := command.Start(context.Background(), &command.Cmd{
+		Name: "sh",
+		Args: []string{"-c", "echo started; exit 3"},
+		Opts: []command.Option{command.CaptureStdout(buf)},
+	})
+	if err != nil {
+		t.Fatalf("got err %v, want nil", err)
+	}
+	if p.Pid() <= 0 {
+		t.Errorf("got pid %d, want a positive pid", p.Pid())
+	}
+	err = p.Wait()
+	var cmdErr *command.Error
+	if !errors.As(err, &cmdErr) || cmdErr.ExitCode != 3 {
+		t.Errorf("got err %v, want exit code 3", err)
+	}
+	if buf.String() != "started\n" {
+		t.Errorf("got stdout %q, want %q", buf.String(), "started\n")
+	}
+	// Wait returns the same result again.
+	if err2 := p.Wait(); err2 != err {
+		t.Errorf("got err %v from second Wait, want %v", err2, err)
+	}
+	if err := p.Signal(os.Kill); err != os.ErrProcessDone {
+		t.Errorf("got err %v from Signal, want %v", err, os.ErrProcessDone)
+	}
+}
+
+func TestProcessStop(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+	}{
+		{"exits on term", "sleep 10"},
+		{"ignores term", "trap '' TERM; while :; do sleep 0.1; done"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := command.Start(context.Background(), &command.Cmd{
+				Name: "sh",
+				Args: []string{"-c", tt.script},
+				Opts: []command.Option{command.WithProcessGroup()},
+			})
+			if err != nil {
+				t.Fatalf("got err %v, want nil", err)
+			}
+			start := time.Now()
+			if err := p.Stop(200 * time.Millisecond); err != nil {
+				t.Errorf("got err %v from Stop, want nil", err)
+			}
+			if d := time.Since(start); d > 5*time.Second {
+				t.Errorf("took %s to stop, want it to be killed after the grace period", d)
+			}
+			if err := p.Wait(); err == nil {
+				t.Error("got nil err from Wait, want the process to have been terminated")
+			}
+			// Stopping again is a no-op.
+			if err := p.Stop(time.Second); err != nil {
+				t.Errorf("got err %v from second Stop, want nil", err)
+			}
+		})
+	}
+}
+
+func TestProcessSignal(t *testing.T) {
+	buf := &bytes.Buffer{}
+	p, err := command.Start(context.Background(), &command.Cmd{
+		Name: "sh",
+		Args: []string{"-c", "trap 'echo interrupted; exit 0' INT; echo ready; while :; do sleep 0.1; done"},
+		Opts: []command.Option{
+			command.CaptureStdout(buf),
+			command.WithReadyCheck(command.ReadyCheck{Line: regexp.MustCompile("^ready$")}),
+		},
+	})
+	if err != nil {
+		t.Fatalf("got err %v, want nil", err)
+	}
+	if err := p.Signal(os.Interrupt); err != nil {
+		t.Fatalf("got err %v from Signal, want nil", err)
+	}
+	if err := p.Wait(); err != nil {
+		t.Errorf("got err %v from Wait, want nil", err)
+	}
+	if buf.String() != "ready\ninterrupted\n" {
+		t.Errorf("got stdout %q, want %q", buf.String(), "ready\ninterrupted\n")
+	}
+}
+
+func TestStartReadyLine(t *testing.T) {
+	var lines []string
+	p, err := command.Start(context.Background(), &command.Cmd{
+		Name: "sh",
+		Args: []string{"-c", "sleep 0.2; echo 'listening on :8080' >&2; sleep 10"},
+		Opts: []command.Option{
+			command.WithLineFunc(func(_ command.Stream, line string) {
+				lines = append(lines, line)
+			}),
+			command.WithReadyCheck(command.ReadyCheck{Line: regexp.MustCompile("listening on")}),
+		},
+	})
+	if err != nil {
+		t.Fatalf("got err %v, want nil", err)
+	}
+	if err := p.Stop(time.Second); err != nil {
+		t.Fatalf("got err %v from Stop, want nil", err)
+	}
+	// The line function set by the caller is still called.
+	if strings.Join(lines, "\n") != "listening on :8080" {
+		t.Errorf("got lines %q, want the ready line", lines)
+	}
+}
+
+func TestStartReadyAddr(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+	p, err := command.Start(context.Background(), &command.Cmd{
+		Name: "sleep",
+		Args: []string{"10"},
+		Opts: []command.Option{command.WithReadyCheck(command.ReadyCheck{Addr: ln.Addr().String()})},
+	})
+	if err != nil {
+		t.Fatalf("got err %v, want nil", err)
+	}
+	if err := p.Stop(time.Second); err != nil {
+		t.Errorf("got err %v from Stop, want nil", err)
+	}
+}
+
+func TestStartNotReady(t *testing.T) {
+	// Find an address that is not open.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	tests := []struct {
+		name         string
+		script       string
+		check        command.ReadyCheck
+		wantErr      error
+		wantExitCode int
+	}{
+		{"timeout", "sleep 10", command.ReadyCheck{Addr: addr, Timeout: 300 * time.Millisecond}, command.ErrNotReady, 0},
+		{
+			"line timeout",
+			"echo starting; sleep 10",
+			command.ReadyCheck{Line: regexp.MustCompile("ready"), Timeout: 300 * time.Millisecond},
+			command.ErrNotReady,
+			0,
+		},
+		{"exited", "exit 0", command.ReadyCheck{Addr: addr}, command.ErrNotReady, 0},
+		{"failed", "exit 3", command.ReadyCheck{Line: regexp.MustCompile("ready")}, nil, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			p, err := command.Start(context.Background(), &command.Cmd{
+				Name: "sh",
+				Args: []string{"-c", tt.script},
+				Opts: []command.Option{command.WithProcessGroup(), command.WithReadyCheck(tt.check)},
+			})
+			if p != nil {
+				t.Errorf("got process %v, want nil", p)
+			}
+			if d := time.Since(start); d > 5*time.Second {
+				t.Errorf("took %s to fail, want the process to be killed", d)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("got err %v, want %v", err, tt.wantErr)
+			}
+			var cmdErr *command.Error
+			if tt.wantExitCode != 0 && (!errors.As(err, &cmdErr) || cmdErr.ExitCode != tt.wantExitCode) {
+				t.Errorf("got err %v, want exit code %d", err, tt.wantExitCode)
+			}
+		})
+	}
+}
+
+func TestStartDryRun(t *testing.T) {
+	buf := &bytes.Buffer{}
+	p, err := command.Start(context.Background(), &command.Cmd{
+		Name: "sleep",
+		Args: []string{"10"},
+		Opts: []command.Option{command.WithDryRun(buf)},
+	})
+	if err != nil {
+		t.Fatalf("got err %v, want nil", err)
+	}
+	if err := p.Wait(); err != nil {
+		t.Errorf("got err %v from Wait, want nil", err)
+	}
+	if err := p.Stop(time.Second); err != nil {
+		t.Errorf("got err %v from Stop, want nil", err)
+	}
+	if buf.String() != "sleep 10\n" {
+		t.Errorf("got %q, want %q", buf.String(), "sleep 10\n")
+	}
+}
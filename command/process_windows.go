@@ -0,0 +1,47 @@
+//go:build windows
+
+package command
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strconv"
+)
+
+// terminate asks the process to exit. Windows does not support signals, so taskkill is used
+// instead, which asks the process and any processes it started to close. This only works
+// for programs with a window, so if taskkill fails, the process is killed instead.
+func (p *Process) terminate() error {
+	err := p.taskkill(false)
+	if err == nil || errors.Is(err, os.ErrProcessDone) {
+		return err
+	}
+	return p.kill()
+}
+
+// kill kills the process immediately, along with any processes it started.
+// If the process was started using WithProcessGroup, its job is terminated. Otherwise,
+// taskkill is used and if that fails only the process itself is killed.
+func (p *Process) kill() error {
+	if p.r != nil && p.r.pg == nil {
+		if err := p.taskkill(true); err == nil || errors.Is(err, os.ErrProcessDone) {
+			return err
+		}
+	}
+	return p.Signal(os.Kill)
+}
+
+// taskkill runs taskkill for the process tree, forcing the processes to exit if force is true.
+func (p *Process) taskkill(force bool) error {
+	select {
+	case <-p.done:
+		return os.ErrProcessDone
+	default:
+	}
+	args := []string{"/T", "/PID", strconv.Itoa(p.r.cmd.Process.Pid)}
+	if force {
+		args = append(args, "/F")
+	}
+	return exec.Command("taskkill", args...).Run()
+}
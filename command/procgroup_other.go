@@ -0,0 +1,25 @@
+//go:build !unix && !windows
+
+package command
+
+import (
+	"os"
+	"os/exec"
+)
+
+// stopSignal is the signal sent by Process.Stop to ask a process to exit.
+var stopSignal = os.Interrupt
+
+// processGroup is a no-op on platforms that do not support process groups,
+// only the command itself is killed if the context becomes done.
+type processGroup struct{}
+
+func (pg *processGroup) prepare(cmd *exec.Cmd) {}
+
+func (pg *processGroup) started(cmd *exec.Cmd) {}
+
+func (pg *processGroup) signal(cmd *exec.Cmd, sig os.Signal) error {
+	return cmd.Process.Signal(sig)
+}
+
+func (pg *processGroup) close() {}
@@ -0,0 +1,52 @@
+//go:build unix
+
+package command
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// stopSignal is the signal sent by Process.Stop to ask a process to exit.
+var stopSignal os.Signal = syscall.SIGTERM
+
+// processGroup manages the process group of a command started using WithProcessGroup.
+type processGroup struct{}
+
+// prepare configures cmd to run in a new process group before it is started.
+func (pg *processGroup) prepare(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	// Starting a new session, which is done for WithPTY, also creates a new process group,
+	// and a session leader is not allowed to change its process group.
+	if !cmd.SysProcAttr.Setsid {
+		cmd.SysProcAttr.Setpgid = true
+	}
+	cmd.Cancel = func() error {
+		return pg.signal(cmd, os.Kill)
+	}
+}
+
+// signal sends sig to all processes in the process group.
+func (pg *processGroup) signal(cmd *exec.Cmd, sig os.Signal) error {
+	s, ok := sig.(syscall.Signal)
+	if !ok {
+		return cmd.Process.Signal(sig)
+	}
+	// The process group ID is the same as the PID of the command since it created the group.
+	// A negative PID sends the signal to the whole group.
+	err := syscall.Kill(-cmd.Process.Pid, s)
+	if err == syscall.ESRCH {
+		return os.ErrProcessDone
+	}
+	return err
+}
+
+// started is called after cmd has been started. The process group
+// is created by the process itself on Unix so there is nothing to do.
+func (pg *processGroup) started(cmd *exec.Cmd) {}
+
+// close releases any resources held by the process group.
+func (pg *processGroup) close() {}
@@ -0,0 +1,72 @@
+//go:build unix
+
+package command_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/TouchBistro/goutils/command"
+)
+
+func TestWithProcessGroup(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "pid")
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	// Start a grandchild process that would outlive the shell if only the shell was killed.
+	cmd := command.New(command.WithProcessGroup())
+	start := time.Now()
+	err := cmd.Exec(ctx, "sh", "-c", "sleep 30 & echo $! > "+pidFile+"; wait")
+	if err == nil {
+		t.Fatal("want non-nil error, got nil")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want it to wrap %v", err, context.DeadlineExceeded)
+	}
+	if d := time.Since(start); d > 10*time.Second {
+		t.Errorf("command took %s, want it to be killed", d)
+	}
+
+	b, err := os.ReadFile(pidFile)
+	if err != nil {
+		t.Fatalf("failed to read pid file: %v", err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(b)))
+	if err != nil {
+		t.Fatalf("failed to parse pid: %v", err)
+	}
+	// The grandchild is killed asynchronously, so give it a moment to exit.
+	for i := 0; i < 100; i++ {
+		if !processRunning(pid) {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	syscall.Kill(pid, syscall.SIGKILL)
+	t.Errorf("grandchild process %d is still running", pid)
+}
+
+// processRunning reports whether the process with the given pid is running.
+// Zombie processes are not considered running, since they may not be reaped
+// if the tests are run in a container.
+func processRunning(pid int) bool {
+	if syscall.Kill(pid, 0) != nil {
+		return false
+	}
+	b, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		// No procfs, assume the process is running since it could be signaled.
+		return true
+	}
+	// The state follows the command name, which is in parentheses.
+	stat := string(b)
+	i := strings.LastIndexByte(stat, ')')
+	return i == -1 || i+2 >= len(stat) || stat[i+2] != 'Z'
+}
@@ -0,0 +1,88 @@
+//go:build windows
+
+package command
+
+import (
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+)
+
+const (
+	processSetQuota  = 0x0100
+	processTerminate = 0x0001
+)
+
+var (
+	kernel32                     = syscall.NewLazyDLL("kernel32.dll")
+	procCreateJobObjectW         = kernel32.NewProc("CreateJobObjectW")
+	procAssignProcessToJobObject = kernel32.NewProc("AssignProcessToJobObject")
+	procTerminateJobObject       = kernel32.NewProc("TerminateJobObject")
+)
+
+// processGroup manages the job object of a command started using WithProcessGroup.
+// Processes created by a process in a job are also part of the job, so terminating
+// the job terminates all of them.
+type processGroup struct {
+	mu  sync.Mutex // protects job since Cancel is called from a different goroutine
+	job syscall.Handle
+}
+
+// prepare configures cmd so that the job is terminated if the context becomes done.
+func (pg *processGroup) prepare(cmd *exec.Cmd) {
+	cmd.Cancel = func() error {
+		return pg.signal(cmd, os.Kill)
+	}
+}
+
+// signal sends sig to the processes in the job. Only os.Kill is supported, which terminates
+// the job. Other signals are sent to just cmd, see os.Process.Signal.
+func (pg *processGroup) signal(cmd *exec.Cmd, sig os.Signal) error {
+	if sig != os.Kill {
+		return cmd.Process.Signal(sig)
+	}
+	pg.mu.Lock()
+	defer pg.mu.Unlock()
+	if pg.job != 0 {
+		if r, _, _ := procTerminateJobObject.Call(uintptr(pg.job), 1); r != 0 {
+			return nil
+		}
+	}
+	// Fall back to killing just the command.
+	return cmd.Process.Kill()
+}
+
+// started assigns cmd to a new job object. This is best effort, processes started by cmd
+// before it is assigned are not part of the job. If the job object cannot be created,
+// only cmd is killed when the context becomes done.
+func (pg *processGroup) started(cmd *exec.Cmd) {
+	r, _, _ := procCreateJobObjectW.Call(0, 0)
+	if r == 0 {
+		return
+	}
+	job := syscall.Handle(r)
+	h, err := syscall.OpenProcess(processSetQuota|processTerminate, false, uint32(cmd.Process.Pid))
+	if err != nil {
+		syscall.CloseHandle(job)
+		return
+	}
+	defer syscall.CloseHandle(h)
+	if r, _, _ := procAssignProcessToJobObject.Call(uintptr(job), uintptr(h)); r == 0 {
+		syscall.CloseHandle(job)
+		return
+	}
+	pg.mu.Lock()
+	pg.job = job
+	pg.mu.Unlock()
+}
+
+// close releases the job object.
+func (pg *processGroup) close() {
+	pg.mu.Lock()
+	defer pg.mu.Unlock()
+	if pg.job != 0 {
+		syscall.CloseHandle(pg.job)
+		pg.job = 0
+	}
+}
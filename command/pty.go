@@ -0,0 +1,147 @@
+package command
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// ErrPTYUnsupported is returned by Exec if WithPTY is used on a platform
+// that does not support pseudo-terminals.
+var ErrPTYUnsupported = errors.New("pty unsupported")
+
+// WithPTY runs the command in a pseudo-terminal (PTY), so that it behaves like it does when
+// run directly in a terminal. This is needed for interactive programs that require a terminal,
+// for example to prompt for a password, and for programs that only show progress and colors
+// when writing to a terminal.
+//
+// The program reads from the reader set by WithStdin, and everything it writes to either
+// stdout or stderr is combined and written to the writer set by WithStdout, as well as to
+// the buffer set by CaptureStdout and the line function set by WithLineFunc. WithStderr and
+// CaptureStderr are not used. Note that the terminal translates newlines to \r\n.
+//
+// Input is copied to the terminal as it becomes available. If the reader is a file, like
+// os.Stdin, nothing is read from it once the program exits, so input meant for the current
+// process is not lost. Other readers are read from until the next read after the program has
+// exited returns, and anything read then is discarded.
+//
+// The size of the terminal is the size of os.Stdout if it is a terminal, otherwise it is
+// 80 columns by 24 rows. The terminal of the current process is not put in raw mode.
+// PTYs are supported on Linux and macOS, on other platforms Exec returns an error
+// wrapping ErrPTYUnsupported.
+func WithPTY() Option {
+	return func(c *Command) {
+		c.pty = true
+	}
+}
+
+// pty relays the input and output of a process running in a pseudo-terminal.
+type pty struct {
+	master *os.File
+	slave  *os.File
+	stdin  io.Reader
+	out    io.Writer
+	done   chan struct{}
+
+	stop      chan struct{} // closed to stop copying stdin
+	stdinDone chan struct{} // closed once copying stdin has stopped
+	waitStdin bool          // whether stdinDone is closed soon after stop
+}
+
+// newPTY opens a pseudo-terminal and configures cmd to run in it.
+func newPTY(cmd *exec.Cmd, stdin io.Reader, out io.Writer) (*pty, error) {
+	master, slave, err := openPTY()
+	if err != nil {
+		return nil, err
+	}
+	if err := setTerminalSize(master, os.Stdout); err != nil {
+		master.Close()
+		slave.Close()
+		return nil, err
+	}
+	cmd.Stdin = slave
+	cmd.Stdout = slave
+	cmd.Stderr = slave
+	setControllingTerminal(cmd)
+	return &pty{master: master, slave: slave, stdin: stdin, out: out, done: make(chan struct{})}, nil
+}
+
+// started starts relaying input and output after the process has been started.
+func (p *pty) started() {
+	// The process has its own copy of the slave, close ours so that reading from the master
+	// fails once the process and any children have exited.
+	p.slave.Close()
+	go func() {
+		defer close(p.done)
+		io.Copy(p.out, p.master)
+	}()
+	if p.stdin != nil {
+		readable := inputReadable(p.stdin)
+		p.stop = make(chan struct{})
+		p.stdinDone = make(chan struct{})
+		// Only wait for copying to stop if it can be interrupted,
+		// otherwise reading from stdin can block forever.
+		p.waitStdin = readable != nil
+		go func() {
+			defer close(p.stdinDone)
+			copyUntil(p.master, p.stdin, p.stop, readable)
+		}()
+	}
+}
+
+// wait waits for all output to be relayed after the process has exited.
+func (p *pty) wait() {
+	// Don't wait forever if the process started a background process that outlives it.
+	select {
+	case <-p.done:
+	case <-time.After(waitDelay):
+	}
+	p.master.Close()
+	<-p.done
+}
+
+// close stops copying stdin and releases the pseudo-terminal.
+func (p *pty) close() {
+	if p.stop != nil {
+		close(p.stop)
+		if p.waitStdin {
+			<-p.stdinDone
+		}
+	}
+	p.master.Close()
+	p.slave.Close()
+}
+
+// copyUntil copies src to dst until stop is closed. If readable is not nil,
+// it is used to wait for src to be readable before each read, so that src
+// is not read from once stop is closed.
+func copyUntil(dst io.Writer, src io.Reader, stop <-chan struct{}, readable func() bool) {
+	buf := make([]byte, 32*1024)
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		if readable != nil && !readable() {
+			continue
+		}
+		n, err := src.Read(buf)
+		select {
+		case <-stop:
+			// The process has exited, so the input has nowhere to go.
+			return
+		default:
+		}
+		if n > 0 {
+			if _, err := dst.Write(buf[:n]); err != nil {
+				return
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package command
+
+import (
+	"bytes"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// openPTY opens a new pseudo-terminal and returns its master and slave.
+func openPTY() (master, slave *os.File, err error) {
+	master, err = os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	// These are the equivalent of grantpt, unlockpt and ptsname.
+	var name [128]byte
+	for _, req := range []struct {
+		req uintptr
+		arg unsafe.Pointer
+	}{
+		{syscall.TIOCPTYGRANT, nil},
+		{syscall.TIOCPTYUNLK, nil},
+		{syscall.TIOCPTYGNAME, unsafe.Pointer(&name)},
+	} {
+		if err := ioctl(master, req.req, req.arg); err != nil {
+			master.Close()
+			return nil, nil, err
+		}
+	}
+	if i := bytes.IndexByte(name[:], 0); i != -1 {
+		slave, err = os.OpenFile(string(name[:i]), os.O_RDWR|syscall.O_NOCTTY, 0)
+	} else {
+		err = syscall.EINVAL
+	}
+	if err != nil {
+		master.Close()
+		return nil, nil, err
+	}
+	return master, slave, nil
+}
+
+// selectRead waits until a file descriptor in r is readable or timeout has passed, see select(2).
+func selectRead(nfd int, r *syscall.FdSet, timeout *syscall.Timeval) error {
+	return syscall.Select(nfd, r, nil, nil, timeout)
+}
@@ -0,0 +1,24 @@
+//go:build !linux && !darwin
+
+package command
+
+import (
+	"io"
+	"os"
+	"os/exec"
+)
+
+// openPTY is not supported on this platform.
+func openPTY() (master, slave *os.File, err error) {
+	return nil, nil, ErrPTYUnsupported
+}
+
+func setControllingTerminal(cmd *exec.Cmd) {}
+
+func setTerminalSize(pty, f *os.File) error {
+	return nil
+}
+
+func inputReadable(src io.Reader) func() bool {
+	return nil
+}
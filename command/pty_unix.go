@@ -0,0 +1,97 @@
+//go:build linux || darwin
+
+package command
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// setControllingTerminal configures cmd to start a new session with its
+// stdin, which is the PTY, as the controlling terminal.
+func setControllingTerminal(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setsid = true
+	cmd.SysProcAttr.Setctty = true
+	cmd.SysProcAttr.Ctty = 0
+}
+
+type winsize struct {
+	row, col, xpixel, ypixel uint16
+}
+
+// setTerminalSize sets the size of the PTY to the size of the terminal f,
+// or to 80x24 if f is not a terminal.
+func setTerminalSize(pty, f *os.File) error {
+	ws := winsize{row: 24, col: 80}
+	var fws winsize
+	if err := ioctl(f, syscall.TIOCGWINSZ, unsafe.Pointer(&fws)); err == nil && fws.row > 0 && fws.col > 0 {
+		ws = fws
+	}
+	return ioctl(pty, syscall.TIOCSWINSZ, unsafe.Pointer(&ws))
+}
+
+// ioctl performs the ioctl request req on f. SyscallConn is used instead of Fd,
+// since Fd puts f in blocking mode, and then closing it does not interrupt reads.
+func ioctl(f *os.File, req uintptr, arg unsafe.Pointer) error {
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var errno syscall.Errno
+	err = rc.Control(func(fd uintptr) {
+		_, _, errno = syscall.Syscall(syscall.SYS_IOCTL, fd, req, uintptr(arg))
+	})
+	if err != nil {
+		return err
+	}
+	if errno != 0 {
+		return os.NewSyscallError("ioctl", errno)
+	}
+	return nil
+}
+
+// inputPollInterval is how long the function returned by inputReadable waits for input,
+// so that the copying of input can be stopped soon after the process exits.
+const inputPollInterval = 50 * time.Millisecond
+
+// inputReadable returns a function that waits up to inputPollInterval for src to be readable,
+// if src is a file that supports select(2). Otherwise, it returns nil.
+func inputReadable(src io.Reader) func() bool {
+	f, ok := src.(*os.File)
+	if !ok {
+		return nil
+	}
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return nil
+	}
+	var fd int
+	if err := rc.Control(func(sysfd uintptr) { fd = int(sysfd) }); err != nil {
+		return nil
+	}
+	var set syscall.FdSet
+	bits := int(unsafe.Sizeof(set.Bits[0]) * 8)
+	if fd < 0 || fd >= len(set.Bits)*bits {
+		return nil
+	}
+	return func() bool {
+		var ready bool
+		rc.Control(func(sysfd uintptr) {
+			set = syscall.FdSet{}
+			set.Bits[fd/bits] |= 1 << (fd % bits)
+			tv := syscall.NsecToTimeval(int64(inputPollInterval))
+			// Errors like EINTR are treated like a timeout, the caller checks again.
+			if err := selectRead(fd+1, &set, &tv); err == nil {
+				ready = set.Bits[fd/bits]&(1<<(fd%bits)) != 0
+			}
+		})
+		return ready
+	}
+}
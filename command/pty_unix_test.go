@@ -0,0 +1,105 @@
+//go:build linux || darwin
+
+package command_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TouchBistro/goutils/command"
+	"github.com/TouchBistro/goutils/errors"
+)
+
+func TestWithPTY(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		opts   []command.Option
+		want   string
+	}{
+		{"terminal", "test -t 0 && test -t 1 && test -t 2 && echo tty", nil, "tty\r\n"},
+		{"stderr", "echo out; echo err >&2", nil, "out\r\nerr\r\n"},
+		{"size", "stty size", nil, "24 80\r\n"},
+		{
+			"stdin",
+			`read line; echo "got $line"`,
+			[]command.Option{command.WithStdin(strings.NewReader("hello\n"))},
+			// The terminal echoes the input.
+			"hello\r\ngot hello\r\n",
+		},
+		{"process group", "echo tty", []command.Option{command.WithProcessGroup()}, "tty\r\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			cmd := command.New(append(tt.opts, command.WithPTY(), command.CaptureStdout(buf))...)
+			if err := cmd.Exec(context.Background(), "sh", "-c", tt.script); err != nil {
+				t.Fatalf("got err %v, want nil", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithPTYLines(t *testing.T) {
+	var lines []string
+	cmd := command.New(command.WithPTY(), command.WithLineFunc(func(s command.Stream, line string) {
+		lines = append(lines, s.String()+": "+line)
+	}))
+	if err := cmd.Exec(context.Background(), "sh", "-c", "echo a; echo b >&2"); err != nil {
+		t.Fatalf("got err %v, want nil", err)
+	}
+	want := []string{"stdout: a", "stdout: b"}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got lines %q, want %q", lines, want)
+	}
+}
+
+func TestWithPTYError(t *testing.T) {
+	cmd := command.New(command.WithPTY())
+	err := cmd.Exec(context.Background(), "sh", "-c", "echo oops; exit 2")
+	var cmdErr *command.Error
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("got err %v, want *command.Error", err)
+	}
+	if cmdErr.ExitCode != 2 {
+		t.Errorf("got exit code %d, want 2", cmdErr.ExitCode)
+	}
+	if cmdErr.Stderr != "oops\r\n" {
+		t.Errorf("got stderr %q, want %q", cmdErr.Stderr, "oops\r\n")
+	}
+}
+
+func TestWithPTYStdinAfterExit(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+	defer r.Close()
+	defer w.Close()
+	cmd := command.New(command.WithPTY(), command.WithStdin(r), command.CaptureStdout(&bytes.Buffer{}))
+	if err := cmd.Exec(context.Background(), "sh", "-c", "echo done"); err != nil {
+		t.Fatalf("got err %v, want nil", err)
+	}
+
+	// Input written after the program exits must not be read by the PTY.
+	if _, err := io.WriteString(w, "next\n"); err != nil {
+		t.Fatalf("failed to write to pipe: %v", err)
+	}
+	r.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 16)
+	n, err := r.Read(buf)
+	if err != nil {
+		t.Fatalf("failed to read from pipe: %v", err)
+	}
+	if got := string(buf[:n]); got != "next\n" {
+		t.Errorf("got %q, want %q", got, "next\n")
+	}
+}
@@ -0,0 +1,122 @@
+package command
+
+import (
+	"context"
+	"math"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/TouchBistro/goutils/errors"
+)
+
+// RetryPolicy determines when and how often RunWithRetry retries a command.
+// The zero value retries any failure 3 times with exponential backoff starting at 1 second.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times the command is run, including
+	// the first attempt. If it is zero or negative, 3 attempts are made.
+	MaxAttempts int
+	// Delay is how long to wait before the first retry. It defaults to 1 second.
+	Delay time.Duration
+	// MaxDelay caps the delay between retries. If it is zero, there is no cap.
+	MaxDelay time.Duration
+	// Multiplier is the factor the delay is multiplied by after each retry. It defaults to 2.
+	Multiplier float64
+
+	// ExitCodes are the exit codes that indicate a transient failure.
+	ExitCodes []int
+	// StderrPatterns match stderr output that indicates a transient failure, for example
+	// "connection reset". Only the end of stderr is matched, see Error.Stderr.
+	StderrPatterns []*regexp.Regexp
+}
+
+// shouldRetry reports whether the command that failed with err should be retried.
+func (p RetryPolicy) shouldRetry(err error) bool {
+	if errors.IsRetryable(err) {
+		return true
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		// The attempt was killed because of WithTimeout, since RunWithRetry doesn't retry once
+		// ctx is done. A command that hangs, for example on a network call, may succeed if run
+		// again, unless the time was limited using WithBudget and it has been used up.
+		return !errors.Is(err, ErrBudgetExceeded)
+	}
+	var cmdErr *Error
+	if !errors.As(err, &cmdErr) || cmdErr.ExitCode == -1 {
+		// The command didn't start or didn't exit normally, running it again won't help.
+		return false
+	}
+	if len(p.ExitCodes) == 0 && len(p.StderrPatterns) == 0 {
+		return true
+	}
+	if slices.Contains(p.ExitCodes, cmdErr.ExitCode) {
+		return true
+	}
+	for _, re := range p.StderrPatterns {
+		if re.MatchString(cmdErr.Stderr) {
+			return true
+		}
+	}
+	return false
+}
+
+// nextDelay returns the delay after delay, multiplied by multiplier and capped by MaxDelay.
+func (p RetryPolicy) nextDelay(delay time.Duration, multiplier float64) time.Duration {
+	next := float64(delay) * multiplier
+	if p.MaxDelay > 0 && next > float64(p.MaxDelay) {
+		return p.MaxDelay
+	}
+	// Saturate instead of overflowing to a negative delay, which would make
+	// all remaining retries happen immediately.
+	if next >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(next)
+}
+
+// RunWithRetry runs cmd and retries it with exponential backoff if it fails with a transient
+// failure, as determined by policy. This is useful for commands that depend on the network
+// and may fail intermittently, like installing packages.
+//
+// A failure is considered transient if the command exited with one of policy.ExitCodes or
+// its stderr matches one of policy.StderrPatterns. If neither are set, any non-zero exit code
+// is considered transient. Errors that are marked as retryable using errors.MarkRetryable are
+// always retried. Attempts that were killed because they took longer than the timeout set
+// with WithTimeout are also retried, but not if the Budget set with WithBudget was used up.
+// A command that cannot be started, for example because it does not exist, is never retried.
+//
+// If ctx becomes done while waiting to retry, the last error is returned. Otherwise,
+// the error from the last attempt is returned.
+func RunWithRetry(ctx context.Context, cmd *Cmd, policy RetryPolicy) error {
+	attempts := policy.MaxAttempts
+	if attempts <= 0 {
+		attempts = 3
+	}
+	delay := policy.Delay
+	if delay <= 0 {
+		delay = time.Second
+	}
+	multiplier := policy.Multiplier
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+
+	var err error
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			t := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return err
+			case <-t.C:
+			}
+			delay = policy.nextDelay(delay, multiplier)
+		}
+		err = cmd.Run(ctx)
+		if err == nil || ctx.Err() != nil || !policy.shouldRetry(err) {
+			return err
+		}
+	}
+	return err
+}
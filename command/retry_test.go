@@ -0,0 +1,130 @@
+package command_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TouchBistro/goutils/command"
+)
+
+// flakyCmd returns a command that fails with exitCode and prints stderr until it has been
+// run succeedAfter times. It also returns a function that reports how many times it was run.
+func flakyCmd(t *testing.T, succeedAfter, exitCode int, stderr string) (*command.Cmd, func() int) {
+	t.Helper()
+	counter := filepath.Join(t.TempDir(), "count")
+	script := `n=$(cat "$1" 2>/dev/null || echo 0); n=$((n+1)); echo $n > "$1"; ` +
+		`if [ $n -lt $2 ]; then echo "$4" >&2; exit $3; fi`
+	cmd := &command.Cmd{
+		Name: "sh",
+		Args: []string{"-c", script, "sh", counter, strconv.Itoa(succeedAfter), strconv.Itoa(exitCode), stderr},
+	}
+	count := func() int {
+		b, err := os.ReadFile(counter)
+		if err != nil {
+			return 0
+		}
+		n, _ := strconv.Atoi(strings.TrimSpace(string(b)))
+		return n
+	}
+	return cmd, count
+}
+
+func TestRunWithRetry(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      command.RetryPolicy
+		exitCode    int
+		stderr      string
+		wantErr     bool
+		wantAttempt int
+	}{
+		{"retries any failure", command.RetryPolicy{}, 1, "", false, 3},
+		{"max attempts", command.RetryPolicy{MaxAttempts: 2}, 1, "", true, 2},
+		{"matching exit code", command.RetryPolicy{ExitCodes: []int{75}}, 75, "", false, 3},
+		{"other exit code", command.RetryPolicy{ExitCodes: []int{75}}, 1, "", true, 1},
+		{"matching stderr", command.RetryPolicy{StderrPatterns: []*regexp.Regexp{regexp.MustCompile(`connection (reset|refused)`)}}, 1, "error: connection reset by peer", false, 3},
+		{"other stderr", command.RetryPolicy{StderrPatterns: []*regexp.Regexp{regexp.MustCompile(`connection reset`)}}, 1, "error: not found", true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, count := flakyCmd(t, 3, tt.exitCode, tt.stderr)
+			tt.policy.Delay = time.Millisecond
+			err := command.RunWithRetry(context.Background(), cmd, tt.policy)
+			if tt.wantErr && err == nil {
+				t.Error("want non-nil error, got nil")
+			} else if !tt.wantErr && err != nil {
+				t.Errorf("want nil error, got %v", err)
+			}
+			if got := count(); got != tt.wantAttempt {
+				t.Errorf("got %d attempts, want %d", got, tt.wantAttempt)
+			}
+		})
+	}
+}
+
+func TestRunWithRetryTimeout(t *testing.T) {
+	// The first attempt hangs and is killed by the timeout, the second one succeeds.
+	counter := filepath.Join(t.TempDir(), "count")
+	script := `n=$(cat "$1" 2>/dev/null || echo 0); n=$((n+1)); echo $n > "$1"; if [ $n -lt 2 ]; then exec sleep 10; fi`
+	cmd := &command.Cmd{
+		Name: "sh",
+		Args: []string{"-c", script, "sh", counter},
+		Opts: []command.Option{command.WithTimeout(200 * time.Millisecond)},
+	}
+	err := command.RunWithRetry(context.Background(), cmd, command.RetryPolicy{Delay: time.Millisecond})
+	if err != nil {
+		t.Errorf("want nil error, got %v", err)
+	}
+	if b, _ := os.ReadFile(counter); strings.TrimSpace(string(b)) != "2" {
+		t.Errorf("got %q attempts, want 2", b)
+	}
+}
+
+func TestRunWithRetryBudgetExceeded(t *testing.T) {
+	cmd := &command.Cmd{
+		Name: "sleep",
+		Args: []string{"10"},
+		Opts: []command.Option{command.WithBudget(command.NewBudget(100 * time.Millisecond))},
+	}
+	start := time.Now()
+	err := command.RunWithRetry(context.Background(), cmd, command.RetryPolicy{Delay: time.Hour})
+	if !errors.Is(err, command.ErrBudgetExceeded) {
+		t.Errorf("got error %v, want ErrBudgetExceeded", err)
+	}
+	if d := time.Since(start); d > time.Minute {
+		t.Errorf("took %s, want no retries", d)
+	}
+}
+
+func TestRunWithRetryNotStarted(t *testing.T) {
+	cmd := &command.Cmd{Name: "thiscannotpossiblyexist1234"}
+	start := time.Now()
+	err := command.RunWithRetry(context.Background(), cmd, command.RetryPolicy{Delay: time.Hour})
+	var cmdErr *command.Error
+	if !errors.As(err, &cmdErr) {
+		t.Errorf("got error %v, want *command.Error", err)
+	}
+	if d := time.Since(start); d > time.Minute {
+		t.Errorf("took %s, want no retries", d)
+	}
+}
+
+func TestRunWithRetryContextDone(t *testing.T) {
+	cmd, count := flakyCmd(t, 3, 1, "")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := command.RunWithRetry(ctx, cmd, command.RetryPolicy{Delay: time.Hour})
+	if err == nil {
+		t.Error("want non-nil error, got nil")
+	}
+	if got := count(); got != 1 {
+		t.Errorf("got %d attempts, want 1", got)
+	}
+}
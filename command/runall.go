@@ -0,0 +1,48 @@
+package command
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TouchBistro/goutils/async"
+	"github.com/TouchBistro/goutils/progress"
+)
+
+// RunAll runs cmds concurrently, with at most concurrency commands running at the same time.
+// If concurrency is zero or negative, progress.DefaultConcurrency is used.
+// RunAll blocks until all commands have completed. Use ctx to cancel any running commands.
+//
+// If ctx contains a progress.Tracker, such as one created by spinner.NewTracker, it is used to
+// display progress. The count is incremented as each command completes, and the message is
+// updated with the command line of each command as it starts.
+//
+// All commands run to completion even if some of them fail. If any commands fail, the returned
+// error is an errors.List containing the error of each failed command, in the same order as cmds.
+func RunAll(ctx context.Context, cmds []*Cmd, concurrency int) error {
+	// No-op if there are no commands since we have nothing to run.
+	if len(cmds) == 0 {
+		return nil
+	}
+	if concurrency < 1 {
+		concurrency = progress.DefaultConcurrency()
+	}
+
+	tracker := progress.TrackerFromContext(ctx)
+	tracker.Start(fmt.Sprintf("Running %d commands", len(cmds)), len(cmds))
+	defer tracker.Stop()
+
+	var group async.Group[struct{}]
+	group.SetLocking(false)
+	group.SetMaxGoroutines(concurrency)
+	for _, cmd := range cmds {
+		cmd := cmd // https://go.dev/doc/faq#closures_and_goroutines
+		group.Queue(func(ctx context.Context) (struct{}, error) {
+			tracker.UpdateMessage("Running " + cmd.String())
+			err := cmd.Run(ctx)
+			tracker.Inc()
+			return struct{}{}, err
+		})
+	}
+	_, err := group.Wait(ctx)
+	return err
+}
@@ -0,0 +1,118 @@
+package command_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/TouchBistro/goutils/command"
+	"github.com/TouchBistro/goutils/errors"
+	"github.com/TouchBistro/goutils/progress"
+)
+
+// countTracker is a progress.Tracker that records the progress reported to it.
+type countTracker struct {
+	progress.NoopTracker
+
+	mu       sync.Mutex
+	count    int
+	total    int
+	messages []string
+}
+
+func (t *countTracker) Start(msg string, count int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.total = count
+}
+
+func (t *countTracker) Inc() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.count++
+}
+
+func (t *countTracker) UpdateMessage(msg string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.messages = append(t.messages, msg)
+}
+
+func TestRunAll(t *testing.T) {
+	dir := t.TempDir()
+	var cmds []*command.Cmd
+	for _, name := range []string{"a", "b", "c", "d"} {
+		cmds = append(cmds, &command.Cmd{Name: "touch", Args: []string{filepath.Join(dir, name)}})
+	}
+	tracker := &countTracker{}
+	ctx := progress.ContextWithTracker(context.Background(), tracker)
+	if err := command.RunAll(ctx, cmds, 2); err != nil {
+		t.Fatalf("got err %v, want nil", err)
+	}
+	for _, name := range []string{"a", "b", "c", "d"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("want file %s to exist, got err %v", name, err)
+		}
+	}
+	if tracker.total != 4 || tracker.count != 4 {
+		t.Errorf("got progress %d/%d, want 4/4", tracker.count, tracker.total)
+	}
+	want := "Running touch " + filepath.Join(dir, "a")
+	if !slices.Contains(tracker.messages, want) {
+		t.Errorf("got messages %q, want them to contain %q", tracker.messages, want)
+	}
+}
+
+func TestRunAllConcurrency(t *testing.T) {
+	// Each command records that it is running, waits for others to start, and checks
+	// how many are running at once using files in dir.
+	dir := t.TempDir()
+	script := `touch "$DIR/$ID"; sleep 0.2; n=$(ls "$DIR" | wc -l); rm "$DIR/$ID"; [ "$n" -le 2 ]`
+	var cmds []*command.Cmd
+	for _, id := range []string{"1", "2", "3", "4", "5"} {
+		cmds = append(cmds, &command.Cmd{
+			Name: "sh",
+			Args: []string{"-c", script},
+			Opts: []command.Option{command.WithExtraEnv(map[string]string{"DIR": dir, "ID": id})},
+		})
+	}
+	if err := command.RunAll(context.Background(), cmds, 2); err != nil {
+		t.Errorf("got err %v, want nil", err)
+	}
+}
+
+func TestRunAllErrors(t *testing.T) {
+	cmds := []*command.Cmd{
+		{Name: "sh", Args: []string{"-c", "exit 1"}},
+		{Name: "true"},
+		{Name: "sh", Args: []string{"-c", "exit 2"}},
+	}
+	tracker := &countTracker{}
+	ctx := progress.ContextWithTracker(context.Background(), tracker)
+	err := command.RunAll(ctx, cmds, 0)
+	var errs errors.List
+	if !errors.As(err, &errs) {
+		t.Fatalf("got err %v, want errors.List", err)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("got %d errors, want 2", len(errs))
+	}
+	for i, want := range []int{1, 2} {
+		var cmdErr *command.Error
+		if !errors.As(errs[i], &cmdErr) || cmdErr.ExitCode != want {
+			t.Errorf("got error %v at index %d, want exit code %d", errs[i], i, want)
+		}
+	}
+	if tracker.count != 3 {
+		t.Errorf("got count %d, want 3", tracker.count)
+	}
+}
+
+func TestRunAllEmpty(t *testing.T) {
+	if err := command.RunAll(context.Background(), nil, 2); err != nil {
+		t.Errorf("got err %v, want nil", err)
+	}
+}
@@ -0,0 +1,170 @@
+package command
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+
+	"github.com/TouchBistro/goutils/text"
+)
+
+// Shell identifies a shell that can be used by RunShell, see WithShell.
+type Shell int
+
+const (
+	// ShellDefault is the platform shell, which is ShellSh on Unix and ShellCmd on Windows.
+	ShellDefault Shell = iota
+	// ShellSh is a POSIX shell. On Unix /bin/sh is used, on Windows sh is looked up
+	// in the PATH, for example the one installed by Git for Windows.
+	ShellSh
+	// ShellCmd is cmd.exe. It is only available on Windows.
+	ShellCmd
+	// ShellPowerShell is PowerShell. On Windows powershell.exe is used,
+	// on other platforms pwsh is used.
+	ShellPowerShell
+)
+
+func (s Shell) String() string {
+	switch s.resolve() {
+	case ShellCmd:
+		return "cmd"
+	case ShellPowerShell:
+		return "powershell"
+	}
+	return "sh"
+}
+
+// resolve returns the shell s refers to, which is only different for ShellDefault.
+func (s Shell) resolve() Shell {
+	if s == ShellDefault {
+		return defaultShell
+	}
+	return s
+}
+
+// Quote quotes each argument in args so that it is interpreted as a single word by s,
+// and joins them with spaces.
+//
+// For ShellSh, arguments are quoted for a POSIX shell, see text.ShellQuote.
+// For ShellCmd, arguments are quoted so that special characters like & and | are not
+// interpreted. Note that cmd.exe still expands %VAR% in quoted arguments, so untrusted values
+// should be passed as environment variables instead. For ShellPowerShell, arguments are single
+// quoted if needed. Note that in PowerShell, a quoted program must be run using the call operator &.
+func (s Shell) Quote(args ...string) string {
+	var sb strings.Builder
+	for i, arg := range args {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		switch s.resolve() {
+		case ShellCmd:
+			writeCmdQuoted(&sb, arg)
+		case ShellPowerShell:
+			writePowerShellArg(&sb, arg)
+		default:
+			sb.WriteString(text.ShellQuote([]string{arg}))
+		}
+	}
+	return sb.String()
+}
+
+// WithShell sets the shell used by RunShell. By default ShellDefault is used.
+// WithShell has no effect on Exec.
+func WithShell(s Shell) Option {
+	return func(c *Command) {
+		c.shell = s
+	}
+}
+
+// RunShell runs script using a shell, which is sh on Unix and cmd.exe on Windows unless another
+// shell is set using WithShell. This is useful for running scripts that use shell features like
+// pipes and redirects, for example scripts defined in config files. See Command.Exec for details.
+//
+// The script is interpreted by the shell, so it must never be built by formatting values
+// that are not trusted into it, since they could run arbitrary commands. Instead, pass them
+// as environment variables using WithExtraEnv and reference them in the script, quoted
+// like "$NAME" in sh. If that is not possible, quote each value using Shell.Quote.
+// For the same reason, WithExpandVariables does not expand variables in the script, only
+// in the values of environment variables. Prefer Exec when a shell is not needed, since
+// arguments are then never interpreted.
+func RunShell(ctx context.Context, script string, opts ...Option) error {
+	c := New(opts...)
+	c.keepArgs = true
+	name, args, opt := shellCommand(c.shell.resolve(), script)
+	if opt != nil {
+		opt(c)
+	}
+	return c.exec(ctx, "command.RunShell", name, args)
+}
+
+// Quote quotes each argument in args so that it is interpreted as a single word by the
+// platform shell used by RunShell, and joins them with spaces. It is the same as
+// ShellDefault.Quote, see Shell.Quote for details.
+func Quote(args ...string) string {
+	return ShellDefault.Quote(args...)
+}
+
+// writeCmdQuoted quotes arg so that cmd.exe does not interpret special characters in it,
+// and programs that parse their command line using CommandLineToArgvW receive it as is.
+func writeCmdQuoted(sb *strings.Builder, arg string) {
+	if arg == "" {
+		sb.WriteString(`""`)
+		return
+	}
+	if !strings.ContainsAny(arg, " \t\"&|<>^()%!,;=") {
+		sb.WriteString(arg)
+		return
+	}
+	sb.WriteByte('"')
+	backslashes := 0
+	for i := 0; i < len(arg); i++ {
+		c := arg[i]
+		switch c {
+		case '\\':
+			backslashes++
+			continue
+		case '"':
+			// Backslashes before a quote must be escaped. The quote is escaped by doubling it,
+			// unlike \" this does not confuse cmd.exe about whether it is in a quoted string.
+			sb.WriteString(strings.Repeat(`\`, 2*backslashes))
+			sb.WriteString(`""`)
+		default:
+			sb.WriteString(strings.Repeat(`\`, backslashes))
+			sb.WriteByte(c)
+		}
+		backslashes = 0
+	}
+	// Backslashes before the closing quote must be escaped.
+	sb.WriteString(strings.Repeat(`\`, 2*backslashes))
+	sb.WriteByte('"')
+}
+
+// writePowerShellArg writes arg to sb, single quoted unless it only contains characters
+// that have no special meaning to PowerShell.
+func writePowerShellArg(sb *strings.Builder, arg string) {
+	safe := arg != ""
+	for i := 0; i < len(arg) && safe; i++ {
+		c := arg[i]
+		safe = 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte("._-/\\:", c) != -1
+	}
+	if safe {
+		sb.WriteString(arg)
+		return
+	}
+	writePowerShellQuoted(sb, arg)
+}
+
+// writePowerShellQuoted writes s to sb as a single quoted PowerShell string.
+func writePowerShellQuoted(sb *strings.Builder, s string) {
+	sb.WriteByte('\'')
+	sb.WriteString(strings.ReplaceAll(s, "'", "''"))
+	sb.WriteByte('\'')
+}
+
+// withPrepare adds a function that is called with the exec.Cmd before it is started.
+// It is used to change how the process is created for special cases.
+func withPrepare(fn func(cmd *exec.Cmd)) Option {
+	return func(c *Command) {
+		c.prepare = append(c.prepare, fn)
+	}
+}
@@ -0,0 +1,25 @@
+//go:build !windows
+
+package command
+
+import "os/exec"
+
+// defaultShell is the shell used for ShellDefault.
+const defaultShell = ShellSh
+
+// shellCommand returns the program and arguments to run script with shell,
+// along with an option needed to run it, if any.
+func shellCommand(shell Shell, script string) (string, []string, Option) {
+	switch shell {
+	case ShellCmd:
+		// This only works if cmd.exe is available, for example under WSL.
+		return "cmd.exe", []string{"/d", "/s", "/c", script}, nil
+	case ShellPowerShell:
+		return "pwsh", []string{"-NoProfile", "-NonInteractive", "-Command", script}, nil
+	}
+	return "/bin/sh", []string{"-c", script}, nil
+}
+
+// preparePlatform makes platform specific changes to cmd before it is started.
+// There are none on this platform.
+func preparePlatform(cmd *exec.Cmd) {}
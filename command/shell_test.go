@@ -0,0 +1,123 @@
+package command_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/TouchBistro/goutils/command"
+)
+
+func TestRunShell(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		opts   []command.Option
+		want   string
+	}{
+		{"pipe", "printf 'b\\na\\n' | sort", nil, "a\nb\n"},
+		{"redirect", "echo out; echo err >&2", nil, "out\n"},
+		{
+			"env",
+			`printf '%s\n' "$INPUT"`,
+			[]command.Option{command.WithExtraEnv(map[string]string{"INPUT": "$(echo no); rm -rf"})},
+			"$(echo no); rm -rf\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			err := command.RunShell(context.Background(), tt.script, append(tt.opts, command.CaptureStdout(buf))...)
+			if err != nil {
+				t.Fatalf("got err %v, want nil", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunShellExpandVariables(t *testing.T) {
+	lookup := func(name string) (string, bool) {
+		if name == "NAME" {
+			return "x; echo INJECTED", true
+		}
+		return "", false
+	}
+	buf := &bytes.Buffer{}
+	err := command.RunShell(
+		context.Background(),
+		`echo "$GREETING"; echo ${NAME}`,
+		command.WithExpandVariables(lookup),
+		command.WithEnv(map[string]string{"GREETING": "hello ${NAME}"}),
+		command.CaptureStdout(buf),
+	)
+	if err != nil {
+		t.Fatalf("got err %v, want nil", err)
+	}
+	// The value is only expanded into the environment, the script is run as is.
+	if got, want := buf.String(), "hello x; echo INJECTED\n\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestRunShellError(t *testing.T) {
+	err := command.RunShell(context.Background(), "echo failed >&2; exit 3", command.WithStderr(&bytes.Buffer{}))
+	var cmdErr *command.Error
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("got err %v, want *command.Error", err)
+	}
+	if cmdErr.ExitCode != 3 {
+		t.Errorf("got exit code %d, want 3", cmdErr.ExitCode)
+	}
+	if cmdErr.Stderr != "failed\n" {
+		t.Errorf("got stderr %q, want %q", cmdErr.Stderr, "failed\n")
+	}
+}
+
+func TestQuote(t *testing.T) {
+	args := []string{"plain", "two words", "it's", "$HOME", "`id`", "a;b|c&d", ""}
+	buf := &bytes.Buffer{}
+	err := command.RunShell(context.Background(), "printf '%s|' "+command.Quote(args...), command.CaptureStdout(buf))
+	if err != nil {
+		t.Fatalf("got err %v, want nil", err)
+	}
+	const want = "plain|two words|it's|$HOME|`id`|a;b|c&d||"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		shell command.Shell
+		args  []string
+		want  string
+	}{
+		{command.ShellSh, []string{"echo", "a b", "it's", ""}, `echo 'a b' 'it'\''s' ''`},
+		{command.ShellCmd, []string{"echo", "a b", "a&b", ""}, `echo "a b" "a&b" ""`},
+		{command.ShellCmd, []string{`C:\Program Files\`, `say "hi"`, `a\"b`}, `"C:\Program Files\\" "say ""hi""" "a\\""b"`},
+		{command.ShellCmd, []string{`C:\tools\app.exe`}, `C:\tools\app.exe`},
+		{command.ShellPowerShell, []string{"echo", "it's", "$HOME", `C:\tools\app.exe`, ""}, `echo 'it''s' '$HOME' C:\tools\app.exe ''`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.shell.String(), func(t *testing.T) {
+			if got := tt.shell.Quote(tt.args...); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithShell(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := command.RunShell(context.Background(), "echo $0", command.WithShell(command.ShellSh), command.CaptureStdout(buf))
+	if err != nil {
+		t.Fatalf("got err %v, want nil", err)
+	}
+	if got := buf.String(); got != "/bin/sh\n" {
+		t.Errorf("got %q, want %q", got, "/bin/sh\n")
+	}
+}
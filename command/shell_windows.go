@@ -0,0 +1,74 @@
+//go:build windows
+
+package command
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// defaultShell is the shell used for ShellDefault.
+const defaultShell = ShellCmd
+
+// shellCommand returns the program and arguments to run script with shell,
+// along with an option needed to run it, if any.
+func shellCommand(shell Shell, script string) (string, []string, Option) {
+	switch shell {
+	case ShellSh:
+		return "sh", []string{"-c", script}, nil
+	case ShellPowerShell:
+		return "powershell.exe", []string{"-NoProfile", "-NonInteractive", "-Command", script}, nil
+	}
+	cmdExe := comspec()
+	return cmdExe, []string{"/d", "/s", "/c", script}, withPrepare(func(cmd *exec.Cmd) {
+		setCmdLine(cmd, cmdExe, script)
+	})
+}
+
+// preparePlatform makes platform specific changes to cmd before it is started.
+//
+// Programs are looked up using PATHEXT, so a name like npm can resolve to npm.cmd.
+// Batch files are always run by cmd.exe, which does not parse arguments the way os/exec
+// escapes them, so they are run explicitly with arguments quoted for cmd.exe.
+func preparePlatform(cmd *exec.Cmd) {
+	if cmd.Err != nil || (cmd.SysProcAttr != nil && cmd.SysProcAttr.CmdLine != "") {
+		return
+	}
+	ext := strings.ToLower(filepath.Ext(cmd.Path))
+	if ext != ".bat" && ext != ".cmd" {
+		return
+	}
+	var sb strings.Builder
+	writeCmdQuoted(&sb, cmd.Path)
+	for _, arg := range cmd.Args[1:] {
+		sb.WriteByte(' ')
+		writeCmdQuoted(&sb, arg)
+	}
+	cmdExe := comspec()
+	cmd.Path = cmdExe
+	setCmdLine(cmd, cmdExe, sb.String())
+}
+
+// setCmdLine sets the command line of cmd to run script with cmdExe.
+func setCmdLine(cmd *exec.Cmd, cmdExe, script string) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	// cmd.exe does not parse quotes the way os/exec escapes arguments. With /s it removes
+	// the outer quotes and runs the rest of the command line as is, so pass the script raw.
+	cmd.SysProcAttr.CmdLine = syscall.EscapeArg(cmdExe) + ` /d /s /c "` + script + `"`
+}
+
+// comspec returns the path of cmd.exe.
+func comspec() string {
+	if s := os.Getenv("COMSPEC"); s != "" {
+		return s
+	}
+	if path, err := exec.LookPath("cmd.exe"); err == nil {
+		return path
+	}
+	return "cmd.exe"
+}
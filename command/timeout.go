@@ -0,0 +1,100 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrBudgetExceeded is returned by Exec if a command used with WithBudget
+// did not complete within the remaining budget.
+var ErrBudgetExceeded = errors.New("budget exceeded")
+
+// WithTimeout kills the command if it does not complete within d. In that case, the error
+// returned by Exec wraps context.DeadlineExceeded. For Start, d limits how long the process
+// can run in the background. Use WithBudget to limit the total time of multiple commands.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Command) {
+		c.timeout = d
+	}
+}
+
+// WithBudget limits the time the command can run to the time remaining in b, and draws the
+// time the command ran for from b once it completes. If the command does not complete within
+// the remaining time, it is killed and the error returned by Exec wraps both ErrBudgetExceeded
+// and context.DeadlineExceeded. If b has already been used up, the command is not run.
+func WithBudget(b *Budget) Option {
+	return func(c *Command) {
+		c.budget = b
+	}
+}
+
+// Budget is an amount of time shared by multiple commands, see WithBudget. It allows a workflow
+// of sequential commands to have a bound on the total time, without having to choose a timeout
+// for each command. For example, to run all steps within 10 minutes:
+//
+//	budget := command.NewBudget(10 * time.Minute)
+//	for _, step := range steps {
+//		step.Opts = append(step.Opts, command.WithBudget(budget))
+//		if err := step.Run(ctx); err != nil {
+//			return err
+//		}
+//	}
+//
+// A Budget is safe to use across multiple goroutines. If commands using the same Budget run
+// concurrently, each is limited by the time remaining when it started, and the time of each
+// is drawn from the budget.
+type Budget struct {
+	mu        sync.Mutex
+	remaining time.Duration
+}
+
+// NewBudget creates a Budget with a total amount of time.
+func NewBudget(total time.Duration) *Budget {
+	return &Budget{remaining: total}
+}
+
+// Remaining returns the time remaining in the budget. It is zero or negative
+// if the budget has been used up.
+func (b *Budget) Remaining() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.remaining
+}
+
+// draw draws d from the remaining time in the budget.
+func (b *Budget) draw(d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.remaining -= d
+}
+
+// withDeadline returns a context with the deadline set by WithTimeout and WithBudget, along
+// with a function that must be called once the command completes. If the budget has been used
+// up, it returns an error wrapping ErrBudgetExceeded.
+func (c *Command) withDeadline(ctx context.Context) (context.Context, func(), error) {
+	done := func() {}
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		done = cancel
+	}
+	if c.budget != nil {
+		remaining := c.budget.Remaining()
+		if remaining <= 0 {
+			done()
+			return nil, nil, ErrBudgetExceeded
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, remaining, ErrBudgetExceeded)
+		start := time.Now()
+		cancelTimeout := done
+		done = func() {
+			c.budget.draw(time.Since(start))
+			cancel()
+			cancelTimeout()
+		}
+	}
+	return ctx, done, nil
+}
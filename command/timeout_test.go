@@ -0,0 +1,74 @@
+package command_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TouchBistro/goutils/command"
+	"github.com/TouchBistro/goutils/errors"
+)
+
+func TestWithTimeout(t *testing.T) {
+	start := time.Now()
+	err := command.New(command.WithTimeout(100*time.Millisecond)).Exec(context.Background(), "sleep", "10")
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("took %s, want the command to be killed after the timeout", d)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got err %v, want %v", err, context.DeadlineExceeded)
+	}
+	if errors.Is(err, command.ErrBudgetExceeded) {
+		t.Errorf("got err %v, want it to not wrap %v", err, command.ErrBudgetExceeded)
+	}
+}
+
+func TestWithTimeoutCompleted(t *testing.T) {
+	if err := command.New(command.WithTimeout(10*time.Second)).Exec(context.Background(), "true"); err != nil {
+		t.Errorf("got err %v, want nil", err)
+	}
+}
+
+func TestWithBudget(t *testing.T) {
+	budget := command.NewBudget(500 * time.Millisecond)
+	cmd := command.New(command.WithBudget(budget))
+	if err := cmd.Exec(context.Background(), "sleep", "0.2"); err != nil {
+		t.Fatalf("got err %v from first command, want nil", err)
+	}
+	if r := budget.Remaining(); r > 300*time.Millisecond || r <= 0 {
+		t.Errorf("got %s remaining, want the time of the first command to be drawn", r)
+	}
+
+	// The second command uses up the rest of the budget.
+	start := time.Now()
+	err := cmd.Exec(context.Background(), "sleep", "10")
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("took %s, want the command to be killed once the budget is used up", d)
+	}
+	if !errors.Is(err, command.ErrBudgetExceeded) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got err %v, want it to wrap %v and %v", err, command.ErrBudgetExceeded, context.DeadlineExceeded)
+	}
+	if r := budget.Remaining(); r > 0 {
+		t.Errorf("got %s remaining, want the budget to be used up", r)
+	}
+
+	// The budget is used up, so the third command is not run.
+	err = cmd.Exec(context.Background(), "true")
+	var cmdErr *command.Error
+	if !errors.As(err, &cmdErr) || cmdErr.ExitCode != -1 || !errors.Is(err, command.ErrBudgetExceeded) {
+		t.Errorf("got err %v, want it to not be run because the budget is used up", err)
+	}
+}
+
+func TestWithBudgetAndTimeout(t *testing.T) {
+	// The shorter of the timeout and the remaining budget applies.
+	budget := command.NewBudget(10 * time.Second)
+	cmd := command.New(command.WithBudget(budget), command.WithTimeout(100*time.Millisecond))
+	err := cmd.Exec(context.Background(), "sleep", "10")
+	if !errors.Is(err, context.DeadlineExceeded) || errors.Is(err, command.ErrBudgetExceeded) {
+		t.Errorf("got err %v, want it to be killed by the timeout", err)
+	}
+	if r := budget.Remaining(); r < 9*time.Second {
+		t.Errorf("got %s remaining, want only the time the command ran to be drawn", r)
+	}
+}
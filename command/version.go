@@ -0,0 +1,107 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrVersionNotFound is returned by Version if the output of a program does not contain a version.
+var ErrVersionNotFound = errors.New("version not found")
+
+// versionRegexp matches versions like 1.2, 2.39.2 or 1.0.0-rc.1+build.5.
+var versionRegexp = regexp.MustCompile(`\d+(?:\.\d+)+(?:-[0-9A-Za-z.-]+)?(?:\+[0-9A-Za-z.-]+)?`)
+
+// Version runs the named program with args and returns the first version found in its output,
+// for example "2.39.2" for "git version 2.39.2". If args is empty, --version is used.
+// Both stdout and stderr are searched, since some programs print their version to stderr.
+//
+// If the program fails to run, the returned error wraps an *Error, see Exec for details.
+// If no version is found, the returned error wraps ErrVersionNotFound.
+// This can be used together with CompareVersions to check that a program meets a minimum version:
+//
+//	v, err := command.Version("docker")
+//	if err != nil {
+//		return err
+//	}
+//	if command.CompareVersions(v, "20.10") < 0 {
+//		return fmt.Errorf("docker %s is too old, at least 20.10 is required", v)
+//	}
+func Version(name string, args ...string) (string, error) {
+	if len(args) == 0 {
+		args = []string{"--version"}
+	}
+	out, err := CombinedOutput(context.Background(), name, args...)
+	if err != nil {
+		return "", err
+	}
+	v := versionRegexp.Find(out)
+	if v == nil {
+		return "", fmt.Errorf("command: failed to get version of '%s': %w", name, ErrVersionNotFound)
+	}
+	return string(v), nil
+}
+
+// CompareVersions compares the versions a and b and returns -1 if a is older than b,
+// 0 if they are the same, and 1 if a is newer than b. A leading v is ignored.
+//
+// Versions are compared by their dot separated numeric components, and missing components
+// are treated as zero, so 1.2 is the same as 1.2.0. A version with a pre-release suffix,
+// like 1.2.0-rc.1, is older than the same version without one. Pre-release suffixes
+// are compared lexically and build metadata is ignored. Components that are not
+// numbers are compared lexically.
+func CompareVersions(a, b string) int {
+	aCore, aPre := splitVersion(a)
+	bCore, bPre := splitVersion(b)
+	aParts := strings.Split(aCore, ".")
+	bParts := strings.Split(bCore, ".")
+	for i := 0; i < max(len(aParts), len(bParts)); i++ {
+		if c := compareComponent(component(aParts, i), component(bParts, i)); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	}
+	return strings.Compare(aPre, bPre)
+}
+
+// splitVersion splits v into its numeric core and pre-release suffix,
+// removing any leading v and build metadata.
+func splitVersion(v string) (core, pre string) {
+	v = strings.TrimPrefix(v, "v")
+	v, _, _ = strings.Cut(v, "+")
+	core, pre, _ = strings.Cut(v, "-")
+	return core, pre
+}
+
+// component returns parts[i], or "0" if i is out of range.
+func component(parts []string, i int) string {
+	if i < len(parts) {
+		return parts[i]
+	}
+	return "0"
+}
+
+func compareComponent(a, b string) int {
+	an, aErr := strconv.Atoi(a)
+	bn, bErr := strconv.Atoi(b)
+	if aErr != nil || bErr != nil {
+		return strings.Compare(a, b)
+	}
+	switch {
+	case an < bn:
+		return -1
+	case an > bn:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,75 @@
+package command_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/TouchBistro/goutils/command"
+)
+
+func TestVersion(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{"git", "git version 2.39.2", "2.39.2"},
+		{"docker", "Docker version 24.0.5, build ced0996", "24.0.5"},
+		{"aws", "aws-cli/2.13.0 Python/3.11.4 Darwin/22.6.0", "2.13.0"},
+		{"go", "go version go1.21.0 linux/amd64", "1.21.0"},
+		{"pre-release", "tool v1.0.0-rc.1+build.5", "1.0.0-rc.1+build.5"},
+		{"stderr", "version 1.2 >&2", "1.2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := command.Version("sh", "-c", "echo "+tt.output)
+			if err != nil {
+				t.Fatalf("want nil error, got %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersionNotFound(t *testing.T) {
+	_, err := command.Version("sh", "-c", "echo no version here")
+	if !errors.Is(err, command.ErrVersionNotFound) {
+		t.Errorf("got error %v, want %v", err, command.ErrVersionNotFound)
+	}
+}
+
+func TestVersionNotInstalled(t *testing.T) {
+	_, err := command.Version("thiscannotpossiblyexist1234")
+	var cmdErr *command.Error
+	if !errors.As(err, &cmdErr) {
+		t.Errorf("got error %v, want *command.Error", err)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"1.2", "1.2.0", 0},
+		{"v1.2.3", "1.2.3", 0},
+		{"1.2.3", "1.2.4", -1},
+		{"1.10.0", "1.9.0", 1},
+		{"2.0", "1.99.99", 1},
+		{"20.10.7", "20.10", 1},
+		{"1.0.0-rc.1", "1.0.0", -1},
+		{"1.0.0", "1.0.0-rc.1", 1},
+		{"1.0.0-alpha", "1.0.0-beta", -1},
+		{"1.0.0+build.1", "1.0.0+build.2", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			if got := command.CompareVersions(tt.a, tt.b); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
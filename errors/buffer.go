@@ -0,0 +1,140 @@
+package errors
+
+import (
+	"io"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+)
+
+// maxPooledBufferSize is the largest buffer that will be returned to bufPool.
+// This prevents holding onto the memory of exceptionally large messages.
+const maxPooledBufferSize = 64 << 10
+
+// bufPool is a pool of buffers used to build error messages.
+// Pooling the buffers means building a message requires a single allocation
+// for the resulting string, regardless of how many errors are in the chain.
+var bufPool = sync.Pool{
+	New: func() any {
+		b := make([]byte, 0, 256)
+		return &b
+	},
+}
+
+func getBuffer() *[]byte {
+	return bufPool.Get().(*[]byte)
+}
+
+func putBuffer(b *[]byte) {
+	if cap(*b) > maxPooledBufferSize {
+		return
+	}
+	*b = (*b)[:0]
+	bufPool.Put(b)
+}
+
+// messageAppender is implemented by the errors in this package that build their
+// message directly into a buffer. It is used as a type constraint instead of
+// an interface value to avoid allocating when converting a List to an interface.
+type messageAppender interface {
+	appendMessage(b []byte) []byte
+}
+
+// message returns the message of e built using a pooled buffer.
+func message[E messageAppender](e E) string {
+	b := getBuffer()
+	*b = e.appendMessage(*b)
+	s := string(*b)
+	putBuffer(b)
+	return s
+}
+
+// writeMessage writes the message of e to w using a pooled buffer,
+// which avoids allocating a string. If quote is true, the message is quoted.
+func writeMessage[E messageAppender](w io.Writer, e E, quote bool) {
+	b := getBuffer()
+	*b = e.appendMessage(*b)
+	if quote {
+		// Quote into the unused capacity of the buffer.
+		n := len(*b)
+		*b = appendQuoted(*b, (*b)[:n])
+		w.Write((*b)[n:])
+	} else {
+		w.Write(*b)
+	}
+	putBuffer(b)
+}
+
+// appendQuoted appends s to b quoted the same way as strconv.Quote and returns the
+// extended buffer. Unlike strconv.AppendQuote, s is a byte slice, so quoting part
+// of b does not require converting it to a string. s may alias b.
+func appendQuoted(b, s []byte) []byte {
+	const hex = "0123456789abcdef"
+	b = append(b, '"')
+	for len(s) > 0 {
+		r, size := utf8.DecodeRune(s)
+		switch {
+		case r == utf8.RuneError && size == 1:
+			b = append(b, '\\', 'x', hex[s[0]>>4], hex[s[0]&0xf])
+		case r == '"' || r == '\\':
+			b = append(b, '\\', byte(r))
+		case strconv.IsPrint(r):
+			b = append(b, s[:size]...)
+		default:
+			// Use the escape sequence strconv uses for the rune, without the quotes.
+			var buf [16]byte
+			q := strconv.AppendQuoteRune(buf[:0], r)
+			b = append(b, q[1:len(q)-1]...)
+		}
+		s = s[size:]
+	}
+	return append(b, '"')
+}
+
+// appendMessage appends the message of e to b and returns the extended buffer.
+func (e *Error) appendMessage(b []byte) []byte {
+	// Only pad relative to what this error has written, b may already
+	// contain the messages of the errors wrapping this one.
+	start := len(b)
+	if e.Kind != nil {
+		b = append(b, e.Kind.Kind()...)
+	}
+	if e.Reason != "" {
+		if len(b) > start {
+			b = append(b, ": "...)
+		}
+		b = append(b, e.Reason...)
+	}
+	if e.Err != nil {
+		if len(b) > start {
+			b = append(b, ": "...)
+		}
+		b = appendMessage(b, e.Err)
+	}
+	return b
+}
+
+// appendMessage appends the message of e to b and returns the extended buffer.
+func (e List) appendMessage(b []byte) []byte {
+	for i, err := range e {
+		if i > 0 {
+			b = append(b, '\n')
+		}
+		b = appendMessage(b, err)
+	}
+	return b
+}
+
+// appendMessage appends the message of err to b and returns the extended buffer.
+// Errors and Lists write directly to b instead of building intermediate strings.
+func appendMessage(b []byte, err error) []byte {
+	switch e := err.(type) {
+	case *Error:
+		return e.appendMessage(b)
+	case List:
+		return e.appendMessage(b)
+	case *retryableError:
+		return appendMessage(b, e.err)
+	}
+	return append(b, err.Error()...)
+}
@@ -0,0 +1,76 @@
+package errors_test
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/TouchBistro/goutils/errors"
+)
+
+func newChain() error {
+	var err error = errors.String("connection refused")
+	err = errors.Wrap(err, errors.Meta{Kind: internal, Reason: "failed to query database", Op: errors.Op("db.Query")})
+	err = errors.Wrap(err, errors.Meta{Reason: "failed to get user", Op: errors.Op("users.Get")})
+	return errors.Wrap(err, errors.Meta{Kind: invalid, Reason: "cannot load profile", Op: errors.Op("api.GetProfile")})
+}
+
+func TestErrorAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocations are not reliable with the race detector")
+	}
+	err := newChain()
+	// Use an interface value so that the conversion doesn't count as an allocation.
+	var list error = errors.List{err, err, err}
+	tests := []struct {
+		name string
+		fn   func()
+		want float64
+	}{
+		{"Error", func() { _ = err.Error() }, 1},
+		{"List.Error", func() { _ = list.Error() }, 1},
+		{"Fprint", func() { fmt.Fprint(io.Discard, err) }, 0},
+		{"Fprintf %s", func() { fmt.Fprintf(io.Discard, "request failed: %s", err) }, 0},
+		{"Fprintf %v", func() { fmt.Fprintf(io.Discard, "%v", err) }, 0},
+		{"Fprintf %q", func() { fmt.Fprintf(io.Discard, "%q", err) }, 0},
+		{"Fprintf %+v", func() { fmt.Fprintf(io.Discard, "%+v", err) }, 0},
+		{"Fprintf %v List", func() { fmt.Fprintf(io.Discard, "%v", list) }, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := testing.AllocsPerRun(100, tt.fn); got > tt.want {
+				t.Errorf("got %v allocs, want at most %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorQuote(t *testing.T) {
+	err := errors.Wrap(errors.String("bad \xff byte, \"quotes\" and \\"), errors.Meta{
+		Reason: "tab\tnewline\n\x00 é \U0001F600 \u2028",
+	})
+	if got, want := fmt.Sprintf("%q", err), strconv.Quote(err.Error()); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func BenchmarkError(b *testing.B) {
+	err := newChain()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = err.Error()
+	}
+}
+
+func BenchmarkFormat(b *testing.B) {
+	err := newChain()
+	for _, format := range []string{"%s", "%q", "%+v"} {
+		b.Run(format, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				fmt.Fprintf(io.Discard, format, err)
+			}
+		})
+	}
+}
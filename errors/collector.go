@@ -0,0 +1,84 @@
+package errors
+
+import (
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// Collector collects errors that occur across multiple goroutines.
+// It is safe to use a Collector concurrently from multiple goroutines.
+//
+// A zero value Collector is ready for use and has no limit on the number of errors.
+//
+// A Collector must not be copied after first use.
+type Collector struct {
+	mu       sync.Mutex
+	errs     List
+	limit    int
+	overflow int
+}
+
+// SetLimit sets the max number of errors that the collector will store.
+// Once the limit is reached, additional errors are not stored and are only counted.
+// This prevents unbounded memory growth when an operation can fail a large number of times.
+// If the value is zero or negative, there will be no limit.
+//
+// SetLimit does not affect errors that have already been added.
+func (c *Collector) SetLimit(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.limit = n
+}
+
+// Add adds err to the collector. If err is nil, Add does nothing.
+// If the collector has reached its limit, err is discarded and counted as overflow.
+func (c *Collector) Add(err error) {
+	if err == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.limit > 0 && len(c.errs) >= c.limit {
+		c.overflow++
+		return
+	}
+	c.errs = append(c.errs, err)
+}
+
+// Overflow returns the number of errors that were discarded because the limit was reached.
+func (c *Collector) Overflow() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.overflow
+}
+
+// Err returns a List containing all errors that have been stored, in the order
+// they were added. If any errors were discarded because the limit was reached,
+// the last error in the List is an Overflow containing the number of discarded errors.
+// If no errors have been added, Err returns nil.
+//
+// The returned List is a copy, so it is not affected by subsequent calls to Add.
+func (c *Collector) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.errs) == 0 {
+		return nil
+	}
+	errs := slices.Clone(c.errs)
+	if c.overflow > 0 {
+		errs = append(errs, Overflow(c.overflow))
+	}
+	return errs
+}
+
+// Overflow is an error that represents a number of errors that were discarded.
+// It is added to the List returned by Collector.Err if the collector's limit was reached.
+type Overflow int
+
+func (e Overflow) Error() string {
+	if e == 1 {
+		return "1 more error omitted"
+	}
+	return strconv.Itoa(int(e)) + " more errors omitted"
+}
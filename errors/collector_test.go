@@ -0,0 +1,62 @@
+package errors_test
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/TouchBistro/goutils/errors"
+)
+
+func TestCollector(t *testing.T) {
+	var c errors.Collector
+	if err := c.Err(); err != nil {
+		t.Errorf("got %v, want nil error", err)
+	}
+
+	const n = 50
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c.Add(nil)
+			c.Add(errors.String("error " + strconv.Itoa(i)))
+		}(i)
+	}
+	wg.Wait()
+
+	err := c.Err()
+	var errs errors.List
+	if !errors.As(err, &errs) {
+		t.Fatalf("want error of type errors.List, got %T", err)
+	}
+	if len(errs) != n {
+		t.Errorf("got %d errors, want %d", len(errs), n)
+	}
+
+	// Make sure the returned list is not affected by new errors.
+	c.Add(errors.String("one more"))
+	if len(errs) != n {
+		t.Errorf("got %d errors after Add, want %d", len(errs), n)
+	}
+}
+
+func TestCollectorLimit(t *testing.T) {
+	var c errors.Collector
+	c.SetLimit(3)
+	for i := 0; i < 10; i++ {
+		c.Add(errors.String("error " + strconv.Itoa(i)))
+	}
+	if got := c.Overflow(); got != 7 {
+		t.Errorf("got overflow %d, want 7", got)
+	}
+	want := "error 0\nerror 1\nerror 2\n7 more errors omitted"
+	if got := c.Err().Error(); got != want {
+		t.Errorf("got\n\t%s\nwant\n\t%s", got, want)
+	}
+	overflow, ok := errors.AsType[errors.Overflow](c.Err())
+	if !ok || overflow != 7 {
+		t.Errorf("got overflow error %v, want 7", overflow)
+	}
+}
@@ -0,0 +1,76 @@
+package errors
+
+import (
+	"context"
+	"sync"
+)
+
+// contextKey is a context key registered with RegisterContextKey.
+type contextKey struct {
+	key   any
+	field string
+}
+
+var (
+	contextKeysMu sync.RWMutex
+	contextKeys   []contextKey
+)
+
+// RegisterContextKey registers a context key whose value should be added to errors
+// by WithContext. The value is stored in the Fields of the error under field.
+// This is useful for correlation data like request IDs or trace IDs.
+//
+// If field is already registered, its key is replaced. It is safe to call
+// RegisterContextKey concurrently, however, keys should generally be
+// registered during program initialization.
+func RegisterContextKey(key any, field string) {
+	contextKeysMu.Lock()
+	defer contextKeysMu.Unlock()
+	for i, ck := range contextKeys {
+		if ck.field == field {
+			contextKeys[i].key = key
+			return
+		}
+	}
+	contextKeys = append(contextKeys, contextKey{key: key, field: field})
+}
+
+// WithContext adds the values of the keys registered with RegisterContextKey
+// from ctx to the Fields of err. This allows errors that are logged far from
+// where the request was handled to still carry correlation data.
+//
+// If err is an *Error, a copy with the fields added is returned. Fields that are
+// already set on err are not overwritten. Otherwise, err is wrapped in an *Error
+// that has the fields. If ctx does not contain any of the registered keys, err
+// is returned as is. If err is nil, WithContext returns nil.
+func WithContext(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	contextKeysMu.RLock()
+	var fields map[string]any
+	for _, ck := range contextKeys {
+		v := ctx.Value(ck.key)
+		if v == nil {
+			continue
+		}
+		if fields == nil {
+			fields = make(map[string]any)
+		}
+		fields[ck.field] = v
+	}
+	contextKeysMu.RUnlock()
+	if fields == nil {
+		return err
+	}
+	e, ok := err.(*Error)
+	if !ok {
+		return &Error{Fields: fields, Err: err}
+	}
+	copy := *e
+	for k, v := range e.Fields {
+		fields[k] = v
+	}
+	copy.Fields = fields
+	return &copy
+}
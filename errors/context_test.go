@@ -0,0 +1,82 @@
+package errors_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/TouchBistro/goutils/errors"
+)
+
+type ctxKey string
+
+const (
+	requestIDKey ctxKey = "requestID"
+	userIDKey    ctxKey = "userID"
+)
+
+func init() {
+	errors.RegisterContextKey(requestIDKey, "request_id")
+	errors.RegisterContextKey(userIDKey, "user_id")
+}
+
+func TestWithContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), requestIDKey, "abc123")
+	ctx = context.WithValue(ctx, userIDKey, 42)
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		err        error
+		wantFields map[string]any
+	}{
+		{
+			"*Error",
+			ctx,
+			errors.New(internal, "oops", "test.Foo"),
+			map[string]any{"request_id": "abc123", "user_id": 42},
+		},
+		{
+			"existing fields are kept",
+			ctx,
+			errors.Wrap(nil, errors.Meta{Reason: "oops", Fields: map[string]any{"user_id": 7, "id": 1}}),
+			map[string]any{"request_id": "abc123", "user_id": 7, "id": 1},
+		},
+		{
+			"other error",
+			ctx,
+			errors.String("oops"),
+			map[string]any{"request_id": "abc123", "user_id": 42},
+		},
+		{
+			"no values",
+			context.Background(),
+			errors.New(internal, "oops", "test.Foo"),
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := errors.WithContext(tt.ctx, tt.err)
+			if got, want := err.Error(), tt.err.Error(); got != want {
+				t.Errorf("got message %q, want %q", got, want)
+			}
+			if _, ok := tt.err.(*errors.Error); !ok && !errors.Is(err, tt.err) {
+				t.Error("want WithContext to wrap the error")
+			}
+			var e *errors.Error
+			if !errors.As(err, &e) {
+				t.Fatal("want error to be an *Error")
+			}
+			if !reflect.DeepEqual(e.Fields, tt.wantFields) {
+				t.Errorf("got fields %v, want %v", e.Fields, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestWithContextNil(t *testing.T) {
+	ctx := context.WithValue(context.Background(), requestIDKey, "abc123")
+	if err := errors.WithContext(ctx, nil); err != nil {
+		t.Errorf("got %v, want nil", err)
+	}
+}
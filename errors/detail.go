@@ -0,0 +1,135 @@
+package errors
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"runtime"
+)
+
+// Detail returns an indented JSON representation of err and its full chain.
+// It includes the kind, op, reason, user message, fields, caller location and stack
+// of each Error, the type and message of all other errors, and each error in a List.
+// It is intended for debugging purposes, such as bug reports and debug dumps.
+// The same representation is created when formatting an Error or List with '%#v'.
+//
+// Field values are included as is if they can be represented as JSON, otherwise they
+// are formatted as strings. If err is nil, Detail returns "null".
+func Detail(err error) string {
+	b, jsonErr := json.MarshalIndent(newErrorDetail(err), "", "  ")
+	if jsonErr != nil {
+		// Should not happen since newErrorDetail ensures all values can be marshaled,
+		// but Detail is used for formatting so it must never fail.
+		b, _ = json.MarshalIndent(&errorDetail{Message: fmt.Sprint(err)}, "", "  ")
+	}
+	return string(b)
+}
+
+// errorDetail is the JSON representation of an error.
+type errorDetail struct {
+	// Fields for errors that are not an *Error.
+	Type    string `json:"type,omitempty"`
+	Message string `json:"message,omitempty"`
+
+	// Fields for an *Error.
+	Kind        string         `json:"kind,omitempty"`
+	Op          string         `json:"op,omitempty"`
+	Reason      string         `json:"reason,omitempty"`
+	UserMessage string         `json:"userMessage,omitempty"`
+	Fields      map[string]any `json:"fields,omitempty"`
+	Caller      string         `json:"caller,omitempty"`
+	Stack       []frameDetail  `json:"stack,omitempty"`
+
+	// Cause is the wrapped error, if any.
+	Cause *errorDetail `json:"cause,omitempty"`
+	// Errors contains each error if the error is a List.
+	Errors []*errorDetail `json:"errors,omitempty"`
+}
+
+type frameDetail struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+func newErrorDetail(err error) *errorDetail {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case *Error:
+		d := &errorDetail{
+			Op:          string(e.Op),
+			Reason:      e.Reason,
+			UserMessage: e.UserMessage,
+			Cause:       newErrorDetail(e.Err),
+		}
+		if e.Kind != nil {
+			d.Kind = e.Kind.Kind()
+		}
+		if len(e.Fields) > 0 {
+			d.Fields = make(map[string]any, len(e.Fields))
+			for k, v := range e.Fields {
+				d.Fields[k] = detailValue(v)
+			}
+		}
+		if e.caller != 0 {
+			d.Caller = string(appendCaller(nil, e.caller))
+		}
+		if e.stack != nil {
+			fs := runtime.CallersFrames(e.stack)
+			for {
+				f, more := fs.Next()
+				d.Stack = append(d.Stack, frameDetail{f.Function, f.File, f.Line})
+				if !more {
+					break
+				}
+			}
+		}
+		return d
+	case List:
+		d := &errorDetail{Errors: make([]*errorDetail, len(e))}
+		for i, err := range e {
+			d.Errors[i] = newErrorDetail(err)
+		}
+		return d
+	}
+	d := &errorDetail{Type: reflect.TypeOf(err).String(), Message: err.Error()}
+	// Include the chain of wrapped errors, since it can contain useful details.
+	if cause := Unwrap(err); cause != nil {
+		d.Cause = newErrorDetail(cause)
+	}
+	return d
+}
+
+// detailValue converts v to a value that can be marshaled as JSON.
+// Values that fail to marshal are formatted with fmt.Sprint, which also
+// handles methods of v that panic.
+func detailValue(v any) any {
+	switch v.(type) {
+	case nil:
+		return nil
+	case json.Marshaler, encoding.TextMarshaler:
+		if canMarshal(v) {
+			return v
+		}
+		return fmt.Sprint(v)
+	case error, fmt.Stringer:
+		return fmt.Sprint(v)
+	}
+	if !canMarshal(v) {
+		return fmt.Sprint(v)
+	}
+	return v
+}
+
+// canMarshal reports whether v can be marshaled as JSON without an error or a panic.
+func canMarshal(v any) (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			ok = false
+		}
+	}()
+	_, err := json.Marshal(v)
+	return err == nil
+}
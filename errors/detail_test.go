@@ -0,0 +1,108 @@
+package errors_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/TouchBistro/goutils/errors"
+)
+
+func TestDetail(t *testing.T) {
+	err := errors.List{
+		errors.Wrap(fmt.Errorf("read failed: %w", errors.String("EOF")), errors.Meta{
+			Kind:        internal,
+			Reason:      "unable to read config",
+			Op:          errors.Op("test.Foo"),
+			UserMessage: "Your config file is corrupt.",
+			Fields: map[string]any{
+				"path":  "/foo/bar",
+				"size":  42,
+				"ratio": math.NaN(),
+				"cause": errors.String("oops"),
+			},
+		}),
+		errors.String("oops"),
+	}
+	want := `{
+  "errors": [
+    {
+      "kind": "internal error",
+      "op": "test.Foo",
+      "reason": "unable to read config",
+      "userMessage": "Your config file is corrupt.",
+      "fields": {
+        "cause": "oops",
+        "path": "/foo/bar",
+        "ratio": "NaN",
+        "size": 42
+      },
+      "cause": {
+        "type": "*fmt.wrapError",
+        "message": "read failed: EOF",
+        "cause": {
+          "type": "errors.String",
+          "message": "EOF"
+        }
+      }
+    },
+    {
+      "type": "errors.String",
+      "message": "oops"
+    }
+  ]
+}`
+	if got := errors.Detail(err); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+	if got := fmt.Sprintf("%#v", err); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+	if got := fmt.Sprintf("%#v", err[0]); got != errors.Detail(err[0]) {
+		t.Errorf("got\n%s\nwant\n%s", got, errors.Detail(err[0]))
+	}
+	if got := errors.Detail(nil); got != "null" {
+		t.Errorf("got %s, want null", got)
+	}
+}
+
+type badJSON struct{ name string }
+
+func (b *badJSON) MarshalJSON() ([]byte, error) {
+	return nil, errors.String("cannot marshal")
+}
+
+func (b *badJSON) String() string {
+	return b.name
+}
+
+type panicText struct{}
+
+func (panicText) MarshalText() ([]byte, error) {
+	panic("boom")
+}
+
+func TestDetailBadFieldValues(t *testing.T) {
+	err := errors.New(internal, "failed", errors.Op("test.Foo"))
+	err.(*errors.Error).Fields = map[string]any{
+		"json":   &badJSON{name: "bad"},
+		"text":   panicText{},
+		"nilptr": (*badJSON)(nil),
+	}
+	want := `{
+  "kind": "internal error",
+  "op": "test.Foo",
+  "reason": "failed",
+  "fields": {
+    "json": "bad",
+    "nilptr": null,
+    "text": "{}"
+  }
+}`
+	if got := errors.Detail(err); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+	if got := fmt.Sprintf("%#v", err); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
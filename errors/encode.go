@@ -0,0 +1,102 @@
+package errors
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// Encode encodes err into a JSON based wire format that can be decoded by Decode.
+// This allows errors to cross process boundaries, such as from a worker to a
+// supervisor or over an RPC, without losing their structure.
+//
+// The kind, op, reason, user message and fields of each Error in the chain are preserved,
+// as well as the messages of all other errors and each error in a List. Stack traces and
+// caller locations are included in the encoding for debugging, but are not restored by Decode.
+// Field values are encoded the same way as by Detail. A nil error and an empty List are
+// both encoded as no error, so they decode to nil.
+func Encode(err error) ([]byte, error) {
+	if l, ok := err.(List); ok && len(l) == 0 {
+		err = nil
+	}
+	return json.Marshal(newErrorDetail(err))
+}
+
+// Decode decodes an error that was encoded with Encode. It returns nil if the encoded
+// error was nil. If data is not a valid encoding, Decode returns a *DecodeError, which
+// can be checked using As to tell it apart from a decoded error.
+//
+// Each Error in the chain is decoded as an *Error. If the kind of an Error was registered
+// using RegisterKind, the decoded Error has the registered Kind. Since other kinds cannot be
+// decoded to their original types, the Kind of a decoded Error is a Kind whose Kind method
+// returns the same value as the original. Errors of type String are decoded as a String with the same value,
+// so sentinel errors can still be matched using Is. All other errors are decoded as an error
+// with the same message that unwraps to the decoded cause, if there was one.
+//
+// Note that JSON numbers in fields are decoded as float64.
+func Decode(data []byte) error {
+	var d *errorDetail
+	if err := json.Unmarshal(data, &d); err != nil {
+		return &DecodeError{Err: err}
+	}
+	return d.decode()
+}
+
+// DecodeError is returned by Decode if the data is not a valid encoding of an error.
+type DecodeError struct {
+	Err error // the error that occurred while decoding
+}
+
+func (e *DecodeError) Error() string {
+	return "invalid encoded error: " + e.Err.Error()
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// stringType is the type name of String used by Detail and Encode.
+var stringType = reflect.TypeOf(String("")).String()
+
+func (d *errorDetail) decode() error {
+	switch {
+	case d == nil:
+		return nil
+	case d.Errors != nil:
+		l := make(List, len(d.Errors))
+		for i, ed := range d.Errors {
+			l[i] = ed.decode()
+		}
+		return l
+	case d.Type == stringType:
+		return String(d.Message)
+	case d.Type != "":
+		return &decodedError{msg: d.Message, err: d.Cause.decode()}
+	}
+	e := &Error{
+		Op:          Op(d.Op),
+		Reason:      d.Reason,
+		UserMessage: d.UserMessage,
+		Fields:      d.Fields,
+		Err:         d.Cause.decode(),
+	}
+	if _, ok := LookupKind(d.Kind); ok {
+		e.Kind = registeredKind(d.Kind)
+	} else if d.Kind != "" {
+		e.Kind = kind(d.Kind)
+	}
+	return e
+}
+
+// decodedError is an error that was decoded and was not an *Error or String.
+type decodedError struct {
+	msg string
+	err error
+}
+
+func (e *decodedError) Error() string {
+	return e.msg
+}
+
+func (e *decodedError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,93 @@
+package errors_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/TouchBistro/goutils/errors"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	const eof errors.String = "EOF"
+	err := errors.List{
+		errors.Wrap(
+			errors.Wrap(fmt.Errorf("read failed: %w", eof), errors.Meta{
+				Reason: "no file for path",
+				Op:     errors.Op("test.Foo"),
+				Fields: map[string]any{"path": "/foo/bar", "size": 42},
+			}),
+			errors.Meta{
+				Kind:        internal,
+				Reason:      "unable to read config",
+				Op:          errors.Op("test.Bar"),
+				UserMessage: "Your config file is corrupt.",
+			},
+		),
+		errors.WithStack(errors.New(errors.KindPanic, "something blew up", errors.Op("test.Baz"))),
+	}
+	data, encErr := errors.Encode(err)
+	if encErr != nil {
+		t.Fatalf("failed to encode error: %v", encErr)
+	}
+	got := errors.Decode(data)
+	var decErr *errors.DecodeError
+	if errors.As(got, &decErr) {
+		t.Fatalf("failed to decode error: %v", decErr)
+	}
+
+	for _, format := range []string{"%s", "%+v"} {
+		want := fmt.Sprintf(format, err)
+		if format == "%+v" {
+			// Stack traces are not decoded.
+			want = fmt.Sprintf("%+v\n%+v", err[0], errors.New(errors.KindPanic, "something blew up", errors.Op("test.Baz")))
+		}
+		if s := fmt.Sprintf(format, got); s != want {
+			t.Errorf("%s: got\n\t%s\nwant\n\t%s", format, s, want)
+		}
+	}
+	if !errors.Is(got, eof) {
+		t.Error("want decoded error to contain eof")
+	}
+	if !errors.IsKind(got, errors.KindPanic) {
+		t.Error("want decoded error to have kind panic")
+	}
+	if got, want := errors.Ops(got.(errors.List)[0]), []errors.Op{"test.Bar", "test.Foo"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got ops %v, want %v", got, want)
+	}
+	if got, want := errors.UserMessage(got.(errors.List)[0]), "Your config file is corrupt."; got != want {
+		t.Errorf("got user message %q, want %q", got, want)
+	}
+}
+
+func TestDecodeNil(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"nil", nil},
+		{"empty list", errors.List{}},
+		{"nil list", errors.List(nil)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := errors.Encode(tt.err)
+			if err != nil {
+				t.Fatalf("failed to encode error: %v", err)
+			}
+			if got := errors.Decode(data); got != nil {
+				t.Errorf("got %v, want nil", got)
+			}
+		})
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	err := errors.Decode([]byte("{"))
+	var decErr *errors.DecodeError
+	if !errors.As(err, &decErr) {
+		t.Fatalf("got %v, want a *DecodeError", err)
+	}
+	if decErr.Err == nil {
+		t.Error("want DecodeError to contain the JSON error")
+	}
+}
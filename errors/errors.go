@@ -17,6 +17,9 @@
 //
 // Both Error and List implement fmt.Formatter and can be formatted by the fmt package.
 // Using the %+v verb will create a detailed description of the error that is suited for debugging.
+// Using the %#v verb will create an indented JSON representation of the full error chain, see Detail.
+// WithStack can be used to annotate any error with a stack trace which is included in this description.
+// SetCaptureCallers can be used to also include the file and line where each error was created.
 //
 // Note that this package is not a solution for all cases. There is no one size fits all for error
 // handling, as errors will depend on the domain of the program and its requirements.
@@ -28,6 +31,8 @@ package errors
 import (
 	stderrors "errors"
 	"fmt"
+	"io"
+	"slices"
 	"strings"
 )
 
@@ -46,9 +51,28 @@ type Error struct {
 	// Op is the operation being performed, usually the
 	// name of a function or method being invoked.
 	Op Op
+	// UserMessage is an optional friendly message that is suitable for displaying
+	// to end users, for example by a CLI or returned in an API response.
+	// Unlike Reason, it should not contain internal details. It is not included
+	// in the error message. Use the UserMessage function to retrieve it from an error chain.
+	UserMessage string
+	// Fields contains additional details about the error as key-value pairs,
+	// for example the path of a file that could not be read.
+	// Fields are not included in the error message, but they are included
+	// in the detailed description created by the '%+v' verb.
+	// Fields should be treated as immutable once the error is created.
+	Fields map[string]any
 	// Err is the underlying error that triggered this one.
 	// If no underlying error occurred, it will be nil.
 	Err error
+
+	stack  []uintptr // program counters captured by WithStack
+	caller uintptr   // program counter of where the error was created, see SetCaptureCallers
+
+	msgKey  string // key of the localized user message, see Localize
+	msgArgs []any  // args of the localized user message
+
+	suggestions []string // suggestions for fixing the error, see WithSuggestion
 }
 
 // Kind represents any type that can categorize errors.
@@ -72,7 +96,7 @@ type Op string
 
 // New creates a new error using kind, reason and op.
 func New(kind Kind, reason string, op Op) error {
-	return newError(kind, reason, op, nil)
+	return newError(Meta{Kind: kind, Reason: reason, Op: op}, nil)
 }
 
 // Wrap wraps an existing error. It can be used to provide additional context
@@ -82,7 +106,51 @@ func New(kind Kind, reason string, op Op) error {
 // to make error chains nicer. If meta.Kind is nil, it will be hoisted from err.
 // If meta.Kind == err.Kind, err.Kind will be set to nil, to prevent duplicate kinds.
 func Wrap(err error, meta Meta) error {
-	return newError(meta.Kind, meta.Reason, meta.Op, err)
+	return newError(meta, err)
+}
+
+// WrapIf is like Wrap but returns nil if err is nil. This allows functions to
+// wrap and return a possibly nil error in a single statement.
+//
+//	return errors.WrapIf(internal, "failed to close file", op, f.Close())
+func WrapIf(kind Kind, reason string, op Op, err error) error {
+	if err == nil {
+		return nil
+	}
+	return newError(Meta{Kind: kind, Reason: reason, Op: op}, err)
+}
+
+// Wrapf creates a new error using kind and op, with a message created by formatting
+// according to format. Like fmt.Errorf, if format contains a %w verb with an error operand,
+// the error will be wrapped, allowing the cause to be embedded where it reads naturally
+// in the message while still being available to Unwrap, Is and As.
+//
+//	errors.Wrapf(internal, op, "reading %s: %w", path, err)
+//
+// Any *Error in args is treated the same way as Wrap treats err. If kind is nil it will be
+// hoisted from the first *Error that has a kind, and kinds that are the same as kind are removed
+// to prevent duplicate kinds in the message.
+func Wrapf(kind Kind, op Op, format string, args ...any) error {
+	copied := false
+	for i, arg := range args {
+		prev, ok := arg.(*Error)
+		if !ok || prev.Kind == nil || (kind != nil && prev.Kind != kind) {
+			continue
+		}
+		// Copy args so the caller's slice is not modified.
+		if !copied {
+			args = append([]any(nil), args...)
+			copied = true
+		}
+		// Make a copy so error chains are immutable.
+		copy := *prev
+		if kind == nil {
+			kind = copy.Kind
+		}
+		copy.Kind = nil
+		args[i] = &copy
+	}
+	return newError(Meta{Kind: kind, Op: op}, fmt.Errorf(format, args...))
 }
 
 // Meta allows for specifying the fields for a wrapped error provided to Wrap.
@@ -93,15 +161,29 @@ type Meta struct {
 	Reason string
 	// Op is the operation being performed. See Error.Op.
 	Op Op
+	// UserMessage is a message suitable for end users. See Error.UserMessage.
+	UserMessage string
+	// Fields are additional details about the error. See Error.Fields.
+	Fields map[string]any
 }
 
-func newError(kind Kind, reason string, op Op, err error) error {
-	e := &Error{Kind: kind, Reason: reason, Op: op}
+func newError(meta Meta, err error) error {
+	e := &Error{
+		Kind:        meta.Kind,
+		Reason:      meta.Reason,
+		Op:          meta.Op,
+		UserMessage: meta.UserMessage,
+		Fields:      meta.Fields,
+		// Skip [runtime.Callers, callerPC, newError, exported function].
+		caller: callerPC(4),
+	}
 	if err == nil {
 		return e
 	}
-	prev, ok := err.(*Error)
-	if !ok {
+	// Look through MarkRetryable, which is transparent, and mark the copy of the
+	// previous error below as retryable instead.
+	prev, retryable := asError(err)
+	if prev == nil {
 		e.Err = err
 		return e
 	}
@@ -120,24 +202,14 @@ func newError(kind Kind, reason string, op Op, err error) error {
 		prev.Kind = nil
 	}
 	e.Err = prev
+	if retryable {
+		e.Err = &retryableError{prev}
+	}
 	return e
 }
 
 func (e *Error) Error() string {
-	sb := &strings.Builder{}
-	if e.Kind != nil {
-		pad(sb, ": ")
-		sb.WriteString(e.Kind.Kind())
-	}
-	if e.Reason != "" {
-		pad(sb, ": ")
-		sb.WriteString(e.Reason)
-	}
-	if e.Err != nil {
-		pad(sb, ": ")
-		sb.WriteString(e.Err.Error())
-	}
-	return sb.String()
+	return message(e)
 }
 
 func (e *Error) Format(s fmt.State, verb rune) {
@@ -145,64 +217,259 @@ func (e *Error) Format(s fmt.State, verb rune) {
 	case 'v':
 		// If '%+v' print a detailed description for debugging purposes.
 		if s.Flag('+') {
-			sb := &strings.Builder{}
-			if e.Op != "" {
-				pad(sb, ": ")
-				sb.WriteString(string(e.Op))
-			}
-			if e.Kind != nil {
-				pad(sb, ": ")
-				sb.WriteString(e.Kind.Kind())
-			}
-			if e.Reason != "" {
-				pad(sb, ": ")
-				sb.WriteString(e.Reason)
-			}
-			if e.Err != nil {
-				if prevErr, ok := e.Err.(*Error); ok {
-					pad(sb, ":\n\t")
-					fmt.Fprintf(sb, "%+v", prevErr)
-				} else {
-					pad(sb, ": ")
-					sb.WriteString(e.Err.Error())
-				}
+			b := getBuffer()
+			*b = e.appendDetail(*b)
+			if frames := Stack(e); len(frames) > 0 {
+				*b = append(*b, '\n')
+				*b = appendFrames(*b, frames)
 			}
-			fmt.Fprint(s, sb.String())
+			*b = appendSuggestions(*b, Suggestions(e))
+			s.Write(*b)
+			putBuffer(b)
+			return
+		}
+		// If '%#v' print the JSON description created by Detail.
+		if s.Flag('#') {
+			fmt.Fprint(s, Detail(e))
 			return
 		}
 		fallthrough
 	case 's':
-		fmt.Fprint(s, e.Error())
+		writeMessage(s, e, false)
 	case 'q':
-		fmt.Fprintf(s, "%q", e.Error())
+		writeMessage(s, e, true)
+	}
+}
+
+// appendDetail appends the detailed description of e and any Errors it wraps to b
+// and returns the extended buffer.
+func (e *Error) appendDetail(b []byte) []byte {
+	// Only pad relative to what this error has written, b may already contain
+	// the details of the errors wrapping this one.
+	start := len(b)
+	pad := func(s string) {
+		if len(b) > start {
+			b = append(b, s...)
+		}
+	}
+	if e.caller != 0 {
+		b = appendCaller(b, e.caller)
+	}
+	if e.Op != "" {
+		pad(": ")
+		b = append(b, e.Op...)
+	}
+	if e.Kind != nil {
+		pad(": ")
+		b = append(b, e.Kind.Kind()...)
+	}
+	if e.Reason != "" {
+		pad(": ")
+		b = append(b, e.Reason...)
+	}
+	if len(e.Fields) > 0 {
+		pad(" ")
+		b = appendFields(b, e.Fields)
+	}
+	if e.Err != nil {
+		if prevErr, _ := asError(e.Err); prevErr != nil {
+			pad(":\n\t")
+			b = prevErr.appendDetail(b)
+		} else {
+			pad(": ")
+			b = appendMessage(b, e.Err)
+		}
+	}
+	return b
+}
+
+// appendFields appends fields to b in the form {k1=v1 k2=v2} and returns the extended
+// buffer. Keys are sorted so that the output is deterministic.
+func appendFields(b []byte, fields map[string]any) []byte {
+	b = append(b, '{')
+	for i, k := range sortedKeys(fields) {
+		if i > 0 {
+			b = append(b, ' ')
+		}
+		b = append(b, k...)
+		b = append(b, '=')
+		b = fmt.Append(b, fields[k])
 	}
+	return append(b, '}')
 }
 
-// pad appends s to sb if b already has some data.
-func pad(sb *strings.Builder, s string) {
-	if sb.Len() == 0 {
-		return
+// sortedKeys returns the keys of fields in sorted order.
+func sortedKeys(fields map[string]any) []string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
 	}
-	sb.WriteString(s)
+	slices.Sort(keys)
+	return keys
 }
 
 func (e *Error) Unwrap() error {
 	return e.Err
 }
 
+// Timeout reports whether e was caused by a timeout. It delegates to the
+// Timeout method of the first error in e's chain that implements one,
+// such as a net.Error or context.DeadlineExceeded. This allows existing
+// checks for timeouts to keep working after an error has been wrapped.
+// If no error in the chain has a Timeout method, it returns false.
+func (e *Error) Timeout() bool {
+	var t interface{ Timeout() bool }
+	return e.Err != nil && As(e.Err, &t) && t.Timeout()
+}
+
+// Temporary reports whether e was caused by a temporary condition. It delegates to the
+// Temporary method of the first error in e's chain that implements one.
+// If no error in the chain has a Temporary method, it returns false.
+func (e *Error) Temporary() bool {
+	var t interface{ Temporary() bool }
+	return e.Err != nil && As(e.Err, &t) && t.Temporary()
+}
+
+// kind is a Kind provided by this package.
+type kind string
+
+func (k kind) Kind() string {
+	return string(k)
+}
+
+// KindUnknown is the Kind returned by KindOf for errors that do not have a kind.
+var KindUnknown Kind = kind("unknown")
+
+// KindOf returns the outermost non-nil Kind of an *Error in err's chain.
+// This allows handling errors based on their kind without unwrapping them manually.
+//
+//	switch errors.KindOf(err) {
+//	case KindNotFound:
+//		...
+//	}
+//
+// Lists are not descended into since they do not form a single chain.
+// If no kind is found or err is nil, KindOf returns KindUnknown.
+func KindOf(err error) Kind {
+	for err != nil {
+		if e, ok := err.(*Error); ok && e.Kind != nil {
+			return e.Kind
+		}
+		err = Unwrap(err)
+	}
+	return KindUnknown
+}
+
+// IsKind reports whether any error in err's chain has the given kind.
+//
+// The chain consists of err itself followed by the sequence of errors obtained by
+// repeatedly calling Unwrap. If an error in the chain is a List, or any other error
+// with an Unwrap() []error method, each of its errors is checked as well.
+//
+// An error matches if it is an *Error whose Kind is equal to kind.
+func IsKind(err error, kind Kind) bool {
+	if kind == nil {
+		return false
+	}
+	found := false
+	Walk(err, func(err error) bool {
+		if e, ok := err.(*Error); ok && e.Kind == kind {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// Walk calls fn for each error in err's tree, in depth-first order, starting with err itself.
+// If fn returns false, Walk stops and no more errors are visited.
+//
+// The tree consists of err itself followed by the errors obtained by calling Unwrap.
+// If an error has an Unwrap() error method, the returned error is visited next.
+// If an error has an Unwrap() []error method, such as List, each of the returned errors
+// and their trees are visited in order.
+func Walk(err error, fn func(error) bool) {
+	walk(err, fn)
+}
+
+// walk is the implementation of Walk. It returns false if the walk was stopped.
+func walk(err error, fn func(error) bool) bool {
+	for err != nil {
+		if !fn(err) {
+			return false
+		}
+		switch x := err.(type) {
+		case interface{ Unwrap() error }:
+			err = x.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, err := range x.Unwrap() {
+				if !walk(err, fn) {
+					return false
+				}
+			}
+			return true
+		default:
+			return true
+		}
+	}
+	return true
+}
+
+// UserMessage returns the first non-empty Error.UserMessage in err's chain.
+// Since the outermost message is returned, a caller can override the message
+// of an error it wraps with one that makes more sense in its context.
+// If the message was set using Localize, it is translated if a translator is set.
+//
+// If no user message is found, the KindDef.Message of the first Error in the chain
+// with a kind registered using RegisterKind is returned. Otherwise, UserMessage
+// returns an empty string.
+func UserMessage(err error) string {
+	var msg string
+	for err != nil {
+		if e, ok := err.(*Error); ok {
+			if e.UserMessage != "" {
+				return e.userMessage()
+			}
+			if def, ok := registeredDef(e.Kind); ok && msg == "" {
+				msg = def.Message
+			}
+		}
+		err = Unwrap(err)
+	}
+	return msg
+}
+
+// Ops returns the operations of each Error in err's chain, ordered from
+// outermost to innermost. Errors in the chain with an empty Op are skipped.
+//
+// The chain consists of err itself followed by the sequence of errors obtained
+// by repeatedly calling Unwrap. Lists are not descended into since they do not
+// form a single chain, use Ops on each error in the List instead.
+//
+// Ops returns nil if no operations are found.
+func Ops(err error) []Op {
+	var ops []Op
+	for err != nil {
+		if e, ok := err.(*Error); ok && e.Op != "" {
+			ops = append(ops, e.Op)
+		}
+		err = Unwrap(err)
+	}
+	return ops
+}
+
 // List is a list of errors. It allows for operations to keep track of
 // multiple errors and return them as a single error value.
 type List []error
 
 func (e List) Error() string {
-	var sb strings.Builder
-	for i, err := range e {
-		if i > 0 {
-			sb.WriteByte('\n')
-		}
-		sb.WriteString(err.Error())
-	}
-	return sb.String()
+	return message(e)
+}
+
+// Unwrap returns the errors contained in the list.
+// This allows Is and As to match any error within the list.
+func (e List) Unwrap() []error {
+	return e
 }
 
 func (e List) Format(s fmt.State, verb rune) {
@@ -217,14 +484,19 @@ func (e List) Format(s fmt.State, verb rune) {
 				}
 				fmt.Fprintf(&sb, "%+v", err)
 			}
-			fmt.Fprint(s, sb.String())
+			io.WriteString(s, sb.String())
+			return
+		}
+		// If '%#v' print the JSON description created by Detail.
+		if s.Flag('#') {
+			fmt.Fprint(s, Detail(e))
 			return
 		}
 		fallthrough
 	case 's':
-		fmt.Fprint(s, e.Error())
+		writeMessage(s, e, false)
 	case 'q':
-		fmt.Fprintf(s, "%q", e.Error())
+		writeMessage(s, e, true)
 	}
 }
 
@@ -244,6 +516,22 @@ func (e String) Error() string {
 	return string(e)
 }
 
+// Sentinel returns a sentinel error for name that is namespaced by namespace,
+// which is usually the name of the package that defines it.
+// The message of the error has the stable form "namespace: name".
+//
+//	var ErrNotFound = errors.Sentinel("users", "not found")
+//
+// Since the returned error is a String, sentinels with the same namespace and
+// name are equal, and Is matches them through any number of wrapped errors.
+// If namespace is empty, the message is just name.
+func Sentinel(namespace, name string) String {
+	if namespace == "" {
+		return String(name)
+	}
+	return String(namespace + ": " + name)
+}
+
 // Unwrap returns the result of calling the Unwrap method on err, if err's
 // type contains an Unwrap method returning error.
 // Otherwise, Unwrap returns nil.
@@ -289,3 +577,20 @@ func Is(err, target error) bool {
 func As(err error, target any) bool {
 	return stderrors.As(err, target)
 }
+
+// AsType finds the first error in err's chain that is of type T, and if so,
+// returns that error and true. Otherwise, it returns the zero value of T and false.
+//
+// It is a generic version of As that removes the need to declare a target variable.
+//
+//	if pathErr, ok := errors.AsType[*fs.PathError](err); ok {
+//		fmt.Println("failed at path:", pathErr.Path)
+//	}
+func AsType[T error](err error) (T, bool) {
+	var target T
+	if As(err, &target) {
+		return target, true
+	}
+	var zero T
+	return zero, false
+}
@@ -1,7 +1,9 @@
 package errors_test
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/TouchBistro/goutils/errors"
@@ -57,6 +59,17 @@ func TestErrorFormat(t *testing.T) {
 			format: "%+v",
 			want:   "test.Foo: internal error: unable to create file: dir not exist",
 		},
+		{
+			name: "detailed format with fields",
+			err: errors.Wrap(fmt.Errorf("dir not exist"), errors.Meta{
+				Kind:   internal,
+				Reason: "unable to create file",
+				Op:     errors.Op("test.Foo"),
+				Fields: map[string]any{"path": "/foo/bar", "mode": 0o644},
+			}),
+			format: "%+v",
+			want:   "test.Foo: internal error: unable to create file {mode=420 path=/foo/bar}: dir not exist",
+		},
 		{
 			name: "detailed format with nested error",
 			err: errors.Wrap(
@@ -191,6 +204,34 @@ func TestIs(t *testing.T) {
 	}
 }
 
+func TestSentinel(t *testing.T) {
+	errNotFound := errors.Sentinel("users", "not found")
+	if got, want := errNotFound.Error(), "users: not found"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if errNotFound != errors.Sentinel("users", "not found") {
+		t.Error("want sentinels with the same namespace and name to be equal")
+	}
+	if errors.Is(errNotFound, errors.Sentinel("groups", "not found")) {
+		t.Error("want sentinels with different namespaces to not match")
+	}
+	if got, want := errors.Sentinel("", "EOF").Error(), "EOF"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	var err error = errNotFound
+	for i := 0; i < 10; i++ {
+		err = errors.Wrap(err, errors.Meta{Kind: internal, Reason: "lookup failed", Op: errors.Op("users.Get")})
+		err = errors.Wrapf(invalid, "api.GetUser", "request %d: %w", i, err)
+		err = fmt.Errorf("attempt %d: %w", i, err)
+		err = errors.WithStack(err)
+		err = errors.List{errors.String("other"), err}
+	}
+	if !errors.Is(err, errNotFound) {
+		t.Error("want Is to match sentinel through nested errors")
+	}
+}
+
 func TestAs(t *testing.T) {
 	pathErr := &pathError{"/foo/bar", "file not found"}
 	err := errors.Wrap(pathErr, errors.Meta{
@@ -206,3 +247,326 @@ func TestAs(t *testing.T) {
 		t.Errorf("got err\n\t%s\nwant\n\t%s", gotErr, pathErr)
 	}
 }
+
+func TestIsKind(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		kind errors.Kind
+		want bool
+	}{
+		{
+			name: "direct",
+			err:  errors.New(internal, "something blew up", errors.Op("test.Foo")),
+			kind: internal,
+			want: true,
+		},
+		{
+			name: "different kind",
+			err:  errors.New(internal, "something blew up", errors.Op("test.Foo")),
+			kind: invalid,
+			want: false,
+		},
+		{
+			name: "wrapped",
+			err: errors.Wrap(
+				errors.New(internal, "no file for path", errors.Op("test.Foo")),
+				errors.Meta{Kind: invalid, Reason: "cannot find file", Op: errors.Op("test.Bar")},
+			),
+			kind: internal,
+			want: true,
+		},
+		{
+			name: "wrapped by fmt",
+			err:  fmt.Errorf("oops: %w", errors.New(internal, "something blew up", errors.Op("test.Foo"))),
+			kind: internal,
+			want: true,
+		},
+		{
+			name: "in list",
+			err: errors.List{
+				fmt.Errorf("something blew up"),
+				errors.New(invalid, "you can't do that", errors.Op("test.Foo")),
+			},
+			kind: invalid,
+			want: true,
+		},
+		{
+			name: "not an Error",
+			err:  errors.String("oops"),
+			kind: internal,
+			want: false,
+		},
+		{
+			name: "nil kind",
+			err:  errors.Wrap(errors.String("oops"), errors.Meta{Reason: "no kind"}),
+			kind: nil,
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.IsKind(tt.err, tt.kind); got != tt.want {
+				t.Errorf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKindOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want errors.Kind
+	}{
+		{"nil", nil, errors.KindUnknown},
+		{"no kind", errors.String("oops"), errors.KindUnknown},
+		{"kind", errors.New(invalid, "oops", ""), invalid},
+		{
+			"outermost kind",
+			errors.Wrap(errors.New(invalid, "oops", ""), errors.Meta{Kind: internal, Reason: "failed"}),
+			internal,
+		},
+		{
+			"kind in chain",
+			fmt.Errorf("failed: %w", errors.Wrap(errors.String("oops"), errors.Meta{Kind: internal})),
+			internal,
+		},
+		{"list", errors.List{errors.New(invalid, "oops", "")}, errors.KindUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.KindOf(tt.err); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsList(t *testing.T) {
+	const eof errors.String = "EOF"
+	err := errors.List{
+		errors.String("oops"),
+		errors.Wrap(eof, errors.Meta{Reason: "unexpected end of file"}),
+	}
+	if !errors.Is(err, eof) {
+		t.Error("want err to contain eof")
+	}
+}
+
+func TestOps(t *testing.T) {
+	err := errors.Wrap(
+		fmt.Errorf("reading config: %w", errors.Wrap(
+			errors.New(internal, "no file for path", errors.Op("test.Foo")),
+			errors.Meta{Reason: "no op here"},
+		)),
+		errors.Meta{Kind: invalid, Reason: "cannot find file", Op: errors.Op("test.Bar")},
+	)
+	got := errors.Ops(err)
+	want := []errors.Op{"test.Bar", "test.Foo"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got ops %v, want %v", got, want)
+	}
+	if got := errors.Ops(errors.String("oops")); got != nil {
+		t.Errorf("got ops %v, want nil", got)
+	}
+}
+
+func TestWrapIf(t *testing.T) {
+	if err := errors.WrapIf(internal, "unable to close file", errors.Op("test.Foo"), nil); err != nil {
+		t.Errorf("got %v, want nil error", err)
+	}
+	err := errors.WrapIf(internal, "unable to close file", errors.Op("test.Foo"), fmt.Errorf("file already closed"))
+	want := "test.Foo: internal error: unable to close file: file already closed"
+	if got := fmt.Sprintf("%+v", err); got != want {
+		t.Errorf("got\n\t%s\nwant\n\t%s", got, want)
+	}
+}
+
+type netError struct {
+	timeout   bool
+	temporary bool
+}
+
+func (e *netError) Error() string   { return "i/o error" }
+func (e *netError) Timeout() bool   { return e.timeout }
+func (e *netError) Temporary() bool { return e.temporary }
+
+func TestTimeoutTemporary(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantTimeout   bool
+		wantTemporary bool
+	}{
+		{
+			name:          "deadline exceeded",
+			err:           errors.Wrap(context.DeadlineExceeded, errors.Meta{Reason: "request failed"}),
+			wantTimeout:   true,
+			wantTemporary: true,
+		},
+		{
+			name: "nested",
+			err: errors.Wrap(
+				fmt.Errorf("dial: %w", errors.Wrap(&netError{temporary: true}, errors.Meta{Reason: "read failed"})),
+				errors.Meta{Reason: "request failed"},
+			),
+			wantTimeout:   false,
+			wantTemporary: true,
+		},
+		{
+			name:          "no cause",
+			err:           errors.New(internal, "something blew up", errors.Op("test.Foo")),
+			wantTimeout:   false,
+			wantTemporary: false,
+		},
+		{
+			name:          "cause without methods",
+			err:           errors.Wrap(errors.String("oops"), errors.Meta{Reason: "request failed"}),
+			wantTimeout:   false,
+			wantTemporary: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := tt.err.(interface {
+				Timeout() bool
+				Temporary() bool
+			})
+			if got := e.Timeout(); got != tt.wantTimeout {
+				t.Errorf("got timeout %t, want %t", got, tt.wantTimeout)
+			}
+			if got := e.Temporary(); got != tt.wantTemporary {
+				t.Errorf("got temporary %t, want %t", got, tt.wantTemporary)
+			}
+		})
+	}
+}
+
+func TestUserMessage(t *testing.T) {
+	err := errors.Wrap(
+		errors.Wrap(fmt.Errorf("dial tcp: connection refused"), errors.Meta{
+			Kind:        internal,
+			Reason:      "unable to connect to db",
+			Op:          errors.Op("test.Foo"),
+			UserMessage: "The service is unavailable, please try again later.",
+		}),
+		errors.Meta{Reason: "failed to load user", Op: errors.Op("test.Bar")},
+	)
+	if got, want := errors.UserMessage(err), "The service is unavailable, please try again later."; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	wantDetail := "test.Bar: internal error: failed to load user:\n\ttest.Foo: unable to connect to db: dial tcp: connection refused"
+	if got := fmt.Sprintf("%+v", err); got != wantDetail {
+		t.Errorf("got\n\t%s\nwant\n\t%s", got, wantDetail)
+	}
+	if got := errors.UserMessage(errors.String("oops")); got != "" {
+		t.Errorf("got %q, want empty string", got)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	foo := errors.New(internal, "something blew up", errors.Op("test.Foo"))
+	oops := errors.String("oops")
+	nested := fmt.Errorf("nested: %w", oops)
+	bar := errors.Wrap(errors.List{nested, foo}, errors.Meta{Reason: "many errors", Op: errors.Op("test.Bar")})
+	err := errors.List{bar, errors.String("last")}
+
+	var got []error
+	errors.Walk(err, func(err error) bool {
+		got = append(got, err)
+		return true
+	})
+	want := []error{err, bar, bar.(*errors.Error).Err, nested, oops, foo, err[1]}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got\n\t%v\nwant\n\t%v", got, want)
+	}
+
+	// Make sure walk stops as soon as fn returns false.
+	got = nil
+	errors.Walk(err, func(err error) bool {
+		got = append(got, err)
+		return err != oops
+	})
+	if want := want[:5]; !reflect.DeepEqual(got, want) {
+		t.Errorf("got\n\t%v\nwant\n\t%v", got, want)
+	}
+}
+
+func TestAsType(t *testing.T) {
+	pathErr := &pathError{"/foo/bar", "file not found"}
+	err := errors.Wrap(pathErr, errors.Meta{
+		Kind:   invalid,
+		Reason: "source does not exist",
+		Op:     errors.Op("config.Read"),
+	})
+	gotErr, ok := errors.AsType[*pathError](err)
+	if !ok {
+		t.Fatal("want err to contain an error of type *pathError")
+	}
+	if gotErr != pathErr {
+		t.Errorf("got err\n\t%s\nwant\n\t%s", gotErr, pathErr)
+	}
+	if gotErr, ok := errors.AsType[errors.String](err); ok {
+		t.Errorf("got err %q, want no match", gotErr)
+	}
+}
+
+func TestWrapf(t *testing.T) {
+	const eof errors.String = "EOF"
+	tests := []struct {
+		name       string
+		err        error
+		want       string
+		wantDetail string
+	}{
+		{
+			name:       "foreign cause",
+			err:        errors.Wrapf(internal, errors.Op("test.Foo"), "reading %s: %w", "/foo/bar", eof),
+			want:       "internal error: reading /foo/bar: EOF",
+			wantDetail: "test.Foo: internal error: reading /foo/bar: EOF",
+		},
+		{
+			name: "hoists kind",
+			err: errors.Wrapf(nil, errors.Op("test.Bar"), "loading config %q: %w", "app",
+				errors.New(invalid, "no file for path", errors.Op("test.Foo"))),
+			want:       `invalid operation: loading config "app": no file for path`,
+			wantDetail: `test.Bar: invalid operation: loading config "app": no file for path`,
+		},
+		{
+			name: "removes duplicate kind",
+			err: errors.Wrapf(invalid, errors.Op("test.Bar"), "loading config: %w",
+				errors.New(invalid, "no file for path", errors.Op("test.Foo"))),
+			want:       "invalid operation: loading config: no file for path",
+			wantDetail: "test.Bar: invalid operation: loading config: no file for path",
+		},
+		{
+			name: "keeps different kind",
+			err: errors.Wrapf(internal, errors.Op("test.Bar"), "loading config: %w",
+				errors.New(invalid, "no file for path", errors.Op("test.Foo"))),
+			want:       "internal error: loading config: invalid operation: no file for path",
+			wantDetail: "test.Bar: internal error: loading config: invalid operation: no file for path",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("got\n\t%s\nwant\n\t%s", got, tt.want)
+			}
+			if got := fmt.Sprintf("%+v", tt.err); got != tt.wantDetail {
+				t.Errorf("got detail\n\t%s\nwant\n\t%s", got, tt.wantDetail)
+			}
+		})
+	}
+
+	err := errors.Wrapf(internal, errors.Op("test.Foo"), "reading %s: %w", "/foo/bar", eof)
+	if !errors.Is(err, eof) {
+		t.Error("want err to contain eof")
+	}
+	inner := errors.New(invalid, "no file for path", errors.Op("test.Foo"))
+	args := []any{inner}
+	_ = errors.Wrapf(nil, errors.Op("test.Bar"), "loading config: %w", args...)
+	if args[0] != inner || inner.(*errors.Error).Kind != invalid {
+		t.Error("want args to not be modified")
+	}
+}
@@ -0,0 +1,89 @@
+package errors
+
+import (
+	"reflect"
+	"sync"
+)
+
+// ExitCoder is implemented by types that can provide a process exit code.
+// It is satisfied by errors such as fatal.Error and exec.ExitError, and can also be
+// implemented by a Kind to specify the exit code for all errors of that kind.
+type ExitCoder interface {
+	ExitCode() int
+}
+
+var (
+	exitCodesMu sync.RWMutex
+	exitCodes   = make(map[Kind]int)
+)
+
+// RegisterExitCode registers code as the exit code for errors with the given kind.
+// This allows specifying exit codes for kinds that do not implement ExitCoder,
+// or overriding the exit code of kinds that do.
+//
+// kind must be comparable. It is safe to call RegisterExitCode concurrently,
+// however, exit codes should generally be registered during program initialization.
+func RegisterExitCode(kind Kind, code int) {
+	exitCodesMu.Lock()
+	defer exitCodesMu.Unlock()
+	exitCodes[kind] = code
+}
+
+// ExitCode returns the exit code a program should exit with because of err.
+// This allows CLIs to have consistent exit statuses that can be relied upon by scripts.
+//
+// If err is nil, ExitCode returns 0. Otherwise, the errors in err's tree are visited
+// in the order described by Walk, and the first exit code greater than zero that is found
+// is returned. For each error, the exit code is determined by the following, in order:
+//
+//  1. If the error implements ExitCoder, the value of ExitCode.
+//  2. If the error is an *Error with a Kind, the exit code registered for the kind
+//     using RegisterExitCode.
+//  3. If the error is an *Error with a Kind that implements ExitCoder, the value of
+//     the kind's ExitCode.
+//
+// If no exit code is found, ExitCode returns 1, since it is the general catch all error code.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	code := 1
+	Walk(err, func(err error) bool {
+		if ec, ok := err.(ExitCoder); ok {
+			if c := ec.ExitCode(); c > 0 {
+				code = c
+				return false
+			}
+		}
+		e, ok := err.(*Error)
+		if !ok || e.Kind == nil {
+			return true
+		}
+		if c, ok := registeredExitCode(e.Kind); ok && c > 0 {
+			code = c
+			return false
+		}
+		if ec, ok := e.Kind.(ExitCoder); ok {
+			if c := ec.ExitCode(); c > 0 {
+				code = c
+				return false
+			}
+		}
+		return true
+	})
+	return code
+}
+
+// registeredExitCode returns the exit code registered for kind. The lock is only held
+// for the lookup, so that ExitCode methods called by ExitCode can register exit codes.
+func registeredExitCode(kind Kind) (int, bool) {
+	// Kinds that are not comparable can't have been registered,
+	// and would cause a panic when used as a map key.
+	if !reflect.TypeOf(kind).Comparable() {
+		return 0, false
+	}
+	exitCodesMu.RLock()
+	defer exitCodesMu.RUnlock()
+	c, ok := exitCodes[kind]
+	return c, ok
+}
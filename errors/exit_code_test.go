@@ -0,0 +1,85 @@
+package errors_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/TouchBistro/goutils/errors"
+)
+
+type usageKind struct{}
+
+func (usageKind) Kind() string  { return "usage error" }
+func (usageKind) ExitCode() int { return 2 }
+
+type exitError struct {
+	code int
+}
+
+func (e exitError) Error() string { return fmt.Sprintf("exit status %d", e.code) }
+func (e exitError) ExitCode() int { return e.code }
+
+// tagsKind is a Kind that is not comparable.
+type tagsKind []string
+
+func (k tagsKind) Kind() string { return strings.Join(k, ",") }
+
+// registeringKind registers an exit code for itself the first time
+// its exit code is requested.
+type registeringKind struct{}
+
+func (registeringKind) Kind() string { return "registering" }
+
+func (registeringKind) ExitCode() int {
+	errors.RegisterExitCode(registeringKind{}, 70)
+	return 70
+}
+
+func TestExitCode(t *testing.T) {
+	errors.RegisterExitCode(invalid, 64)
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, 0},
+		{"no code", errors.String("oops"), 1},
+		{"kind without code", errors.New(internal, "something blew up", errors.Op("test.Foo")), 1},
+		{"registered kind", errors.New(invalid, "you can't do that", errors.Op("test.Foo")), 64},
+		{"kind exit coder", errors.New(usageKind{}, "missing argument", errors.Op("test.Foo")), 2},
+		{
+			"exit coder in chain",
+			errors.Wrap(exitError{3}, errors.Meta{Kind: internal, Reason: "command failed"}),
+			3,
+		},
+		{
+			"outermost wins",
+			errors.Wrap(exitError{3}, errors.Meta{Kind: usageKind{}, Reason: "command failed"}),
+			2,
+		},
+		{
+			"list",
+			errors.List{errors.String("oops"), errors.New(invalid, "you can't do that", errors.Op("test.Foo"))},
+			64,
+		},
+		{"zero exit code", exitError{0}, 1},
+		{"non-comparable kind", errors.New(tagsKind{"a", "b"}, "oops", errors.Op("test.Foo")), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.ExitCode(tt.err); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExitCodeRegisterInExitCode(t *testing.T) {
+	err := errors.New(registeringKind{}, "oops", errors.Op("test.Foo"))
+	for i := 0; i < 2; i++ {
+		if got := errors.ExitCode(err); got != 70 {
+			t.Errorf("got %d, want 70", got)
+		}
+	}
+}
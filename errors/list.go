@@ -0,0 +1,155 @@
+package errors
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// GroupByKind groups the errors in the list by their kind.
+// The keys of the returned map are the values returned by Kind.Kind.
+//
+// The kind of an error is determined using KindOf, so errors that
+// do not have a kind are grouped under the key "unknown".
+// The order of errors within each group is the same as in e.
+func (e List) GroupByKind() map[string]List {
+	groups := make(map[string]List)
+	for _, err := range e {
+		k := KindOf(err).Kind()
+		groups[k] = append(groups[k], err)
+	}
+	return groups
+}
+
+// KindSummary returns a summary of the number of errors of each kind in the list,
+// for example "internal error (7), invalid operation (3)". This is useful for
+// reporting the result of batch operations that can have many errors.
+//
+// Kinds are ordered by number of errors, from most to least. Kinds with the same
+// number of errors are ordered alphabetically. See GroupByKind for how errors are grouped.
+func (e List) KindSummary() string {
+	type group struct {
+		kind  string
+		count int
+	}
+	var groups []group
+	for k, errs := range e.GroupByKind() {
+		groups = append(groups, group{k, len(errs)})
+	}
+	slices.SortFunc(groups, func(a, b group) int {
+		if a.count != b.count {
+			return b.count - a.count
+		}
+		return strings.Compare(a.kind, b.kind)
+	})
+	var sb strings.Builder
+	for i, g := range groups {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(g.kind)
+		sb.WriteString(" (")
+		sb.WriteString(strconv.Itoa(g.count))
+		sb.WriteByte(')')
+	}
+	return sb.String()
+}
+
+// Filter returns a new List containing only the errors in e for which keep returns true.
+// The order of the errors is preserved. If no errors match, Filter returns nil.
+func (e List) Filter(keep func(error) bool) List {
+	var l List
+	for _, err := range e {
+		if keep(err) {
+			l = append(l, err)
+		}
+	}
+	return l
+}
+
+// Partition splits e into two Lists. match contains the errors for which pred returns true,
+// and rest contains all other errors. The order of the errors is preserved in both lists.
+// This is useful for separating errors that can be recovered from after a batch operation.
+//
+//	retry, failed := errs.Partition(errors.IsRetryable)
+func (e List) Partition(pred func(error) bool) (match, rest List) {
+	for _, err := range e {
+		if pred(err) {
+			match = append(match, err)
+		} else {
+			rest = append(rest, err)
+		}
+	}
+	return match, rest
+}
+
+// Dedup returns an error that formats e with repeated errors aggregated. Errors with identical
+// messages are merged into a single line with a count of occurrences, for example:
+//
+//	connection refused (x142)
+//	unable to parse response
+//
+// Lines are ordered by the first occurrence of each message. This is useful when the same
+// error occurred for many items, which would otherwise create an unreadably long message.
+// When formatted using '%+v', errors are aggregated based on their detailed description.
+//
+// Only formatting is affected, the returned error unwraps to all the errors in e,
+// so Is and As work the same as with e.
+func (e List) Dedup() error {
+	return dedupList{e}
+}
+
+type dedupList struct {
+	errs List
+}
+
+func (d dedupList) Error() string {
+	return d.format(func(err error) string { return err.Error() })
+}
+
+func (d dedupList) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			fmt.Fprint(s, d.format(func(err error) string { return fmt.Sprintf("%+v", err) }))
+			return
+		}
+		fallthrough
+	case 's':
+		fmt.Fprint(s, d.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", d.Error())
+	}
+}
+
+func (d dedupList) Unwrap() []error {
+	return d.errs
+}
+
+// format creates the aggregated description of the errors using msgFunc to get
+// the message of each error.
+func (d dedupList) format(msgFunc func(error) string) string {
+	var msgs []string
+	counts := make(map[string]int)
+	for _, err := range d.errs {
+		msg := msgFunc(err)
+		if counts[msg] == 0 {
+			msgs = append(msgs, msg)
+		}
+		counts[msg]++
+	}
+	var sb strings.Builder
+	for i, msg := range msgs {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(msg)
+		if n := counts[msg]; n > 1 {
+			sb.WriteString(" (x")
+			sb.WriteString(strconv.Itoa(n))
+			sb.WriteByte(')')
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,102 @@
+package errors_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/TouchBistro/goutils/errors"
+)
+
+var batchErrs = errors.List{
+	errors.New(internal, "something blew up", errors.Op("test.Foo")),
+	errors.New(invalid, "you can't do that", errors.Op("test.Foo")),
+	fmt.Errorf("oops: %w", errors.New(internal, "something else blew up", errors.Op("test.Bar"))),
+	errors.String("oops"),
+	errors.New(internal, "it blew up again", errors.Op("test.Baz")),
+}
+
+func TestGroupByKind(t *testing.T) {
+	got := batchErrs.GroupByKind()
+	want := map[string]errors.List{
+		"internal error":    {batchErrs[0], batchErrs[2], batchErrs[4]},
+		"invalid operation": {batchErrs[1]},
+		"unknown":           {batchErrs[3]},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestKindSummary(t *testing.T) {
+	want := "internal error (3), invalid operation (1), unknown (1)"
+	if got := batchErrs.KindSummary(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := (errors.List{}).KindSummary(); got != "" {
+		t.Errorf("got %q, want empty string", got)
+	}
+}
+
+func TestDedup(t *testing.T) {
+	refused := errors.String("connection refused")
+	var errs errors.List
+	for i := 0; i < 142; i++ {
+		errs = append(errs, errors.New(internal, "connection refused", errors.Op("test.Foo")))
+	}
+	errs = append(errs, errors.String("unable to parse response"), refused)
+	errs = append(errs, errors.New(internal, "connection refused", errors.Op("test.Bar")))
+
+	err := errs.Dedup()
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{
+			"%s",
+			"internal error: connection refused (x143)\nunable to parse response\nconnection refused",
+		},
+		{
+			"%+v",
+			"test.Foo: internal error: connection refused (x142)\nunable to parse response\nconnection refused\ntest.Bar: internal error: connection refused",
+		},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprintf(tt.format, err); got != tt.want {
+			t.Errorf("%s: got\n\t%s\nwant\n\t%s", tt.format, got, tt.want)
+		}
+	}
+	if !errors.Is(err, refused) {
+		t.Error("want err to contain refused")
+	}
+	if len(errs) != 145 {
+		t.Errorf("got %d errors, want list to be unchanged", len(errs))
+	}
+}
+
+func TestFilter(t *testing.T) {
+	got := batchErrs.Filter(func(err error) bool {
+		return errors.IsKind(err, internal)
+	})
+	want := errors.List{batchErrs[0], batchErrs[2], batchErrs[4]}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := batchErrs.Filter(func(error) bool { return false }); got != nil {
+		t.Errorf("got %v, want nil", got)
+	}
+}
+
+func TestPartition(t *testing.T) {
+	match, rest := batchErrs.Partition(func(err error) bool {
+		return errors.IsKind(err, internal)
+	})
+	wantMatch := errors.List{batchErrs[0], batchErrs[2], batchErrs[4]}
+	wantRest := errors.List{batchErrs[1], batchErrs[3]}
+	if !reflect.DeepEqual(match, wantMatch) {
+		t.Errorf("got match %v, want %v", match, wantMatch)
+	}
+	if !reflect.DeepEqual(rest, wantRest) {
+		t.Errorf("got rest %v, want %v", rest, wantRest)
+	}
+}
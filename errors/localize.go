@@ -0,0 +1,63 @@
+package errors
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// translator is the function set by SetTranslator.
+var translator atomic.Pointer[func(key string, args ...any) string]
+
+// SetTranslator sets the function used by UserMessage to translate user messages
+// of errors created with Localize. fn is called with the key and args given to Localize
+// and should return the localized message, or an empty string if there is no translation,
+// in which case the canonical message is used.
+//
+// Passing a nil fn removes the translator. It is safe to call SetTranslator concurrently.
+func SetTranslator(fn func(key string, args ...any) string) {
+	if fn == nil {
+		translator.Store(nil)
+		return
+	}
+	translator.Store(&fn)
+}
+
+// Localize returns a copy of err with a localizable user message identified by key.
+// key is a format string for the canonical message, which is formatted with args
+// and stored in Error.UserMessage, so logs and other output that is not localized
+// continue to use the canonical message.
+//
+//	errors.Localize(err, "Could not find user %q.", name)
+//
+// When a translator is set using SetTranslator, UserMessage returns the translated
+// message instead. If err is an *Error, a copy with the message is returned.
+// Otherwise, err is wrapped in an *Error with the message. If err is nil,
+// Localize returns nil.
+func Localize(err error, key string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	var copy Error
+	if e, ok := err.(*Error); ok {
+		copy = *e
+	} else {
+		copy.Err = err
+	}
+	copy.UserMessage = fmt.Sprintf(key, args...)
+	copy.msgKey = key
+	copy.msgArgs = args
+	return &copy
+}
+
+// userMessage returns the user message of e, translating it if possible.
+func (e *Error) userMessage() string {
+	if e.msgKey == "" {
+		return e.UserMessage
+	}
+	if fn := translator.Load(); fn != nil {
+		if msg := (*fn)(e.msgKey, e.msgArgs...); msg != "" {
+			return msg
+		}
+	}
+	return e.UserMessage
+}
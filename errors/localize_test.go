@@ -0,0 +1,54 @@
+package errors_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/TouchBistro/goutils/errors"
+)
+
+func TestLocalize(t *testing.T) {
+	tr := map[string]string{
+		"Could not find user %q.": "Utilisateur %q introuvable.",
+	}
+	base := errors.New(invalid, "no user with id", errors.Op("users.Get"))
+	err := errors.Localize(base, "Could not find user %q.", "bob")
+	if got, want := err.Error(), base.Error(); got != want {
+		t.Errorf("got message %q, want %q", got, want)
+	}
+	if got, want := errors.UserMessage(err), `Could not find user "bob".`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	errors.SetTranslator(func(key string, args ...any) string {
+		if f, ok := tr[key]; ok {
+			return fmt.Sprintf(f, args...)
+		}
+		return ""
+	})
+	defer errors.SetTranslator(nil)
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"translated", err, `Utilisateur "bob" introuvable.`},
+		{"wrapped", errors.Wrap(err, errors.Meta{Op: "api.GetUser"}), `Utilisateur "bob" introuvable.`},
+		{"no translation", errors.Localize(errors.String("oops"), "Something went wrong."), "Something went wrong."},
+		{"not localized", errors.Wrap(nil, errors.Meta{UserMessage: "Try again."}), "Try again."},
+		{"redacted args", errors.Redact(errors.Localize(base, "Could not find user %q.", errors.Sensitive("bob"))), `Utilisateur "[REDACTED]" introuvable.`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.UserMessage(tt.err); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalizeNil(t *testing.T) {
+	if err := errors.Localize(nil, "Something went wrong."); err != nil {
+		t.Errorf("got %v, want nil", err)
+	}
+}
@@ -0,0 +1,68 @@
+package errors
+
+import (
+	"log/slog"
+	"strconv"
+)
+
+// LogValue implements slog.LogValuer. It allows an Error to be logged as a group
+// of structured attributes, instead of a single string, when passed to a slog.Logger.
+//
+//	logger.Error("failed to load config", "err", err)
+//
+// The group contains the full error message as msg, along with the kind, op,
+// reason and fields of the error. If the error wraps another error, it is included
+// as cause. The cause is a group containing the same attributes (except msg) if it is
+// an Error, otherwise it is the message of the cause. Empty attributes are omitted.
+func (e *Error) LogValue() slog.Value {
+	attrs := []slog.Attr{slog.String("msg", e.Error())}
+	return slog.GroupValue(e.appendLogAttrs(attrs)...)
+}
+
+// appendLogAttrs appends the attributes describing e to attrs.
+func (e *Error) appendLogAttrs(attrs []slog.Attr) []slog.Attr {
+	if e.Kind != nil {
+		attrs = append(attrs, slog.String("kind", e.Kind.Kind()))
+	}
+	if e.Op != "" {
+		attrs = append(attrs, slog.String("op", string(e.Op)))
+	}
+	if e.Reason != "" {
+		attrs = append(attrs, slog.String("reason", e.Reason))
+	}
+	if len(e.Fields) > 0 {
+		keys := sortedKeys(e.Fields)
+		fieldAttrs := make([]slog.Attr, len(keys))
+		for i, k := range keys {
+			fieldAttrs[i] = slog.Any(k, e.Fields[k])
+		}
+		attrs = append(attrs, slog.Attr{Key: "fields", Value: slog.GroupValue(fieldAttrs...)})
+	}
+	switch cause := e.Err.(type) {
+	case nil:
+	case *Error:
+		attrs = append(attrs, slog.Attr{Key: "cause", Value: slog.GroupValue(cause.appendLogAttrs(nil)...)})
+	default:
+		attrs = append(attrs, slog.String("cause", cause.Error()))
+	}
+	return attrs
+}
+
+// LogValue implements slog.LogValuer. It allows a List to be logged as a group
+// of structured attributes when passed to a slog.Logger.
+//
+// The group contains an attribute for each error, using the index of the error as the key.
+// Errors that implement slog.LogValuer, such as Error, are logged using their LogValue method,
+// all other errors are logged using their message.
+func (e List) LogValue() slog.Value {
+	attrs := make([]slog.Attr, len(e))
+	for i, err := range e {
+		key := strconv.Itoa(i)
+		if lv, ok := err.(slog.LogValuer); ok {
+			attrs[i] = slog.Any(key, lv)
+		} else {
+			attrs[i] = slog.String(key, err.Error())
+		}
+	}
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,59 @@
+package errors_test
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/TouchBistro/goutils/errors"
+)
+
+func TestLogValue(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "Error",
+			err: errors.Wrap(
+				errors.Wrap(fmt.Errorf("dir not exist"), errors.Meta{
+					Reason: "no file for path",
+					Op:     errors.Op("test.Foo"),
+					Fields: map[string]any{"path": "/foo/bar"},
+				}),
+				errors.Meta{Kind: internal, Reason: "cannot find file", Op: errors.Op("test.Bar")},
+			),
+			want: `level=ERROR msg=failed err.msg="internal error: cannot find file: no file for path: dir not exist" ` +
+				`err.kind="internal error" err.op=test.Bar err.reason="cannot find file" ` +
+				`err.cause.op=test.Foo err.cause.reason="no file for path" err.cause.fields.path=/foo/bar err.cause.cause="dir not exist"` + "\n",
+		},
+		{
+			name: "List",
+			err: errors.List{
+				errors.New(invalid, "you can't do that", errors.Op("test.Foo")),
+				errors.String("oops"),
+			},
+			want: `level=ERROR msg=failed err.0.msg="invalid operation: you can't do that" ` +
+				`err.0.kind="invalid operation" err.0.op=test.Foo err.0.reason="you can't do that" err.1=oops` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&b, &slog.HandlerOptions{
+				ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+					if a.Key == slog.TimeKey {
+						return slog.Attr{}
+					}
+					return a
+				},
+			}))
+			logger.Error("failed", "err", tt.err)
+			if got := b.String(); got != tt.want {
+				t.Errorf("got\n\t%s\nwant\n\t%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package errors
+
+import (
+	"runtime"
+	"strings"
+)
+
+// Must returns v if err is nil, otherwise it panics with an *Error wrapping err.
+// It is intended for use in program initialization and tests where an error
+// cannot be reasonably handled and error handling is just noise.
+//
+//	var tmpl = errors.Must(template.New("name").Parse("text"))
+//
+// The panic value is an *Error with the reason "unexpected error" and the name of
+// the calling function as its op. It also has a stack trace of where Must was called.
+func Must[T any](v T, err error) T {
+	if err != nil {
+		mustPanic(err)
+	}
+	return v
+}
+
+// Must2 is like Must but for functions that return two values and an error.
+func Must2[T, U any](v1 T, v2 U, err error) (T, U) {
+	if err != nil {
+		mustPanic(err)
+	}
+	return v1, v2
+}
+
+// mustPanic panics with an *Error wrapping err. It must be called directly by Must or Must2.
+func mustPanic(err error) {
+	// Skip [runtime.Callers, callers, mustPanic, Must].
+	pcs := callers(4)
+	var op Op
+	if f, _ := runtime.CallersFrames(pcs).Next(); f.Function != "" {
+		// Use the form package.function by trimming the package path.
+		op = Op(f.Function[strings.LastIndexByte(f.Function, '/')+1:])
+	}
+	e := newError(Meta{Reason: "unexpected error", Op: op}, err).(*Error)
+	// The stack already contains the location, don't bother with caller.
+	e.caller = 0
+	e.stack = pcs
+	panic(e)
+}
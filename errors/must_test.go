@@ -0,0 +1,47 @@
+package errors_test
+
+import (
+	"testing"
+
+	"github.com/TouchBistro/goutils/errors"
+)
+
+func parse(s string) (int, error) {
+	if s == "" {
+		return 0, errors.New(invalid, "empty string", errors.Op("test.parse"))
+	}
+	return len(s), nil
+}
+
+func parse2(s string) (int, string, error) {
+	n, err := parse(s)
+	return n, s, err
+}
+
+func TestMust(t *testing.T) {
+	if got := errors.Must(parse("foo")); got != 3 {
+		t.Errorf("got %d, want 3", got)
+	}
+	if n, s := errors.Must2(parse2("foo")); n != 3 || s != "foo" {
+		t.Errorf("got %d, %q; want 3, %q", n, s, "foo")
+	}
+
+	defer func() {
+		r := recover()
+		e, ok := r.(*errors.Error)
+		if !ok {
+			t.Fatalf("got panic value of type %T, want *errors.Error", r)
+		}
+		if got, want := e.Error(), "invalid operation: unexpected error: empty string"; got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+		if got, want := e.Op, errors.Op("errors_test.TestMust"); got != want {
+			t.Errorf("got op %q, want %q", got, want)
+		}
+		if len(errors.Stack(e)) == 0 {
+			t.Error("want error to have a stack trace")
+		}
+	}()
+	errors.Must(parse(""))
+	t.Error("want Must to panic")
+}
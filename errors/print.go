@@ -0,0 +1,122 @@
+package errors
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/TouchBistro/goutils/color"
+)
+
+// Printer prints errors in a human-readable format suitable for terminals.
+// It is a complement to the compact formats provided by '%s' and '%+v'.
+//
+// A zero value Printer is ready for use.
+type Printer struct {
+	// Palette is used to color the output. If nil, color.For is used to
+	// determine whether the writer being printed to supports colors.
+	Palette color.Palette
+	// Stack controls whether stack traces are printed.
+	Stack bool
+}
+
+// FPrint prints err to w using a zero value Printer. See Printer.FPrint.
+func FPrint(w io.Writer, err error) error {
+	var p Printer
+	return p.FPrint(w, err)
+}
+
+// FPrint prints a human-readable description of err to w.
+//
+// Each error in err's chain is printed on its own line, with kinds and ops colored
+// using the current color theme. The errors in a List are printed as an indented list, each with their own chain.
+// If p.Stack is true, the stack trace of err is printed after the chain.
+// Any suggestions added using WithSuggestion are printed last.
+//
+// If err is nil, nothing is printed. Any error encountered while writing to w is returned.
+func (p *Printer) FPrint(w io.Writer, err error) error {
+	if err == nil {
+		return nil
+	}
+	if p.Palette == nil {
+		pp := *p
+		pp.Palette = color.For(w)
+		p = &pp
+	}
+	var sb strings.Builder
+	p.writeChain(&sb, err, "")
+	if frames := Stack(err); p.Stack && len(frames) > 0 {
+		sb.WriteString("stack trace:")
+		sb.Write(appendFrames(nil, frames))
+		sb.WriteByte('\n')
+	}
+	for _, s := range Suggestions(err) {
+		sb.WriteString(p.Palette.Success("hint: "))
+		sb.WriteString(s)
+		sb.WriteByte('\n')
+	}
+	_, werr := io.WriteString(w, sb.String())
+	return werr
+}
+
+// writeChain writes each error in err's chain on its own line. The first line is
+// not indented, since it may be preceded by a list item marker.
+func (p *Printer) writeChain(sb *strings.Builder, err error, indent string) {
+	for printed := false; err != nil; {
+		e, ok := err.(*Error)
+		// Skip errors that only annotate their cause, like ones created by WithStack.
+		if ok && e.Kind == nil && e.Op == "" && e.Reason == "" && len(e.Fields) == 0 && e.Err != nil {
+			err = e.Err
+			continue
+		}
+		if printed {
+			sb.WriteString(indent)
+			sb.WriteString("caused by: ")
+		}
+		printed = true
+		switch e := err.(type) {
+		case *Error:
+			p.writeError(sb, e)
+			sb.WriteByte('\n')
+			err = e.Err
+		case List:
+			sb.WriteString(strconv.Itoa(len(e)))
+			sb.WriteString(" errors occurred:\n")
+			for _, err := range e {
+				sb.WriteString(indent)
+				sb.WriteString("  - ")
+				p.writeChain(sb, err, indent+"    ")
+			}
+			return
+		default:
+			sb.WriteString(err.Error())
+			sb.WriteByte('\n')
+			return
+		}
+	}
+}
+
+// writeError writes the details of e without its cause.
+func (p *Printer) writeError(sb *strings.Builder, e *Error) {
+	start := sb.Len()
+	pad := func(s string) {
+		if sb.Len() > start {
+			sb.WriteString(s)
+		}
+	}
+	if e.Op != "" {
+		sb.WriteString(p.Palette.Info(string(e.Op)))
+	}
+	if e.Kind != nil {
+		pad(": ")
+		sb.WriteString(p.Palette.Error(e.Kind.Kind()))
+	}
+	if e.Reason != "" {
+		pad(": ")
+		sb.WriteString(e.Reason)
+	}
+	if len(e.Fields) > 0 {
+		pad(" ")
+		sb.WriteString(p.Palette.Warning(string(appendFields(nil, e.Fields))))
+	}
+}
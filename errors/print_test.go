@@ -0,0 +1,95 @@
+package errors_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TouchBistro/goutils/color"
+	"github.com/TouchBistro/goutils/errors"
+)
+
+func TestFPrint(t *testing.T) {
+	var c color.Colorer
+	c.SetEnabled(false)
+	p := errors.Printer{Palette: &c}
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"nil", nil, ""},
+		{"string", errors.String("oops"), "oops\n"},
+		{
+			"chain",
+			errors.Wrap(
+				errors.WithStack(errors.Wrap(errors.String("file not found"), errors.Meta{
+					Kind:   internal,
+					Op:     errors.Op("file.Read"),
+					Fields: map[string]any{"path": "/foo"},
+				})),
+				errors.Meta{Kind: invalid, Reason: "source does not exist", Op: errors.Op("config.Read")},
+			),
+			"config.Read: invalid operation: source does not exist\n" +
+				"caused by: file.Read: internal error {path=/foo}\n" +
+				"caused by: file not found\n",
+		},
+		{
+			"list",
+			errors.List{
+				errors.New(invalid, "bad input", errors.Op("test.Foo")),
+				errors.Wrap(errors.List{errors.String("a"), errors.String("b")}, errors.Meta{Reason: "multiple failures"}),
+			},
+			"2 errors occurred:\n" +
+				"  - test.Foo: invalid operation: bad input\n" +
+				"  - multiple failures\n" +
+				"    caused by: 2 errors occurred:\n" +
+				"      - a\n" +
+				"      - b\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := p.FPrint(&sb, tt.err); err != nil {
+				t.Fatalf("want nil error, got %v", err)
+			}
+			if got := sb.String(); got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFPrintColorAndStack(t *testing.T) {
+	var c color.Colorer
+	p := errors.Printer{Palette: &c, Stack: true}
+	var sb strings.Builder
+	err := errors.WithStack(errors.New(internal, "oops", errors.Op("test.Foo")))
+	if err := p.FPrint(&sb, err); err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	got := sb.String()
+	wantPrefix := "\x1b[36mtest.Foo\x1b[39m: \x1b[31minternal error\x1b[39m: oops\nstack trace:\n"
+	if !strings.HasPrefix(got, wantPrefix) {
+		t.Errorf("got\n%q\nwant prefix\n%q", got, wantPrefix)
+	}
+	if !strings.Contains(got, "errors_test.TestFPrintColorAndStack") {
+		t.Errorf("want stack trace to contain test function, got\n%s", got)
+	}
+}
+
+func TestFPrintNoTerminal(t *testing.T) {
+	if color.IsForceColorEnvSet() {
+		t.Skip("colors are forced by the environment")
+	}
+	color.SetEnabled(true)
+	defer color.SetEnabled(false)
+	var sb strings.Builder
+	err := errors.New(internal, "oops", errors.Op("test.Foo"))
+	if err := errors.FPrint(&sb, err); err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	if got, want := sb.String(), "test.Foo: internal error: oops\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
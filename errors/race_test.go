@@ -0,0 +1,7 @@
+//go:build race
+
+package errors_test
+
+// raceEnabled reports whether the race detector is enabled. sync.Pool drops
+// items at random under the race detector, so allocations can't be measured.
+const raceEnabled = true
@@ -0,0 +1,49 @@
+package errors
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// KindPanic is the Kind of errors created by Recover.
+var KindPanic Kind = kind("panic")
+
+// Recover recovers from a panic and converts it into an error which is stored in errp.
+// It must be called directly using defer, otherwise it will not be able to recover.
+//
+//	func (w *worker) run() (err error) {
+//		defer errors.Recover(&err, "worker.run")
+//		...
+//	}
+//
+// The created error is an *Error with KindPanic, the given op, and a stack trace
+// of where the panic occurred. If the panic value is an error it is used as Err,
+// otherwise the value is formatted and used as the Reason.
+//
+// If a panic occurred, the error replaces any error already stored in errp.
+// If no panic occurred, errp is not modified.
+func Recover(errp *error, op Op) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	e := &Error{Kind: KindPanic, Op: op}
+	if err, ok := r.(error); ok {
+		e.Err = err
+	} else {
+		e.Reason = fmt.Sprint(r)
+	}
+	// Skip [runtime.Callers, callers, Recover] and then any runtime frames
+	// so that the stack starts at the function that panicked.
+	pcs := callers(3)
+	for len(pcs) > 0 {
+		fn := runtime.FuncForPC(pcs[0] - 1)
+		if fn == nil || !strings.HasPrefix(fn.Name(), "runtime.") {
+			break
+		}
+		pcs = pcs[1:]
+	}
+	e.stack = pcs
+	*errp = e
+}
@@ -0,0 +1,59 @@
+package errors_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/TouchBistro/goutils/errors"
+)
+
+func panicky(v any) (err error) {
+	defer errors.Recover(&err, errors.Op("test.panicky"))
+	if v != nil {
+		panic(v)
+	}
+	return errors.String("no panic")
+}
+
+func TestRecover(t *testing.T) {
+	cause := errors.String("oops")
+	tests := []struct {
+		name    string
+		v       any
+		want    string
+		wantErr error
+	}{
+		{"string value", "something blew up", "test.panicky: panic: something blew up", nil},
+		{"error value", cause, "test.panicky: panic: oops", cause},
+		{"other value", 42, "test.panicky: panic: 42", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := panicky(tt.v)
+			if !errors.IsKind(err, errors.KindPanic) {
+				t.Errorf("want err to have kind panic, got %v", err)
+			}
+			if got := fmt.Sprintf("%+v", err); !strings.HasPrefix(got, tt.want) {
+				t.Errorf("got\n\t%s\nwant prefix\n\t%s", got, tt.want)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("want err to contain %v", tt.wantErr)
+			}
+			frames := errors.Stack(err)
+			if len(frames) == 0 {
+				t.Fatal("want stack frames, got none")
+			}
+			const fn = "github.com/TouchBistro/goutils/errors_test.panicky"
+			if frames[0].Function != fn {
+				t.Errorf("got first frame %s, want %s", frames[0].Function, fn)
+			}
+		})
+	}
+}
+
+func TestRecoverNoPanic(t *testing.T) {
+	if err := panicky(nil); err != errors.String("no panic") {
+		t.Errorf("got %v, want original error", err)
+	}
+}
@@ -0,0 +1,149 @@
+package errors
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// redacted is the text that replaces sensitive values.
+const redacted = "[REDACTED]"
+
+// Redactor is implemented by values that contain sensitive data.
+// When an error is redacted using Redact, field values that implement
+// Redactor are replaced with the result of calling Redact.
+//
+// An error that implements Redactor will have its message replaced by the
+// result of Redact.
+type Redactor interface {
+	Redact() string
+}
+
+// Sensitive marks v as containing sensitive data. The returned value is formatted
+// the same as v, but it is masked when the error containing it is passed to Redact.
+// It is intended to be used for values in Error.Fields.
+//
+//	errors.Meta{Fields: map[string]any{"token": errors.Sensitive(token)}}
+func Sensitive(v any) any {
+	return sensitive{v}
+}
+
+type sensitive struct {
+	v any
+}
+
+func (s sensitive) String() string {
+	return fmt.Sprint(s.v)
+}
+
+func (s sensitive) Redact() string {
+	return redacted
+}
+
+var (
+	redactMu       sync.RWMutex
+	redactPatterns []*regexp.Regexp
+)
+
+// RegisterRedactPattern registers a regular expression that matches sensitive data.
+// When an error is redacted using Redact, any matches of re in messages and string
+// field values are masked. It is safe to call RegisterRedactPattern concurrently,
+// however, patterns should generally be registered during program initialization.
+func RegisterRedactPattern(re *regexp.Regexp) {
+	redactMu.Lock()
+	defer redactMu.Unlock()
+	redactPatterns = append(redactPatterns, re)
+}
+
+// Redact returns a copy of err with all sensitive data masked.
+// It should be used before errors are logged or returned to clients.
+//
+// Values in Error.Fields that implement Redactor are replaced by the result of calling Redact,
+// and any matches of patterns registered with RegisterRedactPattern are masked in the Reason,
+// UserMessage and string values of Fields. Errors in the chain that are not an *Error or List
+// have their message masked using the registered patterns. If the message is changed, the error
+// is replaced by an error with the masked message that still unwraps to the original error.
+//
+// err is not modified. If err is nil, Redact returns nil.
+func Redact(err error) error {
+	redactMu.RLock()
+	defer redactMu.RUnlock()
+	return redact(err)
+}
+
+// redact is the implementation of Redact. The caller must hold redactMu.
+func redact(err error) error {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case *Error:
+		copy := *e
+		copy.Reason = redactString(e.Reason)
+		copy.UserMessage = redactString(e.UserMessage)
+		if len(e.msgArgs) > 0 {
+			copy.msgArgs = make([]any, len(e.msgArgs))
+			for i, v := range e.msgArgs {
+				copy.msgArgs[i] = redactValue(v)
+			}
+			// Reformat the message since args may have been formatted unmasked.
+			copy.UserMessage = redactString(fmt.Sprintf(e.msgKey, copy.msgArgs...))
+		}
+		if e.Fields != nil {
+			copy.Fields = make(map[string]any, len(e.Fields))
+			for k, v := range e.Fields {
+				copy.Fields[k] = redactValue(v)
+			}
+		}
+		copy.Err = redact(e.Err)
+		return &copy
+	case List:
+		l := make(List, len(e))
+		for i, err := range e {
+			l[i] = redact(err)
+		}
+		return l
+	case Redactor:
+		return &redactedError{msg: e.Redact(), err: err}
+	}
+	msg := err.Error()
+	if s := redactString(msg); s != msg {
+		return &redactedError{msg: s, err: err}
+	}
+	return err
+}
+
+// redactValue masks v if it contains sensitive data. The caller must hold redactMu.
+func redactValue(v any) any {
+	switch v := v.(type) {
+	case Redactor:
+		return v.Redact()
+	case string:
+		return redactString(v)
+	case error:
+		return redact(v)
+	}
+	return v
+}
+
+// redactString masks all matches of the registered patterns in s.
+// The caller must hold redactMu.
+func redactString(s string) string {
+	for _, re := range redactPatterns {
+		s = re.ReplaceAllLiteralString(s, redacted)
+	}
+	return s
+}
+
+// redactedError is an error with a redacted message.
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
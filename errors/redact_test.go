@@ -0,0 +1,61 @@
+package errors_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/TouchBistro/goutils/errors"
+)
+
+func TestRedact(t *testing.T) {
+	errors.RegisterRedactPattern(regexp.MustCompile(`password=\S+`))
+	cause := fmt.Errorf("connect to db with password=hunter2 failed")
+	err := errors.Wrap(cause, errors.Meta{
+		Kind:   internal,
+		Reason: "unable to open connection",
+		Op:     errors.Op("test.Foo"),
+		Fields: map[string]any{
+			"token": errors.Sensitive("abc123"),
+			"dsn":   "postgres://db?password=hunter2",
+			"user":  "admin",
+		},
+	})
+
+	wantOrig := "test.Foo: internal error: unable to open connection {dsn=postgres://db?password=hunter2 token=abc123 user=admin}: connect to db with password=hunter2 failed"
+	if got := fmt.Sprintf("%+v", err); got != wantOrig {
+		t.Errorf("got\n\t%s\nwant\n\t%s", got, wantOrig)
+	}
+
+	redactedErr := errors.Redact(err)
+	want := "test.Foo: internal error: unable to open connection {dsn=postgres://db?[REDACTED] token=[REDACTED] user=admin}: connect to db with [REDACTED] failed"
+	if got := fmt.Sprintf("%+v", redactedErr); got != want {
+		t.Errorf("got\n\t%s\nwant\n\t%s", got, want)
+	}
+	if !errors.Is(redactedErr, cause) {
+		t.Errorf("want redacted error to still match the cause")
+	}
+	// Make sure the original was not modified.
+	if got := fmt.Sprintf("%+v", err); got != wantOrig {
+		t.Errorf("original error was modified, got\n\t%s", got)
+	}
+}
+
+type secretError struct{}
+
+func (secretError) Error() string  { return "secret is s3cr3t" }
+func (secretError) Redact() string { return "secret is [REDACTED]" }
+
+func TestRedactList(t *testing.T) {
+	err := errors.Redact(errors.List{
+		secretError{},
+		errors.String("nothing to hide"),
+	})
+	want := "secret is [REDACTED]\nnothing to hide"
+	if got := err.Error(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if errors.Redact(nil) != nil {
+		t.Errorf("want nil error for nil input")
+	}
+}
@@ -0,0 +1,106 @@
+package errors
+
+import "sync"
+
+// KindDef describes a Kind registered using RegisterKind. It allows declaring
+// a kind along with its metadata once, so that the metadata is consistent
+// everywhere errors of the kind are created.
+type KindDef struct {
+	// Name is the name of the kind, it is returned by the kind's Kind method.
+	// Name must be non-empty and unique among registered kinds.
+	Name string
+	// Message is the default user message for errors of the kind. See UserMessage.
+	Message string
+	// HTTPStatus is the HTTP status code for errors of the kind. See HTTPStatus.
+	HTTPStatus int
+	// ExitCode is the exit code for errors of the kind. See ExitCode.
+	ExitCode int
+}
+
+// registeredKind is a Kind created by RegisterKind.
+// Its metadata is stored in the registry under its name.
+type registeredKind string
+
+func (k registeredKind) Kind() string {
+	return string(k)
+}
+
+func (k registeredKind) ExitCode() int {
+	def, _ := LookupKind(string(k))
+	return def.ExitCode
+}
+
+var (
+	kindsMu sync.RWMutex
+	kinds   = make(map[string]KindDef)
+)
+
+// RegisterKind registers a kind with the metadata in def and returns it.
+// The returned Kind can be used to create errors like any other Kind:
+//
+//	var KindNotFound = errors.RegisterKind(errors.KindDef{
+//		Name:       "not found",
+//		Message:    "The requested resource does not exist.",
+//		HTTPStatus: 404,
+//		ExitCode:   4,
+//	})
+//
+//	errors.New(KindNotFound, "no user with id", "users.Get")
+//
+// RegisterKind panics if def.Name is empty or a kind with the same name is already
+// registered. Kinds should be registered during program initialization.
+func RegisterKind(def KindDef) Kind {
+	if def.Name == "" {
+		panic("errors.RegisterKind: kind name must not be empty")
+	}
+	kindsMu.Lock()
+	defer kindsMu.Unlock()
+	if _, ok := kinds[def.Name]; ok {
+		panic("errors.RegisterKind: kind already registered: " + def.Name)
+	}
+	kinds[def.Name] = def
+	return registeredKind(def.Name)
+}
+
+// LookupKind returns the definition of the kind registered with the given name.
+// The boolean result reports whether a kind with the name is registered.
+func LookupKind(name string) (KindDef, bool) {
+	kindsMu.RLock()
+	defer kindsMu.RUnlock()
+	def, ok := kinds[name]
+	return def, ok
+}
+
+// registeredDef returns the definition of k if it was created by RegisterKind.
+func registeredDef(k Kind) (KindDef, bool) {
+	rk, ok := k.(registeredKind)
+	if !ok {
+		return KindDef{}, false
+	}
+	return LookupKind(string(rk))
+}
+
+// HTTPStatus returns the HTTP status code that should be used to respond with err.
+//
+// If err is nil, HTTPStatus returns 200. Otherwise, the errors in err's tree are visited
+// in the order described by Walk, and the first KindDef.HTTPStatus greater than zero of
+// an *Error with a registered kind is returned. If no status is found, HTTPStatus
+// returns 500, since the error is unexpected.
+func HTTPStatus(err error) int {
+	if err == nil {
+		return 200
+	}
+	status := 500
+	Walk(err, func(err error) bool {
+		e, ok := err.(*Error)
+		if !ok {
+			return true
+		}
+		if def, ok := registeredDef(e.Kind); ok && def.HTTPStatus > 0 {
+			status = def.HTTPStatus
+			return false
+		}
+		return true
+	})
+	return status
+}
@@ -0,0 +1,118 @@
+package errors_test
+
+import (
+	"testing"
+
+	"github.com/TouchBistro/goutils/errors"
+)
+
+var notFound = errors.RegisterKind(errors.KindDef{
+	Name:       "not found",
+	Message:    "The requested resource does not exist.",
+	HTTPStatus: 404,
+	ExitCode:   4,
+})
+
+var conflict = errors.RegisterKind(errors.KindDef{Name: "conflict", HTTPStatus: 409})
+
+func TestRegisterKind(t *testing.T) {
+	err := errors.New(notFound, "no user with id", errors.Op("users.Get"))
+	if got, want := err.Error(), "not found: no user with id"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if !errors.IsKind(err, notFound) {
+		t.Error("want IsKind to report true for registered kind")
+	}
+	def, ok := errors.LookupKind("not found")
+	if !ok {
+		t.Fatal("want kind to be registered")
+	}
+	if def.HTTPStatus != 404 || def.ExitCode != 4 {
+		t.Errorf("got def %+v", def)
+	}
+	if _, ok := errors.LookupKind("missing"); ok {
+		t.Error("want unregistered kind to not be found")
+	}
+}
+
+func TestRegisterKindPanics(t *testing.T) {
+	tests := []struct {
+		name string
+		def  errors.KindDef
+	}{
+		{"empty name", errors.KindDef{}},
+		{"duplicate", errors.KindDef{Name: "not found"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("want RegisterKind to panic")
+				}
+			}()
+			errors.RegisterKind(tt.def)
+		})
+	}
+}
+
+func TestRegisteredKindMetadata(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantCode    int
+		wantMessage string
+	}{
+		{"nil", nil, 200, 0, ""},
+		{"unregistered kind", errors.New(internal, "oops", ""), 500, 1, ""},
+		{
+			"registered kind",
+			errors.New(notFound, "no user", "users.Get"),
+			404,
+			4,
+			"The requested resource does not exist.",
+		},
+		{
+			"wrapped",
+			errors.Wrap(errors.New(notFound, "no user", "users.Get"), errors.Meta{Op: "api.GetUser"}),
+			404,
+			4,
+			"The requested resource does not exist.",
+		},
+		{
+			"explicit user message",
+			errors.Wrap(errors.New(notFound, "no user", "users.Get"), errors.Meta{UserMessage: "No such user."}),
+			404,
+			4,
+			"No such user.",
+		},
+		{"kind without message", errors.New(conflict, "already exists", ""), 409, 1, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.HTTPStatus(tt.err); got != tt.wantStatus {
+				t.Errorf("got status %d, want %d", got, tt.wantStatus)
+			}
+			if got := errors.ExitCode(tt.err); got != tt.wantCode {
+				t.Errorf("got exit code %d, want %d", got, tt.wantCode)
+			}
+			if got := errors.UserMessage(tt.err); got != tt.wantMessage {
+				t.Errorf("got user message %q, want %q", got, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestDecodeRegisteredKind(t *testing.T) {
+	data, err := errors.Encode(errors.New(notFound, "no user", "users.Get"))
+	if err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	decoded := errors.Decode(data)
+	if !errors.IsKind(decoded, notFound) {
+		t.Error("want decoded error to have registered kind")
+	}
+	if got := errors.HTTPStatus(decoded); got != 404 {
+		t.Errorf("got status %d, want 404", got)
+	}
+}
@@ -0,0 +1,67 @@
+package errors
+
+import "fmt"
+
+// Retryable is implemented by errors that can report whether the operation
+// that caused them can be attempted again.
+type Retryable interface {
+	Retryable() bool
+}
+
+// MarkRetryable marks err as retryable. The returned error has the same message
+// and formatting as err, and IsRetryable will report true for it and for any error
+// that wraps it. If err is nil, MarkRetryable returns nil.
+func MarkRetryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &retryableError{err}
+}
+
+// IsRetryable reports whether err is retryable.
+//
+// The first error in err's chain that implements Retryable determines the result.
+// This allows an error to explicitly opt out of being retried even if it wraps an
+// error that was marked as retryable. If no error in the chain implements Retryable,
+// IsRetryable returns false.
+func IsRetryable(err error) bool {
+	var r Retryable
+	if As(err, &r) {
+		return r.Retryable()
+	}
+	return false
+}
+
+// asError returns err if it is an *Error, or the *Error it marks as retryable if it was
+// returned by MarkRetryable. It also reports whether err was marked as retryable.
+// If err is neither, asError returns nil.
+func asError(err error) (*Error, bool) {
+	if r, ok := err.(*retryableError); ok {
+		e, _ := r.err.(*Error)
+		return e, e != nil
+	}
+	e, _ := err.(*Error)
+	return e, false
+}
+
+// retryableError is the error returned by MarkRetryable.
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryableError) Format(s fmt.State, verb rune) {
+	// Format exactly like the underlying error so marking is transparent.
+	fmt.Fprintf(s, fmt.FormatString(s, verb), e.err)
+}
+
+func (e *retryableError) Unwrap() error {
+	return e.err
+}
+
+func (e *retryableError) Retryable() bool {
+	return true
+}
@@ -0,0 +1,102 @@
+package errors_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/TouchBistro/goutils/errors"
+)
+
+// permanentError explicitly opts out of retries.
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return "permanent failure: " + e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+func (permanentError) Retryable() bool { return false }
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "marked",
+			err:  errors.MarkRetryable(errors.String("connection refused")),
+			want: true,
+		},
+		{
+			name: "marked and wrapped",
+			err: errors.Wrap(
+				errors.MarkRetryable(errors.New(internal, "connection refused", errors.Op("test.Foo"))),
+				errors.Meta{Reason: "unable to fetch", Op: errors.Op("test.Bar")},
+			),
+			want: true,
+		},
+		{
+			name: "not marked",
+			err:  errors.New(internal, "something blew up", errors.Op("test.Foo")),
+			want: false,
+		},
+		{
+			name: "opt out",
+			err:  permanentError{errors.MarkRetryable(errors.String("connection refused"))},
+			want: false,
+		},
+		{
+			name: "wrapped opt out",
+			err:  fmt.Errorf("giving up: %w", permanentError{errors.MarkRetryable(errors.String("connection refused"))}),
+			want: false,
+		},
+		{
+			name: "nil",
+			err:  nil,
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.IsRetryable(tt.err); got != tt.want {
+				t.Errorf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkRetryableFormat(t *testing.T) {
+	err := errors.New(internal, "connection refused", errors.Op("test.Foo"))
+	marked := errors.MarkRetryable(err)
+	for _, format := range []string{"%s", "%v", "%+v", "%q"} {
+		if got, want := fmt.Sprintf(format, marked), fmt.Sprintf(format, err); got != want {
+			t.Errorf("%s: got %q, want %q", format, got, want)
+		}
+	}
+	if errors.MarkRetryable(nil) != nil {
+		t.Error("want nil error for nil input")
+	}
+}
+
+func TestMarkRetryableWrapped(t *testing.T) {
+	inner := errors.New(internal, "inner", errors.Op("op.Inner"))
+	wrap := func(err error) error {
+		return errors.Wrap(err, errors.Meta{Kind: internal, Op: errors.Op("op.Outer")})
+	}
+	marked, plain := wrap(errors.MarkRetryable(inner)), wrap(inner)
+	for _, format := range []string{"%s", "%+v"} {
+		if got, want := fmt.Sprintf(format, marked), fmt.Sprintf(format, plain); got != want {
+			t.Errorf("%s: got %q, want %q", format, got, want)
+		}
+	}
+	if got, want := marked.Error(), "internal error: inner"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := fmt.Sprintf("%+v", marked); !strings.Contains(got, "op.Inner") {
+		t.Errorf("got %q, want it to contain op.Inner", got)
+	}
+	if !errors.IsRetryable(marked) {
+		t.Error("want wrapped error to be retryable")
+	}
+}
@@ -0,0 +1,125 @@
+package errors
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+)
+
+// maxStackDepth is the maximum number of frames captured in a stack trace.
+const maxStackDepth = 32
+
+// WithStack annotates err with a stack trace of the current goroutine
+// at the point WithStack was called. The message of err is not changed.
+// The stack trace can be retrieved with Stack and is included when the
+// error is formatted using '%+v'.
+//
+// WithStack works with any error, which makes it useful for adding
+// provenance to errors returned by other packages at package boundaries.
+// If err is an *Error that does not have a stack, a copy with the stack is returned.
+// If err already has a stack it is returned as is, since the existing stack is
+// closer to where the error originated. If err is nil, WithStack returns nil.
+func WithStack(err error) error {
+	if err == nil {
+		return nil
+	}
+	if Stack(err) != nil {
+		return err
+	}
+	// Skip [runtime.Callers, callers, WithStack].
+	pcs := callers(3)
+	if e, ok := err.(*Error); ok {
+		copy := *e
+		copy.stack = pcs
+		return &copy
+	}
+	return &Error{Err: err, stack: pcs}
+}
+
+// Stack returns the stack trace captured by WithStack.
+//
+// The chain of err is searched and the stack from the innermost
+// error that has one is returned, since it is closest to the origin of the error.
+// If no error in the chain has a stack, Stack returns nil.
+func Stack(err error) []runtime.Frame {
+	var pcs []uintptr
+	for err != nil {
+		if e, ok := err.(*Error); ok && e.stack != nil {
+			pcs = e.stack
+		}
+		err = Unwrap(err)
+	}
+	if pcs == nil {
+		return nil
+	}
+	var frames []runtime.Frame
+	fs := runtime.CallersFrames(pcs)
+	for {
+		f, more := fs.Next()
+		frames = append(frames, f)
+		if !more {
+			break
+		}
+	}
+	return frames
+}
+
+// callers returns the program counters of the current goroutine's stack,
+// skipping the given number of frames.
+func callers(skip int) []uintptr {
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(skip, pcs[:])
+	return pcs[:n:n]
+}
+
+var captureCallers atomic.Bool
+
+// SetCaptureCallers sets whether the file and line where an error is created should be recorded.
+// If enabled, errors created by New, Wrap and similar functions record their location, and it
+// is included in the detailed description created by the '%+v' verb, for example:
+//
+//	errors_test.go:12: test.Foo: internal error: something blew up
+//
+// This complements the logical Op with the physical location for faster debugging.
+// Capturing callers has a small cost, so it is disabled by default.
+// It is safe to call SetCaptureCallers concurrently.
+func SetCaptureCallers(enabled bool) {
+	captureCallers.Store(enabled)
+}
+
+// callerPC returns the program counter at the given number of frames to skip
+// (as defined by runtime.Callers) if capturing callers is enabled, otherwise it returns 0.
+func callerPC(skip int) uintptr {
+	if !captureCallers.Load() {
+		return 0
+	}
+	var pcs [1]uintptr
+	if runtime.Callers(skip, pcs[:]) == 0 {
+		return 0
+	}
+	return pcs[0]
+}
+
+// appendCaller appends the file and line for pc to b in the form file:line
+// and returns the extended buffer.
+func appendCaller(b []byte, pc uintptr) []byte {
+	f, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	b = append(b, filepath.Base(f.File)...)
+	b = append(b, ':')
+	return strconv.AppendInt(b, int64(f.Line), 10)
+}
+
+// appendFrames appends a description of each frame to b, similar to the format
+// used by the runtime when a goroutine panics, and returns the extended buffer.
+func appendFrames(b []byte, frames []runtime.Frame) []byte {
+	for _, f := range frames {
+		b = append(b, '\n')
+		b = append(b, f.Function...)
+		b = append(b, "\n\t"...)
+		b = append(b, f.File...)
+		b = append(b, ':')
+		b = strconv.AppendInt(b, int64(f.Line), 10)
+	}
+	return b
+}
@@ -0,0 +1,84 @@
+package errors_test
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/TouchBistro/goutils/errors"
+)
+
+func TestWithStack(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"foreign error", fmt.Errorf("dir not exist")},
+		{"Error", errors.New(internal, "something blew up", errors.Op("test.Foo"))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := errors.WithStack(tt.err)
+			if got, want := err.Error(), tt.err.Error(); got != want {
+				t.Errorf("got message %q, want %q", got, want)
+			}
+			if !errors.Is(err, tt.err) && !errors.IsKind(err, internal) {
+				t.Errorf("want err to match original error")
+			}
+
+			frames := errors.Stack(err)
+			if len(frames) == 0 {
+				t.Fatal("want stack frames, got none")
+			}
+			const fn = "github.com/TouchBistro/goutils/errors_test.TestWithStack.func1"
+			if frames[0].Function != fn {
+				t.Errorf("got first frame %s, want %s", frames[0].Function, fn)
+			}
+
+			detail := fmt.Sprintf("%+v", err)
+			wantPrefix := fmt.Sprintf("%+v", tt.err) + "\n\n" + fn + "\n\t"
+			if !strings.HasPrefix(detail, wantPrefix) {
+				t.Errorf("got detail\n\t%s\nwant prefix\n\t%s", detail, wantPrefix)
+			}
+		})
+	}
+}
+
+func TestWithStackKeepsInnermost(t *testing.T) {
+	inner := errors.WithStack(errors.String("oops"))
+	err := errors.Wrap(inner, errors.Meta{Reason: "wrapped"})
+	if got := errors.WithStack(err); got != err {
+		t.Errorf("want error with existing stack to be returned unchanged")
+	}
+	if got, want := errors.Stack(err), errors.Stack(inner); len(got) != len(want) || got[0] != want[0] {
+		t.Errorf("want stack of inner error")
+	}
+	if errors.Stack(errors.String("oops")) != nil {
+		t.Errorf("want nil stack for error without one")
+	}
+	if errors.WithStack(nil) != nil {
+		t.Errorf("want nil error for nil input")
+	}
+}
+
+func TestSetCaptureCallers(t *testing.T) {
+	errors.SetCaptureCallers(true)
+	t.Cleanup(func() {
+		errors.SetCaptureCallers(false)
+	})
+	_, _, line, _ := runtime.Caller(0)
+	inner := errors.New(internal, "no file for path", errors.Op("test.Foo"))
+	err := errors.Wrap(inner, errors.Meta{Kind: invalid, Reason: "cannot find file", Op: errors.Op("test.Bar")})
+	want := fmt.Sprintf(
+		"stack_test.go:%d: test.Bar: invalid operation: cannot find file:\n\tstack_test.go:%d: test.Foo: internal error: no file for path",
+		line+2, line+1,
+	)
+	if got := fmt.Sprintf("%+v", err); got != want {
+		t.Errorf("got\n\t%s\nwant\n\t%s", got, want)
+	}
+	// Make sure the location does not affect the message.
+	if got, want := err.Error(), "invalid operation: cannot find file: internal error: no file for path"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
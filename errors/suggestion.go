@@ -0,0 +1,48 @@
+package errors
+
+// WithSuggestion returns a copy of err with a suggestion for how to fix it, for example
+// "did you mean --force?". This allows library code to guide users of CLIs toward a fix.
+// Suggestions are included at the end of the output when the error is formatted
+// using '%+v' or printed with FPrint, and they can be retrieved using Suggestions.
+//
+// If err is an *Error, a copy with the suggestion added is returned. Otherwise, err is
+// wrapped in an *Error with the suggestion. If err is nil, WithSuggestion returns nil.
+func WithSuggestion(err error, suggestion string) error {
+	if err == nil {
+		return nil
+	}
+	var copy Error
+	if e, ok := err.(*Error); ok {
+		copy = *e
+		// Make sure appending doesn't modify e's suggestions.
+		copy.suggestions = append(e.suggestions[:len(e.suggestions):len(e.suggestions)], suggestion)
+	} else {
+		copy.Err = err
+		copy.suggestions = []string{suggestion}
+	}
+	return &copy
+}
+
+// Suggestions returns the suggestions added using WithSuggestion to errors in err's chain,
+// ordered from outermost to innermost error. Lists are not descended into since they do
+// not form a single chain. If there are no suggestions, Suggestions returns nil.
+func Suggestions(err error) []string {
+	var suggestions []string
+	for err != nil {
+		if e, ok := err.(*Error); ok {
+			suggestions = append(suggestions, e.suggestions...)
+		}
+		err = Unwrap(err)
+	}
+	return suggestions
+}
+
+// appendSuggestions appends each suggestion to b on its own line
+// and returns the extended buffer.
+func appendSuggestions(b []byte, suggestions []string) []byte {
+	for _, s := range suggestions {
+		b = append(b, "\nhint: "...)
+		b = append(b, s...)
+	}
+	return b
+}
@@ -0,0 +1,52 @@
+package errors_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/TouchBistro/goutils/color"
+	"github.com/TouchBistro/goutils/errors"
+)
+
+func TestWithSuggestion(t *testing.T) {
+	base := errors.New(invalid, "file already exists", errors.Op("file.Create"))
+	err := errors.WithSuggestion(base, "did you mean --force?")
+	err = errors.Wrap(err, errors.Meta{Reason: "cannot write output", Op: errors.Op("cmd.Run")})
+	err = errors.WithSuggestion(errors.WithSuggestion(err, "run with --help for usage"), "see the docs")
+
+	if got, want := err.Error(), "invalid operation: cannot write output: file already exists"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	want := []string{"run with --help for usage", "see the docs", "did you mean --force?"}
+	if got := errors.Suggestions(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := errors.Suggestions(base); got != nil {
+		t.Errorf("want original error to not be modified, got %q", got)
+	}
+
+	wantDetail := "cmd.Run: invalid operation: cannot write output:\n\tfile.Create: file already exists" +
+		"\nhint: run with --help for usage\nhint: see the docs\nhint: did you mean --force?"
+	if got := fmt.Sprintf("%+v", err); got != wantDetail {
+		t.Errorf("got\n%s\nwant\n%s", got, wantDetail)
+	}
+
+	var c color.Colorer
+	c.SetEnabled(false)
+	p := errors.Printer{Palette: &c}
+	var sb strings.Builder
+	if err := p.FPrint(&sb, errors.WithSuggestion(errors.String("unknown flag --forse"), "did you mean --force?")); err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	if got, want := sb.String(), "unknown flag --forse\nhint: did you mean --force?\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestWithSuggestionNil(t *testing.T) {
+	if err := errors.WithSuggestion(nil, "try again"); err != nil {
+		t.Errorf("got %v, want nil", err)
+	}
+}
@@ -0,0 +1,243 @@
+// Command gennorm generates the tables used by the text package for Unicode normalization
+// from UnicodeData.txt and CompositionExclusions.txt of the Unicode Character Database.
+//
+// It is run by go generate in the text package:
+//
+//	go generate ./text
+//
+// By default the files are downloaded from unicode.org. The -ucd flag can be set to a local
+// directory containing the files instead.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	version := flag.String("version", "15.0.0", "version of the Unicode Character Database")
+	ucd := flag.String("ucd", "", "URL or directory of the Unicode Character Database (default is unicode.org)")
+	out := flag.String("o", "normalize_tables.go", "output file")
+	flag.Parse()
+	log.SetFlags(0)
+	log.SetPrefix("gennorm: ")
+
+	if *ucd == "" {
+		*ucd = "https://www.unicode.org/Public/" + *version + "/ucd"
+	}
+	chars, err := parseUnicodeData(*ucd)
+	if err != nil {
+		log.Fatal(err)
+	}
+	exclusions, err := parseCompositionExclusions(*ucd)
+	if err != nil {
+		log.Fatal(err)
+	}
+	src, err := format.Source(generate(*version, chars, exclusions))
+	if err != nil {
+		log.Fatalf("failed to format generated code: %v", err)
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// char contains the properties of a rune needed for normalization.
+type char struct {
+	r   rune
+	ccc uint8
+	// dec is the canonical decomposition of r, the second rune is 0 for singletons.
+	dec [2]rune
+}
+
+// parseUnicodeData returns the runes in UnicodeData.txt that have a non-zero canonical
+// combining class or a canonical decomposition, sorted by rune.
+func parseUnicodeData(ucd string) ([]char, error) {
+	var chars []char
+	err := readLines(ucd, "UnicodeData.txt", func(fields []string) error {
+		if len(fields) < 6 {
+			return fmt.Errorf("want at least 6 fields, got %d", len(fields))
+		}
+		r, err := parseRune(fields[0])
+		if err != nil {
+			return err
+		}
+		ccc, err := strconv.ParseUint(fields[3], 10, 8)
+		if err != nil {
+			return fmt.Errorf("invalid combining class %q: %w", fields[3], err)
+		}
+		c := char{r: r, ccc: uint8(ccc)}
+		// Compatibility decompositions start with a <tag> and are ignored.
+		if d := fields[5]; d != "" && d[0] != '<' {
+			runes := strings.Fields(d)
+			if len(runes) > 2 {
+				return fmt.Errorf("decomposition of %U has %d runes", r, len(runes))
+			}
+			for i, s := range runes {
+				if c.dec[i], err = parseRune(s); err != nil {
+					return err
+				}
+			}
+		}
+		if c.ccc != 0 || c.dec[0] != 0 {
+			chars = append(chars, c)
+		}
+		return nil
+	})
+	sort.Slice(chars, func(i, j int) bool { return chars[i].r < chars[j].r })
+	return chars, err
+}
+
+// parseCompositionExclusions returns the runes listed in CompositionExclusions.txt.
+func parseCompositionExclusions(ucd string) (map[rune]bool, error) {
+	exclusions := make(map[rune]bool)
+	err := readLines(ucd, "CompositionExclusions.txt", func(fields []string) error {
+		r, err := parseRune(fields[0])
+		if err != nil {
+			return err
+		}
+		exclusions[r] = true
+		return nil
+	})
+	return exclusions, err
+}
+
+// readLines calls fn with the semicolon separated fields of each line in the given
+// file of the Unicode Character Database. Comments and empty lines are skipped.
+func readLines(ucd, name string, fn func(fields []string) error) error {
+	var rc io.ReadCloser
+	if strings.HasPrefix(ucd, "http://") || strings.HasPrefix(ucd, "https://") {
+		url := strings.TrimSuffix(ucd, "/") + "/" + name
+		resp, err := http.Get(url)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return fmt.Errorf("failed to download %s: %s", url, resp.Status)
+		}
+		rc = resp.Body
+	} else {
+		f, err := os.Open(filepath.Join(ucd, name))
+		if err != nil {
+			return err
+		}
+		rc = f
+	}
+	defer rc.Close()
+
+	sc := bufio.NewScanner(rc)
+	for n := 1; sc.Scan(); n++ {
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields := strings.Split(line, ";")
+		for i, f := range fields {
+			fields[i] = strings.TrimSpace(f)
+		}
+		if err := fn(fields); err != nil {
+			return fmt.Errorf("%s:%d: %w", name, n, err)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return nil
+}
+
+func parseRune(s string) (rune, error) {
+	r, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid code point %q: %w", s, err)
+	}
+	return rune(r), nil
+}
+
+// generate returns the source of the normalization tables.
+func generate(version string, chars []char, exclusions map[rune]bool) []byte {
+	ccc := make(map[rune]uint8)
+	for _, c := range chars {
+		ccc[c.r] = c.ccc
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by gennorm from the Unicode Character Database, version %s. DO NOT EDIT.\n\n", version)
+	buf.WriteString("package text\n\n")
+
+	buf.WriteString(`// decompositions maps precomposed runes to their canonical decomposition. The first rune
+// may itself be decomposable, and the second rune is 0 for singleton decompositions.
+// Hangul syllables are decomposed algorithmically and are not included.
+var decompositions = map[rune][2]rune{
+`)
+	var excluded []rune
+	var n int
+	for _, c := range chars {
+		if c.dec[0] == 0 {
+			continue
+		}
+		writeItem(&buf, n, 4, fmt.Sprintf("0x%04X: {0x%04X, 0x%04X},", c.r, c.dec[0], c.dec[1]))
+		n++
+		// Runes that decompose to a combining mark are not primary composites,
+		// see the definition of a non-starter decomposition in UAX #15.
+		if c.dec[1] != 0 && (exclusions[c.r] || c.ccc != 0 || ccc[c.dec[0]] != 0) {
+			excluded = append(excluded, c.r)
+		}
+	}
+	buf.WriteString("\n}\n\n")
+
+	buf.WriteString(`// compositionExclusions contains the runes with a decomposition in decompositions that
+// are never produced by composition, either because they are listed in CompositionExclusions.txt
+// or because their decomposition starts with a combining mark.
+var compositionExclusions = map[rune]bool{
+`)
+	for i, r := range excluded {
+		writeItem(&buf, i, 6, fmt.Sprintf("0x%04X: true,", r))
+	}
+	buf.WriteString("\n}\n\n")
+
+	buf.WriteString(`// combiningClasses contains the ranges of runes with a non-zero canonical combining
+// class, sorted by rune. It is used to put combining marks in canonical order.
+var combiningClasses = []combiningClass{
+`)
+	var lo, hi rune
+	var class uint8
+	for i, c := range chars {
+		if c.ccc != 0 && class == c.ccc && c.r == hi+1 {
+			hi = c.r
+		} else {
+			if class != 0 {
+				fmt.Fprintf(&buf, "\t{0x%04X, 0x%04X, %d},\n", lo, hi, class)
+			}
+			lo, hi, class = c.r, c.r, c.ccc
+		}
+		if i == len(chars)-1 && class != 0 {
+			fmt.Fprintf(&buf, "\t{0x%04X, 0x%04X, %d},\n", lo, hi, class)
+		}
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes()
+}
+
+// writeItem writes the i-th item of a table with perLine items on each line.
+func writeItem(buf *bytes.Buffer, i, perLine int, item string) {
+	switch {
+	case i == 0:
+	case i%perLine == 0:
+		buf.WriteString("\n")
+	default:
+		buf.WriteString(" ")
+	}
+	buf.WriteString(item)
+}
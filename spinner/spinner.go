@@ -12,6 +12,8 @@ import (
 	"sync"
 	"time"
 	"unicode/utf8"
+
+	"github.com/TouchBistro/goutils/text"
 )
 
 var frames = [...]string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
@@ -119,7 +121,7 @@ func WithCount(c int) Option {
 }
 
 // WithMaxMessageLength sets the maximum length of the message that is written
-// by the spinner. If the message is longer then this length it will be truncated.
+// by the spinner. If the message is wider than this length it will be truncated.
 // The default max length is 80.
 func WithMaxMessageLength(l int) Option {
 	return func(s *Spinner) {
@@ -218,10 +220,7 @@ func (s *Spinner) setMsg(m string) {
 		m = m[:len(m)-1]
 	}
 	// Truncate msg if it's too long
-	const ellipses = "..."
-	if len(m)-len(ellipses) > s.maxMsgLen {
-		m = m[:s.maxMsgLen-len(ellipses)] + ellipses
-	}
+	m = text.Truncate(m, s.maxMsgLen, "...")
 	// Make sure message has a leading space to pad between it and the spinner icon
 	if m[0] != ' ' {
 		m = " " + m
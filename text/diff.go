@@ -0,0 +1,272 @@
+package text
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/TouchBistro/goutils/color"
+)
+
+// diffContext is the number of unchanged lines shown around each change in a diff.
+const diffContext = 3
+
+// Diff returns a unified diff of the lines in a and b, or an empty string if they are equal.
+// It is equivalent to using a zero value Differ.
+func Diff(a, b string) string {
+	var d Differ
+	return d.Diff(a, b)
+}
+
+// Differ creates unified diffs and allows customizing their output.
+// A zero value Differ is ready for use.
+type Differ struct {
+	// OldName and NewName are used in the header of the diff to label a and b.
+	// They default to "a" and "b".
+	OldName, NewName string
+	// Palette is used to color the diff. Removed lines are colored as errors, added lines
+	// as successes and hunk headers as info. If nil, the diff is not colored.
+	// Use color.For to only color the diff if it will be written to a terminal.
+	Palette color.Palette
+}
+
+// Diff returns a unified diff of the lines in a and b, or an empty string if they are equal.
+// The diff contains up to three lines of context around each change. If a or b does not
+// end with a newline, this is indicated the same way as diff(1).
+func (d *Differ) Diff(a, b string) string {
+	if a == b {
+		return ""
+	}
+	oldName, newName := d.OldName, d.NewName
+	if oldName == "" {
+		oldName = "a"
+	}
+	if newName == "" {
+		newName = "b"
+	}
+	ops := diffLines(splitLines(a), splitLines(b))
+
+	var sb strings.Builder
+	sb.WriteString("--- " + oldName + "\n")
+	sb.WriteString("+++ " + newName + "\n")
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		// Extend the hunk until there is a run of unchanged lines that
+		// is too long to be the context of two consecutive changes.
+		start := max(i-diffContext, 0)
+		end := i
+		for j := i; j < len(ops); j++ {
+			if ops[j].kind != ' ' {
+				end = j + 1
+			} else if j-end >= 2*diffContext {
+				break
+			}
+		}
+		stop := min(end+diffContext, len(ops))
+		d.writeHunk(&sb, ops[start:stop])
+		i = stop
+	}
+	return sb.String()
+}
+
+func (d *Differ) writeHunk(sb *strings.Builder, ops []diffOp) {
+	var oldCount, newCount int
+	for _, op := range ops {
+		if op.kind != '+' {
+			oldCount++
+		}
+		if op.kind != '-' {
+			newCount++
+		}
+	}
+	header := "@@ -" + hunkRange(ops[0].oldPos, oldCount) + " +" + hunkRange(ops[0].newPos, newCount) + " @@"
+	sb.WriteString(d.style('@', header))
+	sb.WriteByte('\n')
+	for _, op := range ops {
+		line, found := strings.CutSuffix(op.line, "\n")
+		sb.WriteString(d.style(op.kind, string(op.kind)+line))
+		sb.WriteByte('\n')
+		if !found {
+			sb.WriteString("\\ No newline at end of file\n")
+		}
+	}
+}
+
+// style colors a line of the diff based on its kind, where '@' is a hunk header.
+func (d *Differ) style(kind byte, line string) string {
+	if d.Palette == nil {
+		return line
+	}
+	switch kind {
+	case '@':
+		return d.Palette.Info(line)
+	case '-':
+		return d.Palette.Error(line)
+	case '+':
+		return d.Palette.Success(line)
+	}
+	return line
+}
+
+// hunkRange formats the range of lines in a hunk header. pos is the number of lines
+// before the hunk. Like diff(1), the count is omitted if it is 1, and if the range is
+// empty the line before it is used as the start.
+func hunkRange(pos, count int) string {
+	switch count {
+	case 0:
+		return strconv.Itoa(pos) + ",0"
+	case 1:
+		return strconv.Itoa(pos + 1)
+	}
+	return strconv.Itoa(pos+1) + "," + strconv.Itoa(count)
+}
+
+// splitLines splits s into lines, each including its trailing newline.
+// The last line does not end with a newline if s does not.
+func splitLines(s string) []string {
+	lines := make([]string, 0, strings.Count(s, "\n")+1)
+	for s != "" {
+		i := strings.IndexByte(s, '\n')
+		if i == -1 {
+			lines = append(lines, s)
+			break
+		}
+		lines = append(lines, s[:i+1])
+		s = s[i+1:]
+	}
+	return lines
+}
+
+// diffOp is a single line of a diff.
+type diffOp struct {
+	kind   byte // ' ' for unchanged, '-' for removed, '+' for added
+	line   string
+	oldPos int // number of lines of the old text before this line
+	newPos int // number of lines of the new text before this line
+}
+
+// diffLines computes the shortest edit script that transforms a into b using the linear space
+// variant of the algorithm described in "An O(ND) Difference Algorithm and Its Variations"
+// by Eugene W. Myers. It uses O(N+M) memory and O((N+M)D) time, where D is the number of
+// changed lines.
+func diffLines(a, b []string) []diffOp {
+	// Compare lines by number, which is faster than comparing strings.
+	ids := make(map[string]int, len(a))
+	intern := func(lines []string) []int {
+		res := make([]int, len(lines))
+		for i, l := range lines {
+			id, ok := ids[l]
+			if !ok {
+				id = len(ids)
+				ids[l] = id
+			}
+			res[i] = id
+		}
+		return res
+	}
+	md := myersDiff{a: a, b: b, ai: intern(a), bi: intern(b)}
+	md.compare(0, len(a), 0, len(b))
+	return md.ops
+}
+
+// myersDiff holds the state for diffLines.
+type myersDiff struct {
+	a, b   []string
+	ai, bi []int // line numbers of a and b
+	ops    []diffOp
+}
+
+// compare appends the edits that transform a[aLo:aHi] into b[bLo:bHi] to md.ops.
+func (md *myersDiff) compare(aLo, aHi, bLo, bHi int) {
+	for aLo < aHi && bLo < bHi && md.ai[aLo] == md.bi[bLo] {
+		md.ops = append(md.ops, diffOp{kind: ' ', line: md.a[aLo], oldPos: aLo, newPos: bLo})
+		aLo++
+		bLo++
+	}
+	suffix := 0
+	for aLo < aHi-suffix && bLo < bHi-suffix && md.ai[aHi-suffix-1] == md.bi[bHi-suffix-1] {
+		suffix++
+	}
+	aHi -= suffix
+	bHi -= suffix
+	switch {
+	case aLo == aHi:
+		for y := bLo; y < bHi; y++ {
+			md.ops = append(md.ops, diffOp{kind: '+', line: md.b[y], oldPos: aLo, newPos: y})
+		}
+	case bLo == bHi:
+		for x := aLo; x < aHi; x++ {
+			md.ops = append(md.ops, diffOp{kind: '-', line: md.a[x], oldPos: x, newPos: bLo})
+		}
+	default:
+		// Since common lines were removed from both ends, at least two lines have changed,
+		// so both halves are smaller than the whole and the recursion ends.
+		x, y, u, v := md.middleSnake(aLo, aHi, bLo, bHi)
+		md.compare(aLo, x, bLo, y)
+		for ; x < u; x, y = x+1, y+1 {
+			md.ops = append(md.ops, diffOp{kind: ' ', line: md.a[x], oldPos: x, newPos: y})
+		}
+		md.compare(u, aHi, v, bHi)
+	}
+	for i := 0; i < suffix; i++ {
+		md.ops = append(md.ops, diffOp{kind: ' ', line: md.a[aHi+i], oldPos: aHi + i, newPos: bHi + i})
+	}
+}
+
+// middleSnake finds the middle snake of a shortest edit script that transforms a[aLo:aHi]
+// into b[bLo:bHi], by searching forwards from the start and backwards from the end at the
+// same time until the searches overlap. It returns the start (x, y) and end (u, v) of the
+// snake, which is a possibly empty run of unchanged lines.
+func (md *myersDiff) middleSnake(aLo, aHi, bLo, bHi int) (x, y, u, v int) {
+	n, m := aHi-aLo, bHi-bLo
+	delta := n - m
+	odd := delta%2 != 0
+	maxD := (n + m + 1) / 2
+	offset := maxD + 1
+	// vf[offset+k] is the furthest x reached on diagonal k = x-y searching forwards, and
+	// vb[offset+k] the furthest distance from the end on diagonal k searching backwards.
+	vf := make([]int, 2*offset+1)
+	vb := make([]int, 2*offset+1)
+	for d := 0; d <= maxD; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || k != d && vf[offset+k-1] < vf[offset+k+1] {
+				x = vf[offset+k+1]
+			} else {
+				x = vf[offset+k-1] + 1
+			}
+			y := x - k
+			x0, y0 := x, y
+			for x < n && y < m && md.ai[aLo+x] == md.bi[bLo+y] {
+				x++
+				y++
+			}
+			vf[offset+k] = x
+			// The backwards search has taken d-1 steps, the diagonal k is delta-k for it.
+			if kb := delta - k; odd && kb >= -(d-1) && kb <= d-1 && x+vb[offset+kb] >= n {
+				return aLo + x0, bLo + y0, aLo + x, bLo + y
+			}
+		}
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || k != d && vb[offset+k-1] < vb[offset+k+1] {
+				x = vb[offset+k+1]
+			} else {
+				x = vb[offset+k-1] + 1
+			}
+			y := x - k
+			x0, y0 := x, y
+			for x < n && y < m && md.ai[aHi-x-1] == md.bi[bHi-y-1] {
+				x++
+				y++
+			}
+			vb[offset+k] = x
+			if kf := delta - k; !odd && kf >= -d && kf <= d && x+vf[offset+kf] >= n {
+				return aHi - x, bHi - y, aHi - x0, bHi - y0
+			}
+		}
+	}
+	panic("unreachable: text: no middle snake found")
+}
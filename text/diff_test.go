@@ -0,0 +1,108 @@
+package text_test
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/TouchBistro/goutils/color"
+	"github.com/TouchBistro/goutils/text"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want string
+	}{
+		{"equal", "a\nb\n", "a\nb\n", ""},
+		{
+			name: "change",
+			a:    "a\nb\nc\n",
+			b:    "a\nB\nc\n",
+			want: "--- a\n+++ b\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			name: "from empty",
+			a:    "",
+			b:    "a\nb\n",
+			want: "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name: "to empty",
+			a:    "a\n",
+			b:    "",
+			want: "--- a\n+++ b\n@@ -1 +0,0 @@\n-a\n",
+		},
+		{
+			name: "context",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n",
+			b:    "1\n2\n3\n4\n5\n6\n7\nx\n",
+			want: "--- a\n+++ b\n@@ -5,4 +5,4 @@\n 5\n 6\n 7\n-8\n+x\n",
+		},
+		{
+			name: "separate hunks",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			b:    "x\n2\n3\n4\n5\n6\n7\n8\n9\ny\n",
+			want: "--- a\n+++ b\n@@ -1,4 +1,4 @@\n-1\n+x\n 2\n 3\n 4\n@@ -7,4 +7,4 @@\n 7\n 8\n 9\n-10\n+y\n",
+		},
+		{
+			name: "merged hunks",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n",
+			b:    "x\n2\n3\n4\n5\n6\n7\ny\n",
+			want: "--- a\n+++ b\n@@ -1,8 +1,8 @@\n-1\n+x\n 2\n 3\n 4\n 5\n 6\n 7\n-8\n+y\n",
+		},
+		{
+			name: "no newline at end",
+			a:    "a\nb",
+			b:    "a\nb\n",
+			want: "--- a\n+++ b\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := text.Diff(tt.a, tt.b); got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDifferOptions(t *testing.T) {
+	var c color.Colorer
+	c.SetLevel(color.LevelTrueColor)
+	d := text.Differ{OldName: "config.yml", NewName: "config.yml (new)", Palette: &c}
+	got := d.Diff("a: 1\n", "a: 2\n")
+	want := "--- config.yml\n+++ config.yml (new)\n" +
+		"\x1b[36m@@ -1 +1 @@\x1b[39m\n\x1b[31m-a: 1\x1b[39m\n\x1b[32m+a: 2\x1b[39m\n"
+	if got != want {
+		t.Errorf("got\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestDiffLarge(t *testing.T) {
+	// Every line is changed, which is the worst case for the algorithm.
+	const n = 10000
+	var a, b strings.Builder
+	for i := 0; i < n; i++ {
+		a.WriteString("old " + strconv.Itoa(i) + "\n")
+		b.WriteString("new " + strconv.Itoa(i) + "\n")
+	}
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	got := text.Diff(a.String(), b.String())
+	runtime.ReadMemStats(&after)
+
+	if !strings.HasPrefix(got, "--- a\n+++ b\n@@ -1,10000 +1,10000 @@\n-old 0\n") {
+		t.Errorf("got diff starting with\n%s", got[:min(len(got), 100)])
+	}
+	if c := strings.Count(got, "\n-old "); c != n {
+		t.Errorf("got %d removed lines, want %d", c, n)
+	}
+	// The memory used must be linear in the size of the input, which is less than 1 MB.
+	if alloc := after.TotalAlloc - before.TotalAlloc; alloc > 64<<20 {
+		t.Errorf("allocated %d MB, want at most 64 MB", alloc>>20)
+	}
+}
@@ -0,0 +1,54 @@
+package text
+
+// Distance returns the Levenshtein distance between a and b, which is the minimum
+// number of single rune insertions, deletions or substitutions needed to change a into b.
+func Distance(a, b string) int {
+	if a == b {
+		return 0
+	}
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) < len(rb) {
+		// Use the shorter string for the row to use less memory
+		ra, rb = rb, ra
+	}
+	row := make([]int, len(rb)+1)
+	for j := range row {
+		row[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		prev := row[0] // the value of row[j-1] from the previous iteration
+		row[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur := min(row[j]+1, row[j-1]+1, prev+cost)
+			prev = row[j]
+			row[j] = cur
+		}
+	}
+	return row[len(rb)]
+}
+
+// SuggestClosest returns the candidate that is closest to input, as measured by Distance.
+// Only candidates that are at most maxDist away from input are considered. If there are
+// no such candidates, an empty string is returned. If multiple candidates are equally close,
+// the first one is returned.
+//
+// This is useful for suggesting a fix for invalid input, for example:
+//
+//	if s := text.SuggestClosest(name, commands, 2); s != "" {
+//		fmt.Printf("unknown command %q, did you mean %q?\n", name, s)
+//	}
+func SuggestClosest(input string, candidates []string, maxDist int) string {
+	closest := ""
+	closestDist := maxDist + 1
+	for _, c := range candidates {
+		if d := Distance(input, c); d < closestDist {
+			closest = c
+			closestDist = d
+		}
+	}
+	return closest
+}
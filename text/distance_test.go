@@ -0,0 +1,57 @@
+package text_test
+
+import (
+	"testing"
+
+	"github.com/TouchBistro/goutils/text"
+)
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"same", "same", 0},
+		{"héllo", "hello", 1},
+		{"日本", "日本語", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := text.Distance(tt.a, tt.b); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+			if got := text.Distance(tt.b, tt.a); got != tt.want {
+				t.Errorf("got %d for reversed args, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestClosest(t *testing.T) {
+	commands := []string{"build", "deploy", "destroy", "status"}
+	tests := []struct {
+		name    string
+		input   string
+		maxDist int
+		want    string
+	}{
+		{"exact", "build", 2, "build"},
+		{"typo", "biuld", 2, "build"},
+		{"closest wins", "destory", 3, "destroy"},
+		{"too far", "xyz", 2, ""},
+		{"first of equals", "dep", 5, "deploy"},
+		{"no candidates", "", 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := text.SuggestClosest(tt.input, commands, tt.maxDist); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
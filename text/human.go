@@ -0,0 +1,93 @@
+package text
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+var (
+	byteUnits  = []string{" B", " KiB", " MiB", " GiB", " TiB", " PiB", " EiB"}
+	countUnits = []string{"", "k", "M", "G", "T", "P", "E"}
+)
+
+// HumanBytes formats n bytes using binary units, for example "512 B" or "1.4 GiB".
+// Values of at least 1 KiB are rounded to one decimal place, which is omitted if it is zero.
+func HumanBytes(n int64) string {
+	return humanize(n, 1024, byteUnits)
+}
+
+// HumanCount formats n using SI suffixes, for example "999", "12.3k" or "4M".
+// Values of at least 1000 are rounded to one decimal place, which is omitted if it is zero.
+func HumanCount(n int64) string {
+	return humanize(n, 1000, countUnits)
+}
+
+// humanize formats n as a multiple of the largest power of base that is not greater than n,
+// with units[i] being the unit for base^i.
+func humanize(n int64, base float64, units []string) string {
+	if n < 0 {
+		// Convert to uint64 first so that the minimum int64 is handled correctly.
+		return "-" + humanizeAbs(float64(uint64(-n)), base, units)
+	}
+	return humanizeAbs(float64(n), base, units)
+}
+
+func humanizeAbs(v, base float64, units []string) string {
+	if v < base {
+		return strconv.FormatFloat(v, 'f', 0, 64) + units[0]
+	}
+	i := 0
+	for v >= base && i < len(units)-1 {
+		v /= base
+		i++
+	}
+	// Round first since rounding can carry over to the next unit, for example 1023.96 KiB.
+	v = float64(int64(v*10+0.5)) / 10
+	if v >= base && i < len(units)-1 {
+		v /= base
+		i++
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64) + units[i]
+}
+
+// HumanDuration formats d in a short form that is precise enough to be useful to a person,
+// for example "250ms", "1.5s", "2m30s", "1h5m" or "3d4h". Smaller units are omitted as the
+// duration gets longer, and a unit is omitted if its value is zero, for example "2m".
+// Durations shorter than a millisecond are formatted the same as time.Duration.String.
+func HumanDuration(d time.Duration) string {
+	if d < 0 {
+		if d == math.MinInt64 {
+			// -d would overflow, the difference is not noticeable in the output.
+			d++
+		}
+		return "-" + HumanDuration(-d)
+	}
+	if d < time.Millisecond {
+		return d.String()
+	}
+	if r := d.Round(time.Millisecond); r < time.Second {
+		return strconv.FormatInt(r.Milliseconds(), 10) + "ms"
+	}
+	if r := d.Round(100 * time.Millisecond); r < time.Minute {
+		return strconv.FormatFloat(r.Seconds(), 'f', -1, 64) + "s"
+	}
+	if r := d.Round(time.Second); r < time.Hour {
+		return joinUnits(int64(r/time.Minute), "m", int64(r%time.Minute/time.Second), "s")
+	}
+	const day = 24 * time.Hour
+	if r := d.Round(time.Minute); r < day {
+		return joinUnits(int64(r/time.Hour), "h", int64(r%time.Hour/time.Minute), "m")
+	}
+	r := d.Round(time.Hour)
+	return joinUnits(int64(r/day), "d", int64(r%day/time.Hour), "h")
+}
+
+// joinUnits formats a value made up of two units, omitting the second if it is zero.
+func joinUnits(a int64, aUnit string, b int64, bUnit string) string {
+	s := strconv.FormatInt(a, 10) + aUnit
+	if b != 0 {
+		s += strconv.FormatInt(b, 10) + bUnit
+	}
+	return s
+}
@@ -0,0 +1,79 @@
+package text_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/TouchBistro/goutils/text"
+)
+
+func TestHumanBytes(t *testing.T) {
+	tests := []struct {
+		in   int64
+		want string
+	}{
+		{0, "0 B"},
+		{512, "512 B"},
+		{1023, "1023 B"},
+		{1024, "1 KiB"},
+		{1536, "1.5 KiB"},
+		{1048575, "1 MiB"},
+		{1503238554, "1.4 GiB"},
+		{-2048, "-2 KiB"},
+		{math.MaxInt64, "8 EiB"},
+		{math.MinInt64, "-8 EiB"},
+	}
+	for _, tt := range tests {
+		if got := text.HumanBytes(tt.in); got != tt.want {
+			t.Errorf("HumanBytes(%d): got %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestHumanCount(t *testing.T) {
+	tests := []struct {
+		in   int64
+		want string
+	}{
+		{0, "0"},
+		{999, "999"},
+		{1000, "1k"},
+		{12345, "12.3k"},
+		{999999, "1M"},
+		{4000000, "4M"},
+		{-1500, "-1.5k"},
+	}
+	for _, tt := range tests {
+		if got := text.HumanCount(tt.in); got != tt.want {
+			t.Errorf("HumanCount(%d): got %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestHumanDuration(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{500 * time.Nanosecond, "500ns"},
+		{1500 * time.Nanosecond, "1.5µs"},
+		{250 * time.Millisecond, "250ms"},
+		{999600 * time.Microsecond, "1s"},
+		{1500 * time.Millisecond, "1.5s"},
+		{59960 * time.Millisecond, "1m"},
+		{150 * time.Second, "2m30s"},
+		{2 * time.Minute, "2m"},
+		{65*time.Minute + 20*time.Second, "1h5m"},
+		{3 * time.Hour, "3h"},
+		{76 * time.Hour, "3d4h"},
+		{-1500 * time.Millisecond, "-1.5s"},
+		{math.MinInt64, "-106751d23h"},
+	}
+	for _, tt := range tests {
+		if got := text.HumanDuration(tt.in); got != tt.want {
+			t.Errorf("HumanDuration(%v): got %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
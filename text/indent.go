@@ -0,0 +1,129 @@
+package text
+
+import "strings"
+
+// Indent adds prefix to the beginning of every line in s. Empty lines are not indented,
+// so that the result does not contain trailing whitespace.
+func Indent(s, prefix string) string {
+	if prefix == "" || s == "" {
+		return s
+	}
+	var sb strings.Builder
+	sb.Grow(len(s) + (strings.Count(s, "\n")+1)*len(prefix))
+	indent(&sb, s, prefix)
+	return sb.String()
+}
+
+// IndentBytes is like Indent but operates on a byte slice. The returned byte slice is a copy
+// of b with the indentation applied, b is not modified. If prefix or b is empty, b is returned as is.
+func IndentBytes(b []byte, prefix string) []byte {
+	if prefix == "" || len(b) == 0 {
+		return b
+	}
+	buf := bytesBuilder(make([]byte, 0, len(b)+(countNewlines(b)+1)*len(prefix)))
+	indent(&buf, b, prefix)
+	return buf
+}
+
+func indent[T string | []byte](w textWriter, s T, prefix string) {
+	for {
+		line, rest, found := cutLine(s)
+		if len(line) > 0 {
+			w.WriteString(prefix)
+			write(w, line)
+		}
+		if !found {
+			return
+		}
+		w.WriteByte('\n')
+		s = rest
+	}
+}
+
+// countNewlines returns the number of newlines in b.
+func countNewlines(b []byte) int {
+	n := 0
+	for _, c := range b {
+		if c == '\n' {
+			n++
+		}
+	}
+	return n
+}
+
+// Dedent removes any common leading whitespace from every line in s.
+// This can be used to embed multi-line strings in source code while keeping them
+// indented along with the code, and still printing them without the indentation.
+//
+// Spaces and tabs are both considered whitespace, but are not equal to each other.
+// Lines that consist solely of whitespace are ignored when determining the common
+// whitespace, and are normalized to empty lines in the result.
+func Dedent(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+	dedent(&sb, s)
+	return sb.String()
+}
+
+// DedentBytes is like Dedent but operates on a byte slice.
+// The returned byte slice is a copy of b with the whitespace removed, b is not modified.
+func DedentBytes(b []byte) []byte {
+	buf := bytesBuilder(make([]byte, 0, len(b)))
+	dedent(&buf, b)
+	return buf
+}
+
+func dedent[T string | []byte](w textWriter, s T) {
+	var margin T
+	hasMargin := false
+	for rest := s; ; {
+		line, next, found := cutLine(rest)
+		if indent, ok := leadingBlanks(line); ok {
+			switch {
+			case !hasMargin:
+				margin = indent
+				hasMargin = true
+			case len(indent) < len(margin) || string(indent[:len(margin)]) != string(margin):
+				margin = commonPrefix(margin, indent)
+			}
+		}
+		if !found {
+			break
+		}
+		rest = next
+	}
+
+	for {
+		line, rest, found := cutLine(s)
+		if _, ok := leadingBlanks(line); ok {
+			write(w, line[len(margin):])
+		}
+		if !found {
+			return
+		}
+		w.WriteByte('\n')
+		s = rest
+	}
+}
+
+// leadingBlanks returns the leading spaces and tabs of line.
+// It reports false if line consists solely of spaces and tabs.
+func leadingBlanks[T string | []byte](line T) (T, bool) {
+	for i := 0; i < len(line); i++ {
+		if !isBlank(line[i]) {
+			return line[:i], true
+		}
+	}
+	return line, false
+}
+
+// commonPrefix returns the longest common prefix of a and b.
+func commonPrefix[T string | []byte](a, b T) T {
+	n := min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return a[:i]
+		}
+	}
+	return a[:n]
+}
@@ -0,0 +1,60 @@
+package text_test
+
+import (
+	"testing"
+
+	"github.com/TouchBistro/goutils/text"
+)
+
+func TestIndent(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		prefix string
+		want   string
+	}{
+		{"empty", "", "  ", ""},
+		{"single line", "foo", "  ", "  foo"},
+		{"multiple lines", "foo\nbar\n", "> ", "> foo\n> bar\n"},
+		{"empty lines", "foo\n\nbar", "\t", "\tfoo\n\n\tbar"},
+		{"empty prefix", "foo\nbar", "", "foo\nbar"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := text.Indent(tt.in, tt.prefix); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if got := text.IndentBytes([]byte(tt.in), tt.prefix); string(got) != tt.want {
+				t.Errorf("IndentBytes: got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDedent(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"empty", "", ""},
+		{"no indent", "foo\nbar", "foo\nbar"},
+		{"common indent", "    foo\n    bar\n", "foo\nbar\n"},
+		{"nested indent", "  foo\n    bar\n  baz", "foo\n  bar\nbaz"},
+		{"blank lines ignored", "\n    foo\n  \n    bar\n", "\nfoo\n\nbar\n"},
+		{"tabs", "\tfoo\n\t\tbar", "foo\n\tbar"},
+		{"mixed tabs and spaces", "\t foo\n\t  bar", "foo\n bar"},
+		{"tabs and spaces differ", "\tfoo\n    bar", "\tfoo\n    bar"},
+		{"only whitespace", "  \n\t", "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := text.Dedent(tt.in); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if got := text.DedentBytes([]byte(tt.in)); string(got) != tt.want {
+				t.Errorf("DedentBytes: got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package text
+
+import (
+	"bufio"
+	"io"
+)
+
+// maxLineSize is the maximum length of a line supported by MapLines and FilterLines.
+const maxLineSize = 1024 * 1024
+
+// MapLines reads lines from r, calls fn with each line, and writes the result to w
+// followed by a newline. Lines passed to fn do not contain the trailing newline or carriage return.
+// This is useful for processing a stream line by line, for example to prefix or redact each line.
+//
+// Lines can be at most 1 MiB long. Any error encountered while reading from r or writing
+// to w is returned.
+func MapLines(r io.Reader, w io.Writer, fn func(line string) string) error {
+	return processLines(r, w, func(line string) (string, bool) {
+		return fn(line), true
+	})
+}
+
+// FilterLines reads lines from r and writes the lines for which keep returns true to w.
+// See MapLines for details.
+func FilterLines(r io.Reader, w io.Writer, keep func(line string) bool) error {
+	return processLines(r, w, func(line string) (string, bool) {
+		return line, keep(line)
+	})
+}
+
+func processLines(r io.Reader, w io.Writer, fn func(line string) (string, bool)) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 4096), maxLineSize)
+	bw := bufio.NewWriter(w)
+	for sc.Scan() {
+		line, ok := fn(sc.Text())
+		if !ok {
+			continue
+		}
+		bw.WriteString(line)
+		if err := bw.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
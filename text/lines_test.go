@@ -0,0 +1,68 @@
+package text_test
+
+import (
+	"bufio"
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/TouchBistro/goutils/text"
+)
+
+func TestMapLines(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"empty", "", ""},
+		{"lines", "a\nb\n", "> a\n> b\n"},
+		{"no trailing newline", "a\nb", "> a\n> b\n"},
+		{"crlf", "a\r\nb\r\n", "> a\n> b\n"},
+		{"blank lines", "a\n\nb\n", "> a\n> \n> b\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			err := text.MapLines(strings.NewReader(tt.in), &sb, func(line string) string {
+				return "> " + line
+			})
+			if err != nil {
+				t.Fatalf("want nil error, got %v", err)
+			}
+			if got := sb.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterLines(t *testing.T) {
+	var sb strings.Builder
+	in := "error: a\ninfo: b\nerror: c\n"
+	err := text.FilterLines(strings.NewReader(in), &sb, func(line string) bool {
+		return strings.HasPrefix(line, "error:")
+	})
+	if err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	if got, want := sb.String(), "error: a\nerror: c\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestMapLinesErrors(t *testing.T) {
+	errRead := errors.New("read failed")
+	var sb strings.Builder
+	err := text.MapLines(iotest.ErrReader(errRead), &sb, func(line string) string { return line })
+	if !errors.Is(err, errRead) {
+		t.Errorf("got error %v, want %v", err, errRead)
+	}
+
+	long := strings.Repeat("x", 2*1024*1024)
+	err = text.MapLines(strings.NewReader(long), &sb, func(line string) string { return line })
+	if !errors.Is(err, bufio.ErrTooLong) {
+		t.Errorf("got error %v, want %v", err, bufio.ErrTooLong)
+	}
+}
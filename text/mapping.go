@@ -0,0 +1,40 @@
+package text
+
+import "os"
+
+// MappingFromMap returns a mapping function that can be used with ExpandVariables
+// and looks up variables in m. Variables that are not in m map to an empty string.
+func MappingFromMap(m map[string]string) func(string) string {
+	return func(name string) string {
+		return m[name]
+	}
+}
+
+// MappingFromEnv returns a mapping function that can be used with ExpandVariables
+// and looks up variables in the environment. Variables that are not set map to an
+// empty string. The environment is read each time a variable is looked up.
+func MappingFromEnv() func(string) string {
+	return os.Getenv
+}
+
+// ChainMappings returns a mapping function that can be used with ExpandVariables and tries
+// each of mappings in order, returning the first non-empty value. This allows declaring
+// lookup precedence, for example explicit values, then the environment, then defaults:
+//
+//	mapping := text.ChainMappings(
+//		text.MappingFromMap(values),
+//		text.MappingFromEnv(),
+//		text.MappingFromMap(defaults),
+//	)
+//
+// If all mappings return an empty string, the result is an empty string.
+func ChainMappings(mappings ...func(string) string) func(string) string {
+	return func(name string) string {
+		for _, mapping := range mappings {
+			if v := mapping(name); v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+}
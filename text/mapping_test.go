@@ -0,0 +1,67 @@
+package text_test
+
+import (
+	"testing"
+
+	"github.com/TouchBistro/goutils/text"
+)
+
+func TestMappingFromMap(t *testing.T) {
+	mapping := text.MappingFromMap(map[string]string{"first": "abc"})
+	if got := mapping("first"); got != "abc" {
+		t.Errorf("got %q, want %q", got, "abc")
+	}
+	if got := mapping("nope"); got != "" {
+		t.Errorf("got %q, want empty string", got)
+	}
+	if got := text.MappingFromMap(nil)("first"); got != "" {
+		t.Errorf("got %q, want empty string", got)
+	}
+}
+
+func TestMappingFromEnv(t *testing.T) {
+	t.Setenv("GOUTILS_TEST_VAR", "abc")
+	mapping := text.MappingFromEnv()
+	if got := mapping("GOUTILS_TEST_VAR"); got != "abc" {
+		t.Errorf("got %q, want %q", got, "abc")
+	}
+	if got := mapping("GOUTILS_TEST_UNSET_VAR"); got != "" {
+		t.Errorf("got %q, want empty string", got)
+	}
+}
+
+func TestChainMappings(t *testing.T) {
+	t.Setenv("GOUTILS_TEST_ENV", "env")
+	t.Setenv("GOUTILS_TEST_BOTH", "env")
+	mapping := text.ChainMappings(
+		text.MappingFromMap(map[string]string{"GOUTILS_TEST_BOTH": "explicit", "GOUTILS_TEST_EMPTY": ""}),
+		text.MappingFromEnv(),
+		text.MappingFromMap(map[string]string{"GOUTILS_TEST_DEFAULT": "default", "GOUTILS_TEST_EMPTY": "default"}),
+	)
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"first wins", "GOUTILS_TEST_BOTH", "explicit"},
+		{"env", "GOUTILS_TEST_ENV", "env"},
+		{"default", "GOUTILS_TEST_DEFAULT", "default"},
+		{"empty skipped", "GOUTILS_TEST_EMPTY", "default"},
+		{"missing", "GOUTILS_TEST_MISSING", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mapping(tt.in); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	got := text.ExpandVariablesString("${GOUTILS_TEST_BOTH}-${GOUTILS_TEST_ENV}", mapping)
+	if want := "explicit-env"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := text.ChainMappings()("GOUTILS_TEST_ENV"); got != "" {
+		t.Errorf("got %q, want empty string", got)
+	}
+}
@@ -0,0 +1,204 @@
+package text
+
+//go:generate go run ../internal/gennorm -o normalize_tables.go
+
+import (
+	"sort"
+	"unicode"
+	"unicode/utf8"
+)
+
+// compositions maps pairs of runes to the rune they compose to. It is the inverse of
+// decompositions, without singletons and composition exclusions.
+var compositions = func() map[[2]rune]rune {
+	m := make(map[[2]rune]rune, len(decompositions))
+	for r, d := range decompositions {
+		if d[1] != 0 && !compositionExclusions[r] {
+			m[d] = r
+		}
+	}
+	return m
+}()
+
+// strokes maps letters with a stroke, which have no decomposition, to their base letter.
+var strokes = map[rune]rune{
+	'ø': 'o', 'Ø': 'O',
+	'đ': 'd', 'Đ': 'D',
+	'ł': 'l', 'Ł': 'L',
+	'ħ': 'h', 'Ħ': 'H',
+	'ŧ': 't', 'Ŧ': 'T',
+}
+
+// combiningClass is a range of runes with the same canonical combining class.
+type combiningClass struct {
+	lo, hi rune
+	ccc    uint8
+}
+
+// Constants for decomposing and composing Hangul syllables algorithmically,
+// as described in section 3.12 of the Unicode Standard.
+const (
+	hangulBase   = 0xAC00 // first syllable
+	hangulLBase  = 0x1100 // first leading consonant
+	hangulVBase  = 0x1161 // first vowel
+	hangulTBase  = 0x11A7 // one before the first trailing consonant
+	hangulLCount = 19
+	hangulVCount = 21
+	hangulTCount = 28
+	hangulNCount = hangulVCount * hangulTCount
+	hangulCount  = hangulLCount * hangulNCount
+)
+
+// NormalizeNFD returns s in Unicode Normalization Form D, where precomposed characters
+// are decomposed into a base character followed by combining marks in canonical order,
+// for example U+00E9 (é) becomes U+0065 U+0301 (e followed by a combining acute accent).
+// Together with NormalizeNFC, this allows strings that are canonically equivalent, that is
+// which have the same appearance and meaning, to be compared regardless of how they were entered.
+func NormalizeNFD(s string) string {
+	if isASCII(s) {
+		return s
+	}
+	return string(decompose(s))
+}
+
+// NormalizeNFC returns s in Unicode Normalization Form C, where characters are decomposed
+// like NormalizeNFD and then replaced by their precomposed form where one exists,
+// for example U+0065 U+0301 becomes U+00E9.
+func NormalizeNFC(s string) string {
+	if isASCII(s) {
+		return s
+	}
+	return string(compose(decompose(s)))
+}
+
+// decompose returns the runes of s fully decomposed and in canonical order.
+func decompose(s string) []rune {
+	runes := make([]rune, 0, len(s))
+	for _, r := range s {
+		runes = appendDecomposed(runes, r)
+	}
+	// Put each sequence of combining marks in canonical order with a stable insertion
+	// sort, since sequences are short and are usually already sorted.
+	for i := 1; i < len(runes); i++ {
+		c := combiningClassOf(runes[i])
+		if c == 0 {
+			continue
+		}
+		for j := i; j > 0; j-- {
+			if p := combiningClassOf(runes[j-1]); p == 0 || p <= c {
+				break
+			}
+			runes[j-1], runes[j] = runes[j], runes[j-1]
+		}
+	}
+	return runes
+}
+
+func appendDecomposed(runes []rune, r rune) []rune {
+	if r >= hangulBase && r < hangulBase+hangulCount {
+		i := r - hangulBase
+		runes = append(runes, hangulLBase+i/hangulNCount, hangulVBase+(i%hangulNCount)/hangulTCount)
+		if t := i % hangulTCount; t != 0 {
+			runes = append(runes, hangulTBase+t)
+		}
+		return runes
+	}
+	d, ok := decompositions[r]
+	if !ok {
+		return append(runes, r)
+	}
+	runes = appendDecomposed(runes, d[0])
+	if d[1] != 0 {
+		runes = appendDecomposed(runes, d[1])
+	}
+	return runes
+}
+
+// compose applies the canonical composition algorithm to runes, which must be decomposed
+// and in canonical order. runes is modified in place and the composed runes are returned.
+func compose(runes []rune) []rune {
+	starter := -1 // index of the last starter, that is the last rune with combining class 0
+	var last uint8
+	n := 0
+	for _, r := range runes {
+		c := combiningClassOf(r)
+		// r can be combined with the last starter if there is nothing between them, or if
+		// it isn't blocked by a rune between them with a greater or equal combining class.
+		if starter >= 0 && (n == starter+1 || (last != 0 && last < c)) {
+			if p, ok := composePair(runes[starter], r); ok {
+				runes[starter] = p
+				continue
+			}
+		}
+		if c == 0 {
+			starter = n
+		}
+		last = c
+		runes[n] = r
+		n++
+	}
+	return runes[:n]
+}
+
+func composePair(a, b rune) (rune, bool) {
+	// Hangul leading consonant and vowel.
+	if a >= hangulLBase && a < hangulLBase+hangulLCount && b >= hangulVBase && b < hangulVBase+hangulVCount {
+		return hangulBase + ((a-hangulLBase)*hangulVCount+b-hangulVBase)*hangulTCount, true
+	}
+	// Hangul syllable without a trailing consonant and a trailing consonant.
+	if a >= hangulBase && a < hangulBase+hangulCount && (a-hangulBase)%hangulTCount == 0 &&
+		b > hangulTBase && b < hangulTBase+hangulTCount {
+		return a + b - hangulTBase, true
+	}
+	r, ok := compositions[[2]rune{a, b}]
+	return r, ok
+}
+
+// combiningClassOf returns the canonical combining class of r.
+func combiningClassOf(r rune) uint8 {
+	// Fast path, there are no combining marks before U+0300.
+	if r < 0x0300 {
+		return 0
+	}
+	i := sort.Search(len(combiningClasses), func(i int) bool {
+		return combiningClasses[i].hi >= r
+	})
+	if i < len(combiningClasses) && combiningClasses[i].lo <= r {
+		return combiningClasses[i].ccc
+	}
+	return 0
+}
+
+// RemoveDiacritics returns s with diacritics removed from letters, for example
+// "Crème Brûlée" becomes "Creme Brulee" and "Łódź" becomes "Lodz". Letters keep their case.
+// It removes all nonspacing combining marks after decomposing s like NormalizeNFD, and
+// returns the result in Normalization Form C. This is useful for comparing and sorting
+// names in a way that ignores accents.
+func RemoveDiacritics(s string) string {
+	if isASCII(s) {
+		return s
+	}
+	runes := decompose(s)
+	n := 0
+	for _, r := range runes {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if base, ok := strokes[r]; ok {
+			r = base
+		}
+		runes[n] = r
+		n++
+	}
+	return string(compose(runes[:n]))
+}
+
+// isASCII reports whether s only contains ASCII characters, which are not affected by normalization.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,209 @@
+package text_test
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/TouchBistro/goutils/text"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		nfc  string
+		nfd  string
+	}{
+		{"ascii", "hello", "hello"},
+		{"acute", "café", "café"},
+		{"uppercase", "Ångström", "Ångström"},
+		{"nested", "Việt", "Việt"},
+		{"cjk unchanged", "日本", "日本"},
+		{"cyrillic", "\u0439\u0451", "\u0438\u0306\u0435\u0308"},
+		{"greek tonos", "\u03ac\u1f00", "\u03b1\u0301\u03b1\u0313"},
+		{"hangul", "\ud55c\uad6d", "\u1112\u1161\u11ab\u1100\u116e\u11a8"},
+		{"hangul without trailing consonant", "\uac00", "\u1100\u1161"},
+		{"ordered marks", "\u1ec7", "\u0065\u0323\u0302"},
+		{"singleton", "\u00c5", "\u0041\u030a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := text.NormalizeNFD(tt.nfc); got != tt.nfd {
+				t.Errorf("NFD: got %q, want %q", got, tt.nfd)
+			}
+			if got := text.NormalizeNFC(tt.nfd); got != tt.nfc {
+				t.Errorf("NFC: got %q, want %q", got, tt.nfc)
+			}
+			if got := text.NormalizeNFC(tt.nfc); got != tt.nfc {
+				t.Errorf("NFC of NFC: got %q, want %q", got, tt.nfc)
+			}
+		})
+	}
+}
+
+func TestNormalizeEquivalent(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		nfc  string
+		nfd  string
+	}{
+		{
+			// Dot below (class 220) is ordered before circumflex (class 230).
+			"reordered marks",
+			"e\u0302\u0323",
+			"e\u0323\u0302",
+			"\u1ec7",
+			"e\u0323\u0302",
+		},
+		{
+			"precomposed and reordered",
+			"\u00ea\u0323",
+			"\u1eb9\u0302",
+			"\u1ec7",
+			"e\u0323\u0302",
+		},
+		{
+			// Marks with the same class keep their order and are not equivalent
+			// when swapped, so only the first one composes.
+			"same class",
+			"a\u0301\u0308",
+			"a\u0301\u0308",
+			"\u00e1\u0308",
+			"a\u0301\u0308",
+		},
+		{
+			"singletons",
+			"\u212b",
+			"\u00c5",
+			"\u00c5",
+			"A\u030a",
+		},
+		{
+			"composition exclusion",
+			"\u0958",
+			"\u0915\u093c",
+			"\u0915\u093c",
+			"\u0915\u093c",
+		},
+		{
+			"hangul jamo",
+			"\u1112\u1161\u11ab",
+			"\ud55c",
+			"\ud55c",
+			"\u1112\u1161\u11ab",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, s := range []string{tt.a, tt.b} {
+				if got := text.NormalizeNFC(s); got != tt.nfc {
+					t.Errorf("NFC(%+q): got %+q, want %+q", s, got, tt.nfc)
+				}
+				if got := text.NormalizeNFD(s); got != tt.nfd {
+					t.Errorf("NFD(%+q): got %+q, want %+q", s, got, tt.nfd)
+				}
+			}
+		})
+	}
+}
+
+// TestNormalizeConformance runs the test cases in testdata/NormalizationTest.txt,
+// see the conformance testing section of UAX #15 for details.
+func TestNormalizeConformance(t *testing.T) {
+	f, err := os.Open("testdata/NormalizationTest.txt")
+	if err != nil {
+		t.Fatalf("failed to open test data: %v", err)
+	}
+	defer f.Close()
+
+	part1 := make(map[rune]bool)
+	var part string
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "@") {
+			part = line
+			continue
+		}
+		fields := strings.Split(line, ";")
+		if len(fields) < 5 {
+			t.Fatalf("line %d: want 5 fields, got %d", n, len(fields))
+		}
+		var c [5]string
+		for i := range c {
+			var sb strings.Builder
+			for _, h := range strings.Fields(fields[i]) {
+				r, err := strconv.ParseUint(h, 16, 32)
+				if err != nil {
+					t.Fatalf("line %d: invalid code point %q", n, h)
+				}
+				sb.WriteRune(rune(r))
+			}
+			c[i] = sb.String()
+		}
+		if part == "@Part1" {
+			r, _ := utf8.DecodeRuneInString(c[0])
+			part1[r] = true
+		}
+		for i, want := range [5]string{c[1], c[1], c[1], c[3], c[3]} {
+			if got := text.NormalizeNFC(c[i]); got != want {
+				t.Errorf("line %d: NFC(c%d): got %+q, want %+q", n, i+1, got, want)
+			}
+		}
+		for i, want := range [5]string{c[2], c[2], c[2], c[4], c[4]} {
+			if got := text.NormalizeNFD(c[i]); got != want {
+				t.Errorf("line %d: NFD(c%d): got %+q, want %+q", n, i+1, got, want)
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		t.Fatalf("failed to read test data: %v", err)
+	}
+
+	// All characters not in part 1 must be unchanged. Only a sample of the Hangul
+	// syllables is included, so they are skipped.
+	for r := rune(0); r <= utf8.MaxRune; r++ {
+		if part1[r] || (r >= 0xD800 && r <= 0xDFFF) || (r >= 0xAC00 && r <= 0xD7A3) {
+			continue
+		}
+		s := string(r)
+		if got := text.NormalizeNFC(s); got != s {
+			t.Errorf("NFC(%U): got %+q, want unchanged", r, got)
+		}
+		if got := text.NormalizeNFD(s); got != s {
+			t.Errorf("NFD(%U): got %+q, want unchanged", r, got)
+		}
+	}
+}
+
+func TestRemoveDiacritics(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"hello", "hello"},
+		{"Crème Brûlée", "Creme Brulee"},
+		{"Łódź", "Lodz"},
+		{"Ærøskøbing", "Æroskobing"},
+		{"café", "cafe"},
+		{"Việt Nam", "Viet Nam"},
+		{"Straße", "Straße"},
+		{"Ёлка", "Елка"},
+		{"Άθηνα", "Αθηνα"},
+		{"한국어", "한국어"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := text.RemoveDiacritics(tt.in); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package text
+
+import (
+	"strings"
+
+	"github.com/TouchBistro/goutils/color"
+)
+
+// PadLeft pads s with spaces on the left so that it is width columns wide when displayed,
+// which right aligns s. Width is measured using color.Width, so ANSI escape sequences
+// are ignored and wide characters are accounted for. If s is already at least width
+// columns wide, it is returned as is.
+func PadLeft(s string, width int) string {
+	n := width - color.Width(s)
+	if n <= 0 {
+		return s
+	}
+	return strings.Repeat(" ", n) + s
+}
+
+// PadRight pads s with spaces on the right so that it is width columns wide when displayed,
+// which left aligns s. See PadLeft for details.
+func PadRight(s string, width int) string {
+	n := width - color.Width(s)
+	if n <= 0 {
+		return s
+	}
+	return s + strings.Repeat(" ", n)
+}
+
+// Center pads s with spaces on both sides so that it is width columns wide when displayed.
+// If the padding cannot be split evenly, the extra space is added on the right.
+// See PadLeft for details.
+func Center(s string, width int) string {
+	n := width - color.Width(s)
+	if n <= 0 {
+		return s
+	}
+	left := n / 2
+	return strings.Repeat(" ", left) + s + strings.Repeat(" ", n-left)
+}
+
+// PadLeftBytes is like PadLeft but operates on a byte slice. The returned byte slice is a copy
+// of b with the padding added, b is not modified. If b is already at least width columns wide,
+// it is returned as is.
+func PadLeftBytes(b []byte, width int) []byte {
+	n := width - color.WidthBytes(b)
+	return padBytes(b, n, 0)
+}
+
+// PadRightBytes is like PadRight but operates on a byte slice. See PadLeftBytes for details.
+func PadRightBytes(b []byte, width int) []byte {
+	n := width - color.WidthBytes(b)
+	return padBytes(b, 0, n)
+}
+
+// CenterBytes is like Center but operates on a byte slice. See PadLeftBytes for details.
+func CenterBytes(b []byte, width int) []byte {
+	n := width - color.WidthBytes(b)
+	return padBytes(b, n/2, n-n/2)
+}
+
+// padBytes returns a copy of b with left spaces before it and right spaces after it.
+// If neither are positive, b is returned as is.
+func padBytes(b []byte, left, right int) []byte {
+	left, right = max(left, 0), max(right, 0)
+	if left == 0 && right == 0 {
+		return b
+	}
+	buf := make([]byte, 0, left+len(b)+right)
+	for i := 0; i < left; i++ {
+		buf = append(buf, ' ')
+	}
+	buf = append(buf, b...)
+	for i := 0; i < right; i++ {
+		buf = append(buf, ' ')
+	}
+	return buf
+}
@@ -0,0 +1,46 @@
+package text_test
+
+import (
+	"testing"
+
+	"github.com/TouchBistro/goutils/text"
+)
+
+func TestPad(t *testing.T) {
+	tests := []struct {
+		name      string
+		in        string
+		width     int
+		wantLeft  string
+		wantRight string
+		wantCtr   string
+	}{
+		{"empty", "", 3, "   ", "   ", "   "},
+		{"ascii", "ab", 5, "   ab", "ab   ", " ab  "},
+		{"already wide", "abcdef", 3, "abcdef", "abcdef", "abcdef"},
+		{"ansi", "\x1b[31mab\x1b[39m", 4, "  \x1b[31mab\x1b[39m", "\x1b[31mab\x1b[39m  ", " \x1b[31mab\x1b[39m "},
+		{"wide runes", "日本", 6, "  日本", "日本  ", " 日本 "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := text.PadLeft(tt.in, tt.width); got != tt.wantLeft {
+				t.Errorf("PadLeft: got %q, want %q", got, tt.wantLeft)
+			}
+			if got := text.PadRight(tt.in, tt.width); got != tt.wantRight {
+				t.Errorf("PadRight: got %q, want %q", got, tt.wantRight)
+			}
+			if got := text.Center(tt.in, tt.width); got != tt.wantCtr {
+				t.Errorf("Center: got %q, want %q", got, tt.wantCtr)
+			}
+			if got := text.PadLeftBytes([]byte(tt.in), tt.width); string(got) != tt.wantLeft {
+				t.Errorf("PadLeftBytes: got %q, want %q", got, tt.wantLeft)
+			}
+			if got := text.PadRightBytes([]byte(tt.in), tt.width); string(got) != tt.wantRight {
+				t.Errorf("PadRightBytes: got %q, want %q", got, tt.wantRight)
+			}
+			if got := text.CenterBytes([]byte(tt.in), tt.width); string(got) != tt.wantCtr {
+				t.Errorf("CenterBytes: got %q, want %q", got, tt.wantCtr)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package text
+
+import "io"
+
+const (
+	// readerBufSize is the initial size of the buffer used to read input in ExpandReader.
+	readerBufSize = 4096
+	// maxPending is the maximum amount of input ExpandReader will buffer while
+	// waiting for the end of a variable reference.
+	maxPending = 64 * 1024
+)
+
+// ExpandVariablesReader reads src from r, replaces ${var} based on the mapping function,
+// and writes the result to w. See ExpandVariables for the supported syntax.
+//
+// The input is expanded as it is read using a small buffer, so r does not need to fit in memory.
+// Because of this, a variable reference that is not closed within 64 KiB of input is left as is.
+// Any error encountered while reading from r or writing to w is returned.
+func ExpandVariablesReader(r io.Reader, w io.Writer, mapping func(string) string) error {
+	var e Expander
+	return e.ExpandReader(r, w, mapping)
+}
+
+// ExpandReader reads src from r, replaces variables based on the mapping function,
+// and writes the result to w. See ExpandVariablesReader for details.
+func (e *Expander) ExpandReader(r io.Reader, w io.Writer, mapping func(string) string) error {
+	m := mapper{fn: mapping}
+	buf := make([]byte, 0, readerBufSize)
+	var out []byte
+	for eof := false; !eof; {
+		if len(buf) == cap(buf) {
+			// The buffer is full of a pending variable reference, grow it so more can be read.
+			nb := make([]byte, len(buf), 2*cap(buf))
+			copy(nb, buf)
+			buf = nb
+		}
+		n, err := r.Read(buf[len(buf):cap(buf)])
+		buf = buf[:len(buf)+n]
+		if err == io.EOF {
+			eof = true
+		} else if err != nil {
+			return err
+		}
+
+		var consumed int
+		out, consumed, err = e.appendExpanded(out[:0], buf, m, eof, len(buf) >= maxPending)
+		if err != nil {
+			return err
+		}
+		if len(out) > 0 {
+			if _, err := w.Write(out); err != nil {
+				return err
+			}
+		}
+		// Keep any pending input so it can be completed by the next read.
+		buf = buf[:copy(buf, buf[consumed:])]
+	}
+	return nil
+}
+
+// appendExpanded appends src with variables expanded to dst. It returns the extended
+// byte slice and the number of bytes of src that were consumed.
+//
+// If atEOF is false, expansion stops before the first variable reference that may be
+// continued by more input. If force is true, a reference at the start of src is treated
+// as complete, which guarantees progress when src is full of pending input.
+func (e *Expander) appendExpanded(dst, src []byte, m mapper, atEOF, force bool) ([]byte, int, error) {
+	start, _, _ := e.delimiters()
+	end := 0
+	for i := 0; i < len(src); i++ {
+		if src[i] != start[0] {
+			continue
+		}
+		ref, res := matchVariable(e, src, i, atEOF || force && i == 0)
+		if res == needMore {
+			return append(dst, src[end:i]...), i, nil
+		}
+		if res == noMatch {
+			continue
+		}
+		v, err := resolveVariable(src, ref, m)
+		if err != nil {
+			return dst, end, err
+		}
+		dst = append(dst, src[end:ref.start]...)
+		dst = append(dst, v...)
+		end = ref.end
+		i = ref.end - 1
+	}
+	return append(dst, src[end:]...), len(src), nil
+}
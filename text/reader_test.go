@@ -0,0 +1,89 @@
+package text_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/TouchBistro/goutils/text"
+)
+
+func TestExpandVariablesReader(t *testing.T) {
+	for _, tt := range expandVariablesTests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := text.ExpandVariablesReader(strings.NewReader(tt.in), &sb, testMapping); err != nil {
+				t.Fatalf("want nil error, got %v", err)
+			}
+			if got := sb.String(); got != tt.out {
+				t.Errorf("got %q, want %q", got, tt.out)
+			}
+		})
+	}
+}
+
+func TestExpandReaderSplitReads(t *testing.T) {
+	// Read one byte at a time so every reference is split across reads.
+	tests := []struct {
+		name string
+		in   string
+		out  string
+	}{
+		{"braced", "a ${HOME} b ${first}", "a /home/foo b abc"},
+		{"default", "${empty:-x}", "x"},
+		{"escaped", "$${HOME} $$", "${HOME} $$"},
+		{"bare", "$HOME/$first", "/home/foo/abc"},
+		{"bare at end", "x $first", "x abc"},
+		{"unclosed", "${HOME $first", "${HOME abc"},
+		{"trailing $", "abc$", "abc$"},
+	}
+	e := text.Expander{Bare: true}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			r := iotest.OneByteReader(strings.NewReader(tt.in))
+			if err := e.ExpandReader(r, &sb, testMapping); err != nil {
+				t.Fatalf("want nil error, got %v", err)
+			}
+			if got := sb.String(); got != tt.out {
+				t.Errorf("got %q, want %q", got, tt.out)
+			}
+		})
+	}
+}
+
+func TestExpandReaderDelimiters(t *testing.T) {
+	e := text.Expander{Start: "{{", End: "}}"}
+	var sb strings.Builder
+	r := iotest.OneByteReader(strings.NewReader("a {{HOME}} {{first} {{second}}{"))
+	if err := e.ExpandReader(r, &sb, testMapping); err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	if got, want := sb.String(), "a /home/foo UNKNOWN_VAR{"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestExpandVariablesReaderLarge(t *testing.T) {
+	// The input is larger than the internal buffer and contains
+	// a reference that is never closed and exceeds the buffer limit.
+	in := strings.Repeat("foo ${first} ", 10000) + "${" + strings.Repeat("x", 100*1024) + " ${second}"
+	want := strings.Repeat("foo abc ", 10000) + "${" + strings.Repeat("x", 100*1024) + " def"
+	var sb strings.Builder
+	if err := text.ExpandVariablesReader(strings.NewReader(in), &sb, testMapping); err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	if got := sb.String(); got != want {
+		t.Errorf("got output of length %d, want length %d", len(got), len(want))
+	}
+}
+
+func TestExpandVariablesReaderError(t *testing.T) {
+	errRead := errors.New("read failed")
+	var sb strings.Builder
+	err := text.ExpandVariablesReader(iotest.ErrReader(errRead), &sb, testMapping)
+	if !errors.Is(err, errRead) {
+		t.Errorf("got error %v, want %v", err, errRead)
+	}
+}
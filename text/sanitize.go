@@ -0,0 +1,92 @@
+package text
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/TouchBistro/goutils/color"
+)
+
+// SanitizeControl makes untrusted text safe to write to a terminal or log by removing
+// ANSI escape sequences and escaping control characters. This protects against input
+// that changes the terminal state, for example by moving the cursor or changing the
+// window title, and against input that forges log lines by including newlines.
+//
+// Control characters are escaped using Go syntax, for example a newline becomes \n
+// and a BEL becomes \x07. Tabs are preserved. This includes the C1 control characters
+// U+0080 to U+009F and bytes that are not valid UTF-8, since some terminals interpret
+// those as control characters as well. Backslashes are not escaped, so the result is
+// meant to be displayed and not to be unescaped. If s does not need to be sanitized,
+// s is returned as is.
+//
+// To keep line breaks in multi-line text, sanitize each line separately, for example using MapLines.
+func SanitizeControl(s string) string {
+	i := 0
+	for i < len(s) {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if needsSanitizing(r, size) {
+			break
+		}
+		i += size
+	}
+	if i == len(s) {
+		return s
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(s) + 8)
+	sb.WriteString(s[:i])
+	for i < len(s) {
+		if n := color.EscapeLen(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if !needsSanitizing(r, size) {
+			sb.WriteString(s[i : i+size])
+			i += size
+			continue
+		}
+		switch {
+		case r == utf8.RuneError:
+			// Invalid UTF-8, escape each byte.
+			writeHexEscape(&sb, `\x`, s[i])
+		case r < utf8.RuneSelf:
+			switch r {
+			case '\a', '\b', '\f', '\n', '\r', '\v':
+				// Use the short form, for example \n.
+				q := strconv.QuoteRune(r)
+				sb.WriteString(q[1 : len(q)-1])
+			default:
+				writeHexEscape(&sb, `\x`, byte(r))
+			}
+		default:
+			writeHexEscape(&sb, `\u00`, byte(r))
+		}
+		i += size
+	}
+	return sb.String()
+}
+
+// needsSanitizing reports whether the rune r, which was decoded from size bytes,
+// must be escaped or removed by SanitizeControl.
+func needsSanitizing(r rune, size int) bool {
+	switch {
+	case r == '\t':
+		return false
+	case r < 0x20 || r == 0x7f:
+		return true
+	case r >= 0x80 && r <= 0x9f:
+		return true
+	}
+	return r == utf8.RuneError && size == 1
+}
+
+// writeHexEscape writes prefix followed by c as two hex digits to sb, for example \x07.
+func writeHexEscape(sb *strings.Builder, prefix string, c byte) {
+	const hex = "0123456789abcdef"
+	sb.WriteString(prefix)
+	sb.WriteByte(hex[c>>4])
+	sb.WriteByte(hex[c&0xf])
+}
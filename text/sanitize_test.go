@@ -0,0 +1,34 @@
+package text_test
+
+import (
+	"testing"
+
+	"github.com/TouchBistro/goutils/text"
+)
+
+func TestSanitizeControl(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"empty", "", ""},
+		{"safe", "hello wörld\t日本 \\path", "hello wörld\t日本 \\path"},
+		{"colors removed", "\x1b[31mred\x1b[39m", "red"},
+		{"osc removed", "a\x1b]0;evil title\ab", "ab"},
+		{"cursor movement removed", "ok\x1b[2J\x1b[Hdone", "okdone"},
+		{"lone escape", "abc\x1b", "abc"},
+		{"newlines", "user\nINFO forged line\r\n", `user\nINFO forged line\r\n`},
+		{"control characters", "a\x00b\x07c\x7f", `a\x00b\ac\x7f`},
+		{"c1 control", "a\u009bb", `a\u009bb`},
+		{"invalid utf8", "a\x9b\xffb", `a\x9b\xffb`},
+		{"replacement char kept", "a�b", "a�b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := text.SanitizeControl(tt.in); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
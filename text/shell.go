@@ -0,0 +1,159 @@
+package text
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnterminatedQuote indicates that text being split contains
+// a quote that does not have a matching closing quote.
+var ErrUnterminatedQuote = errors.New("unterminated quote")
+
+// ShellQuote quotes each argument in args so that it is interpreted as a single word
+// by a POSIX shell, and joins them with spaces. Arguments that only contain characters
+// that have no special meaning to a shell are not quoted, all other arguments are single quoted.
+// This is useful for displaying command lines that can be copied and run.
+func ShellQuote(args []string) string {
+	var sb strings.Builder
+	for i, arg := range args {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		writeShellQuoted(&sb, arg)
+	}
+	return sb.String()
+}
+
+func writeShellQuoted(sb *strings.Builder, arg string) {
+	if arg == "" {
+		sb.WriteString("''")
+		return
+	}
+	safe := true
+	for i := 0; i < len(arg); i++ {
+		if !isShellSafe(arg[i]) {
+			safe = false
+			break
+		}
+	}
+	if safe {
+		sb.WriteString(arg)
+		return
+	}
+	// Single quotes preserve everything literally, except for single quotes which
+	// cannot be escaped inside single quotes. Close the quotes, add an escaped quote,
+	// and reopen them instead.
+	sb.WriteByte('\'')
+	sb.WriteString(strings.ReplaceAll(arg, "'", `'\''`))
+	sb.WriteByte('\'')
+}
+
+// isShellSafe reports whether c has no special meaning to a shell and does not need to be quoted.
+func isShellSafe(c byte) bool {
+	if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' {
+		return true
+	}
+	return strings.IndexByte("@%+=:,./-_", c) != -1
+}
+
+// ShellSplit splits s into words using similar rules to a POSIX shell. It is the inverse of ShellQuote.
+//
+// Words are separated by spaces, tabs and newlines. Characters in single quotes are preserved
+// literally. In double quotes, a backslash only escapes $, `, ", \ and newlines, otherwise it is
+// preserved. Outside of quotes, a backslash escapes the next character, and a # at the start of a
+// word begins a comment that continues to the end of the line. A trailing backslash is preserved.
+//
+// No expansions or substitutions are performed, so variables, globs and command substitutions are
+// returned as is. If s contains an unterminated quote, an error wrapping ErrUnterminatedQuote is returned.
+func ShellSplit(s string) ([]string, error) {
+	return splitWords(s, true)
+}
+
+// SplitFields splits s into fields separated by whitespace, like strings.Fields, but respects
+// quotes and backslash escapes. This is useful for parsing user supplied command strings.
+//
+// Characters in single or double quotes are preserved, including whitespace. Outside of single
+// quotes, a backslash escapes the next character, which allows quotes to be included in a field.
+// A trailing backslash is preserved. Unlike ShellSplit, there are no comments and a backslash in
+// double quotes can escape any character. If s contains an unterminated quote, an error wrapping
+// ErrUnterminatedQuote is returned.
+func SplitFields(s string) ([]string, error) {
+	return splitWords(s, false)
+}
+
+// splitWords splits s into words, respecting quotes and backslash escapes.
+// If shell is true, POSIX shell rules are used for double quotes and comments.
+// Otherwise, a backslash always escapes the next character in double quotes
+// and there are no comments.
+func splitWords(s string, shell bool) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case c == '#' && shell && !inWord:
+			// Skip the comment, the newline ending it will be skipped by the loop.
+			for i+1 < len(s) && s[i+1] != '\n' {
+				i++
+			}
+		case c == '\\':
+			if i+1 == len(s) {
+				inWord = true
+				word.WriteByte(c)
+				break
+			}
+			i++
+			if s[i] == '\n' && shell {
+				// A line continuation is removed entirely and does not start a word.
+				break
+			}
+			inWord = true
+			word.WriteByte(s[i])
+		case c == '\'':
+			inWord = true
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end == -1 {
+				return nil, fmt.Errorf("%w at offset %d", ErrUnterminatedQuote, i)
+			}
+			word.WriteString(s[i+1 : i+1+end])
+			i += end + 1
+		case c == '"':
+			inWord = true
+			start := i
+			for i++; ; i++ {
+				if i == len(s) {
+					return nil, fmt.Errorf("%w at offset %d", ErrUnterminatedQuote, start)
+				}
+				if s[i] == '"' {
+					break
+				}
+				if s[i] == '\\' && i+1 < len(s) {
+					next := s[i+1]
+					if !shell || strings.IndexByte("$`\"\\\n", next) != -1 {
+						i++
+						if next != '\n' || !shell {
+							word.WriteByte(next)
+						}
+						continue
+					}
+				}
+				word.WriteByte(s[i])
+			}
+		default:
+			inWord = true
+			word.WriteByte(c)
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,123 @@
+package text_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/TouchBistro/goutils/text"
+)
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"empty", nil, ""},
+		{"safe", []string{"git", "commit", "--amend", "a/b.txt"}, "git commit --amend a/b.txt"},
+		{"empty arg", []string{"echo", ""}, "echo ''"},
+		{"spaces", []string{"git", "commit", "-m", "a message"}, "git commit -m 'a message'"},
+		{"single quote", []string{"echo", "it's"}, `echo 'it'\''s'`},
+		{"special chars", []string{"echo", "$HOME", "*", "a;b"}, "echo '$HOME' '*' 'a;b'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := text.ShellQuote(tt.args)
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			split, err := text.ShellSplit(got)
+			if err != nil {
+				t.Fatalf("want nil error, got %v", err)
+			}
+			if len(tt.args) > 0 && !reflect.DeepEqual(split, tt.args) {
+				t.Errorf("got %q after splitting, want %q", split, tt.args)
+			}
+		})
+	}
+}
+
+func TestShellSplit(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{"empty", "", nil},
+		{"whitespace", " \t\n", nil},
+		{"words", "git  commit\t-m\nmsg", []string{"git", "commit", "-m", "msg"}},
+		{"single quotes", `echo 'a  b' '$HOME \n'`, []string{"echo", "a  b", `$HOME \n`}},
+		{"double quotes", `echo "a  b" "say \"hi\"" "\$x \n"`, []string{"echo", "a  b", `say "hi"`, `$x \n`}},
+		{"adjacent quotes", `a'b'"c"d`, []string{"abcd"}},
+		{"empty quotes", `echo '' ""`, []string{"echo", "", ""}},
+		{"backslash", `a\ b c\"d`, []string{"a b", `c"d`}},
+		{"line continuation", "a \\\nb", []string{"a", "b"}},
+		{"indented line continuation", "a \\\n  b \\\n", []string{"a", "b"}},
+		{"line continuation in word", "a\\\nb", []string{"ab"}},
+		{"trailing backslash", `a\`, []string{`a\`}},
+		{"comment", "a # comment\nb", []string{"a", "b"}},
+		{"hash in word", "a#b", []string{"a#b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := text.ShellSplit(tt.in)
+			if err != nil {
+				t.Fatalf("want nil error, got %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShellSplitError(t *testing.T) {
+	tests := []struct {
+		in      string
+		wantErr string
+	}{
+		{`echo 'abc`, "unterminated quote at offset 5"},
+		{`echo "abc\"`, "unterminated quote at offset 5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			_, err := text.ShellSplit(tt.in)
+			if !errors.Is(err, text.ErrUnterminatedQuote) || err.Error() != tt.wantErr {
+				t.Errorf("got error %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSplitFields(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{"empty", "", nil},
+		{"fields", "  git commit\t-m  msg ", []string{"git", "commit", "-m", "msg"}},
+		{"quotes", `git commit -m 'a message' "another one"`, []string{"git", "commit", "-m", "a message", "another one"}},
+		{"escapes", `a\ b "c\d" 'e\f'`, []string{"a b", "cd", `e\f`}},
+		{"escaped quotes", `"say \"hi\"" it\'s`, []string{`say "hi"`, "it's"}},
+		{"no comments", "a #b", []string{"a", "#b"}},
+		{"escaped newline", "a\\\nb", []string{"a\nb"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := text.SplitFields(tt.in)
+			if err != nil {
+				t.Fatalf("want nil error, got %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	_, err := text.SplitFields(`a "b`)
+	if !errors.Is(err, text.ErrUnterminatedQuote) {
+		t.Errorf("got error %v, want %v", err, text.ErrUnterminatedQuote)
+	}
+}
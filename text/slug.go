@@ -0,0 +1,77 @@
+package text
+
+import (
+	"strings"
+	"unicode"
+)
+
+// latinFolds maps lowercase Latin letters with diacritics and ligatures to their ASCII equivalents.
+var latinFolds = func() map[rune]string {
+	m := map[rune]string{
+		'ß': "ss",
+		'æ': "ae",
+		'œ': "oe",
+		'þ': "th",
+		'ĳ': "ij",
+	}
+	for _, f := range []struct {
+		base     string
+		variants string
+	}{
+		{"a", "àáâãäåāăą"},
+		{"c", "çćĉċč"},
+		{"d", "ďđð"},
+		{"e", "èéêëēĕėęě"},
+		{"g", "ĝğġģ"},
+		{"h", "ĥħ"},
+		{"i", "ìíîïĩīĭįı"},
+		{"j", "ĵ"},
+		{"k", "ķ"},
+		{"l", "ĺļľŀł"},
+		{"n", "ñńņňŉ"},
+		{"o", "òóôõöøōŏő"},
+		{"r", "ŕŗř"},
+		{"s", "śŝşšſ"},
+		{"t", "ţťŧ"},
+		{"u", "ùúûüũūŭůűų"},
+		{"w", "ŵ"},
+		{"y", "ýÿŷ"},
+		{"z", "źżž"},
+	} {
+		for _, r := range f.variants {
+			m[r] = f.base
+		}
+	}
+	return m
+}()
+
+// Slugify converts s to a slug, which is a lowercase identifier made up of ASCII letters
+// and digits separated by hyphens. This is useful for deriving names of resources from user input.
+//
+// Common Latin letters with diacritics and ligatures are transliterated to ASCII, for example
+// "Crème Brûlée" becomes "creme-brulee". All other characters are treated as separators,
+// consecutive separators are collapsed into a single hyphen, and leading and trailing
+// separators are removed.
+func Slugify(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+	sep := false
+	for _, r := range s {
+		r = unicode.ToLower(r)
+		fold, ok := latinFolds[r]
+		if !ok && !('a' <= r && r <= 'z' || '0' <= r && r <= '9') {
+			sep = true
+			continue
+		}
+		if sep && sb.Len() > 0 {
+			sb.WriteByte('-')
+		}
+		sep = false
+		if ok {
+			sb.WriteString(fold)
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
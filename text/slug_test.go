@@ -0,0 +1,33 @@
+package text_test
+
+import (
+	"testing"
+
+	"github.com/TouchBistro/goutils/text"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{"hello", "hello"},
+		{"Hello World", "hello-world"},
+		{"  --Leading and trailing--  ", "leading-and-trailing"},
+		{"many   spaces___and...dots", "many-spaces-and-dots"},
+		{"Crème Brûlée", "creme-brulee"},
+		{"Straße Ærø Œuvre", "strasse-aero-oeuvre"},
+		{"Łódź Þing", "lodz-thing"},
+		{"v1.2.3", "v1-2-3"},
+		{"日本 cafe", "cafe"},
+		{"emoji 🎉 party", "emoji-party"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := text.Slugify(tt.in); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
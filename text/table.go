@@ -0,0 +1,94 @@
+package text
+
+import (
+	"strings"
+
+	"github.com/TouchBistro/goutils/color"
+)
+
+// defaultSeparator is the separator used between columns if Table.Separator is empty.
+const defaultSeparator = "  "
+
+// Table formats rows of text into aligned columns. Columns are aligned by display width
+// as measured by color.Width, so cells can contain colored text and wide characters.
+//
+// A zero value Table is ready for use.
+type Table struct {
+	// Header is an optional row that is printed before all other rows.
+	Header []string
+	// MaxWidths optionally sets the maximum width of each column.
+	// Cells that are wider are truncated using Truncate. A width of
+	// 0 or a missing width means the column has no maximum.
+	MaxWidths []int
+	// Separator is printed between columns. Defaults to two spaces.
+	Separator string
+
+	rows [][]string
+}
+
+// AddRow adds a row with the given cells to the table.
+// Rows can have different numbers of cells.
+func (t *Table) AddRow(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// String returns the formatted table. Each row is on its own line and ends with a newline.
+// Cells are left aligned and padded with spaces, except for the last cell in each row
+// which is not padded, so that lines do not have trailing whitespace.
+func (t *Table) String() string {
+	rows := t.rows
+	if t.Header != nil {
+		rows = append([][]string{t.Header}, rows...)
+	}
+	return formatColumns(rows, t.MaxWidths, t.Separator)
+}
+
+// Columns formats rows into aligned columns. It is a shorthand for
+// creating a Table and adding each row to it.
+func Columns(rows [][]string) string {
+	return formatColumns(rows, nil, "")
+}
+
+func formatColumns(rows [][]string, maxWidths []int, sep string) string {
+	if sep == "" {
+		sep = defaultSeparator
+	}
+	// Truncate cells first so that widths are computed from the final contents.
+	if len(maxWidths) > 0 {
+		truncated := make([][]string, len(rows))
+		for i, row := range rows {
+			truncated[i] = make([]string, len(row))
+			for j, cell := range row {
+				if j < len(maxWidths) && maxWidths[j] > 0 {
+					cell = Truncate(cell, maxWidths[j], "...")
+				}
+				truncated[i][j] = cell
+			}
+		}
+		rows = truncated
+	}
+	var widths []int
+	for _, row := range rows {
+		for j, cell := range row {
+			if j == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[j] = max(widths[j], color.Width(cell))
+		}
+	}
+
+	var sb strings.Builder
+	for _, row := range rows {
+		for j, cell := range row {
+			if j > 0 {
+				sb.WriteString(sep)
+			}
+			if j < len(row)-1 {
+				cell = PadRight(cell, widths[j])
+			}
+			sb.WriteString(cell)
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
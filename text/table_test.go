@@ -0,0 +1,75 @@
+package text_test
+
+import (
+	"testing"
+
+	"github.com/TouchBistro/goutils/text"
+)
+
+func TestTable(t *testing.T) {
+	tests := []struct {
+		name  string
+		table text.Table
+		rows  [][]string
+		want  string
+	}{
+		{
+			name:  "empty",
+			table: text.Table{},
+			want:  "",
+		},
+		{
+			name:  "aligned",
+			table: text.Table{},
+			rows:  [][]string{{"a", "bbb", "c"}, {"dddd", "e", "f"}},
+			want:  "a     bbb  c\ndddd  e    f\n",
+		},
+		{
+			name:  "header",
+			table: text.Table{Header: []string{"NAME", "STATUS"}},
+			rows:  [][]string{{"postgres", "running"}, {"redis", "stopped"}},
+			want:  "NAME      STATUS\npostgres  running\nredis     stopped\n",
+		},
+		{
+			name:  "max widths",
+			table: text.Table{MaxWidths: []int{6}},
+			rows:  [][]string{{"a-long-name", "x"}, {"short", "y"}},
+			want:  "a-l...  x\nshort   y\n",
+		},
+		{
+			name:  "separator",
+			table: text.Table{Separator: " | "},
+			rows:  [][]string{{"a", "b"}, {"cc", "d"}},
+			want:  "a  | b\ncc | d\n",
+		},
+		{
+			name:  "uneven rows",
+			table: text.Table{},
+			rows:  [][]string{{"a", "b", "c"}, {"dd"}},
+			want:  "a   b  c\ndd\n",
+		},
+		{
+			name:  "ansi and wide runes",
+			table: text.Table{},
+			rows:  [][]string{{"\x1b[32mok\x1b[39m", "x"}, {"日本", "y"}},
+			want:  "\x1b[32mok\x1b[39m    x\n日本  y\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, row := range tt.rows {
+				tt.table.AddRow(row...)
+			}
+			if got := tt.table.String(); got != tt.want {
+				t.Errorf("got\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColumns(t *testing.T) {
+	got := text.Columns([][]string{{"a", "b"}, {"ccc", "d"}})
+	if want := "a    b\nccc  d\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
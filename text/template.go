@@ -0,0 +1,127 @@
+package text
+
+import "strings"
+
+// Template is a parsed template that can be expanded many times with different mappings.
+// Parsing a template once and expanding it repeatedly is faster than calling ExpandVariables
+// each time, since the template does not need to be scanned for variables again.
+//
+// A Template is immutable and safe to use across multiple goroutines.
+type Template struct {
+	src   string
+	parts []templatePart
+}
+
+// templatePart is either a literal piece of text or a variable.
+type templatePart struct {
+	literal string
+	name    string // name of the variable, empty if the part is a literal
+	word    string // default or alternate value, see variableRef
+	op      byte
+}
+
+// Parse parses src into a Template using the default syntax described in ExpandVariables.
+// It is equivalent to using a zero value Expander.
+func Parse(src string) *Template {
+	var e Expander
+	return e.Parse(src)
+}
+
+// Parse parses src into a Template using the syntax supported by e.
+// Changes to e after calling Parse do not affect the returned Template.
+func (e *Expander) Parse(src string) *Template {
+	t := &Template{src: src}
+	end := 0
+	addLiteral := func(s string) {
+		if s == "" {
+			return
+		}
+		// Merge consecutive literals, which happens with escapes.
+		if n := len(t.parts); n > 0 && t.parts[n-1].name == "" {
+			t.parts[n-1].literal += s
+			return
+		}
+		t.parts = append(t.parts, templatePart{literal: s})
+	}
+	for i := 0; i < len(src); {
+		ref, ok := scanVariable(e, src, i)
+		if !ok {
+			break
+		}
+		addLiteral(src[end:ref.start])
+		if ref.escape {
+			addLiteral("$")
+		} else {
+			t.parts = append(t.parts, templatePart{
+				name: src[ref.nameStart:ref.nameEnd],
+				word: src[ref.wordStart:ref.wordEnd],
+				op:   ref.op,
+			})
+		}
+		i = ref.end
+		end = ref.end
+	}
+	addLiteral(src[end:])
+	return t
+}
+
+// String returns the source text the template was parsed from.
+func (t *Template) String() string {
+	return t.src
+}
+
+// Variables returns the unique names of all variables referenced in the template
+// in the order they first appear. See ListVariables for details.
+func (t *Template) Variables() []string {
+	var names []string
+	for _, p := range t.parts {
+		if p.name != "" {
+			names = appendUnique(names, p.name)
+		}
+	}
+	return names
+}
+
+// Expand expands the template using the mapping function.
+// See ExpandVariables for details.
+func (t *Template) Expand(mapping func(string) string) string {
+	s, _ := t.expand(mapper{fn: mapping})
+	return s
+}
+
+// ExpandErr is like Expand but uses a mapping function that can fail.
+// See ExpandVariablesErr for details.
+func (t *Template) ExpandErr(mapping func(string) (string, error)) (string, error) {
+	return t.expand(mapper{fnErr: mapping})
+}
+
+// ExpandStrict is like Expand but fails if any variables are not defined.
+// See ExpandVariablesStrict for details.
+func (t *Template) ExpandStrict(lookup func(string) (string, bool)) (string, error) {
+	var missing []string
+	s, err := t.expand(mapper{fnLookup: lookup, missing: &missing})
+	if err == nil && len(missing) > 0 {
+		return "", &MissingVariablesError{Names: missing}
+	}
+	return s, err
+}
+
+func (t *Template) expand(m mapper) (string, error) {
+	if len(t.parts) == 1 && t.parts[0].name == "" {
+		return t.parts[0].literal, nil
+	}
+	var sb strings.Builder
+	sb.Grow(2 * len(t.src))
+	for _, p := range t.parts {
+		if p.name == "" {
+			sb.WriteString(p.literal)
+			continue
+		}
+		v, err := m.resolve(p.name, p.op, p.word)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(v)
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,66 @@
+package text_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/TouchBistro/goutils/text"
+)
+
+func TestTemplate(t *testing.T) {
+	for _, tt := range expandVariablesTests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := text.Parse(tt.in)
+			if got := tmpl.Expand(testMapping); got != tt.out {
+				t.Errorf("got %q, want %q", got, tt.out)
+			}
+			// Expanding again must produce the same result.
+			if got := tmpl.Expand(testMapping); got != tt.out {
+				t.Errorf("got %q on second expansion, want %q", got, tt.out)
+			}
+			if got := tmpl.String(); got != tt.in {
+				t.Errorf("got source %q, want %q", got, tt.in)
+			}
+		})
+	}
+}
+
+func TestTemplateExpander(t *testing.T) {
+	e := text.Expander{Bare: true}
+	tmpl := e.Parse("$first-${second:-x}")
+	if got, want := tmpl.Expand(testMapping), "abc-def"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	got := tmpl.Expand(func(name string) string { return "" })
+	if want := "-x"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestTemplateExpandErr(t *testing.T) {
+	tmpl := text.Parse("${first} ${fail}")
+	_, err := tmpl.ExpandErr(testMappingErr)
+	if !errors.Is(err, errFail) {
+		t.Errorf("got error %v, want %v", err, errFail)
+	}
+}
+
+func TestTemplateExpandStrict(t *testing.T) {
+	tmpl := text.Parse("${a} ${first} ${b:-x} ${c} ${d:+y}")
+	_, err := tmpl.ExpandStrict(testLookup)
+	var mErr *text.MissingVariablesError
+	if !errors.As(err, &mErr) {
+		t.Fatalf("got error %v, want *MissingVariablesError", err)
+	}
+	if want := []string{"a", "c"}; !reflect.DeepEqual(mErr.Names, want) {
+		t.Errorf("got missing %v, want %v", mErr.Names, want)
+	}
+}
+
+func BenchmarkTemplate(b *testing.B) {
+	tmpl := text.Parse("${foo} ${foo} ${foo} ${foo}")
+	for i := 0; i < b.N; i++ {
+		tmpl.Expand(func(s string) string { return "bar" })
+	}
+}
@@ -3,108 +3,469 @@
 package text
 
 import (
+	"fmt"
 	"strings"
+	"unicode/utf8"
+
+	"github.com/TouchBistro/goutils/color"
 )
 
 // ExpandVariables replaces ${var} in the byte slice based on the mapping function.
 // The returned byte slice is a copy of src with the replacements made, src is not modified.
 // If src contains no variables, src is returned as is.
+//
+// A variable can specify a default value using the form ${var:-default}. The default
+// is used if mapping returns an empty string. The default is used as is, it is not expanded.
+//
+// A variable can specify an alternate value using the form ${var:+alternate}. The alternate
+// is used if mapping returns a non-empty string, otherwise the variable expands to an empty
+// string. This is useful for including text only if a variable is set, for example
+// ${TAG:+--tag=latest}. Like defaults, the alternate is used as is.
+//
+// A literal ${ can be produced by escaping it as $${, for example $${var} expands to ${var}.
+//
+// ExpandVariables is equivalent to using a zero value Expander.
 func ExpandVariables(src []byte, mapping func(string) string) []byte {
+	var e Expander
+	return e.Expand(src, mapping)
+}
+
+// ExpandVariablesString replaces ${var} in the string based on the mapping function.
+// See ExpandVariables for the supported syntax.
+func ExpandVariablesString(src string, mapping func(string) string) string {
+	var e Expander
+	return e.ExpandString(src, mapping)
+}
+
+// ExpandVariablesErr is like ExpandVariables but uses a mapping function that can fail.
+// This is useful if looking up a variable can fail, for example if the value is fetched
+// from a remote service. If mapping returns an error, expansion is stopped and the error
+// is returned wrapped with the name of the variable.
+func ExpandVariablesErr(src []byte, mapping func(string) (string, error)) ([]byte, error) {
+	var e Expander
+	return e.ExpandErr(src, mapping)
+}
+
+// ExpandVariablesStringErr is like ExpandVariablesString but uses a mapping function that can fail.
+// See ExpandVariablesErr for details.
+func ExpandVariablesStringErr(src string, mapping func(string) (string, error)) (string, error) {
+	var e Expander
+	return e.ExpandStringErr(src, mapping)
+}
+
+// ExpandVariablesStrict is like ExpandVariables but fails if any variables are not defined.
+// lookup returns the value of a variable and reports whether it is defined, for example os.LookupEnv.
+// A variable that is not defined but has a default value is not considered missing,
+// the default is used if the variable is not defined or is empty. Similarly, a variable
+// with an alternate value is not considered missing, since it only tests whether it is set.
+//
+// If any variables are missing, the returned error is a *MissingVariablesError that lists all of them.
+func ExpandVariablesStrict(src []byte, lookup func(string) (string, bool)) ([]byte, error) {
+	var e Expander
+	return e.ExpandStrict(src, lookup)
+}
+
+// ExpandVariablesStringStrict is like ExpandVariablesString but fails if any variables are not defined.
+// See ExpandVariablesStrict for details.
+func ExpandVariablesStringStrict(src string, lookup func(string) (string, bool)) (string, error) {
+	var e Expander
+	return e.ExpandStringStrict(src, lookup)
+}
+
+// MissingVariablesError is returned by strict expansion if variables are not defined.
+type MissingVariablesError struct {
+	// Names contains the missing variables in the order they were encountered, without duplicates.
+	Names []string
+}
+
+func (e *MissingVariablesError) Error() string {
+	if len(e.Names) == 1 {
+		return fmt.Sprintf("missing variable: %s", e.Names[0])
+	}
+	return fmt.Sprintf("missing variables: %s", strings.Join(e.Names, ", "))
+}
+
+// ListVariables returns the unique names of all variables referenced in src in the order
+// they first appear, using the syntax described in ExpandVariables. Variables that have a
+// default or alternate value are included. This is useful for validating that all required
+// values are available before expanding src.
+func ListVariables(src []byte) []string {
+	var e Expander
+	return e.ListVariables(src)
+}
+
+// ListVariablesString is like ListVariables but operates on a string.
+func ListVariablesString(src string) []string {
+	var e Expander
+	return e.ListVariablesString(src)
+}
+
+// Expander expands variables in text and allows customizing the supported syntax.
+// A zero value Expander is ready for use and supports the syntax described in ExpandVariables.
+type Expander struct {
+	// Bare enables expanding variables of the form $var in addition to ${var},
+	// similar to os.Expand. The name of a bare variable is the longest sequence of
+	// ASCII letters, digits and underscores following the $, and must not start with a digit.
+	// A $ that is not followed by a valid name is left as is. A literal $var can be
+	// produced by escaping it as $$var.
+	Bare bool
+
+	// Start and End optionally set the delimiters of a variable reference, which
+	// default to ${ and }. For example, setting both to % allows expanding %var%,
+	// and setting them to {{ and }} allows expanding {{var}}. Both must be set,
+	// otherwise the default delimiters are used. Default and alternate values are
+	// supported with custom delimiters, but escaping and bare variables are not.
+	Start, End string
+}
+
+// delimiters returns the delimiters of a variable reference. It reports whether they are custom.
+func (e *Expander) delimiters() (start, end string, custom bool) {
+	if e.Start == "" || e.End == "" {
+		return "${", "}", false
+	}
+	return e.Start, e.End, true
+}
+
+// Expand replaces variables in the byte slice based on the mapping function.
+// See ExpandVariables for details.
+func (e *Expander) Expand(src []byte, mapping func(string) string) []byte {
+	b, _ := e.expand(src, mapper{fn: mapping})
+	return b
+}
+
+// ExpandString replaces variables in the string based on the mapping function.
+// See ExpandVariables for details.
+func (e *Expander) ExpandString(src string, mapping func(string) string) string {
+	s, _ := e.expandString(src, mapper{fn: mapping})
+	return s
+}
+
+// ExpandErr is like Expand but uses a mapping function that can fail.
+// See ExpandVariablesErr for details.
+func (e *Expander) ExpandErr(src []byte, mapping func(string) (string, error)) ([]byte, error) {
+	return e.expand(src, mapper{fnErr: mapping})
+}
+
+// ExpandStringErr is like ExpandString but uses a mapping function that can fail.
+// See ExpandVariablesErr for details.
+func (e *Expander) ExpandStringErr(src string, mapping func(string) (string, error)) (string, error) {
+	return e.expandString(src, mapper{fnErr: mapping})
+}
+
+// ExpandStrict is like Expand but fails if any variables are not defined.
+// See ExpandVariablesStrict for details.
+func (e *Expander) ExpandStrict(src []byte, lookup func(string) (string, bool)) ([]byte, error) {
+	var missing []string
+	b, err := e.expand(src, mapper{fnLookup: lookup, missing: &missing})
+	if err == nil && len(missing) > 0 {
+		return nil, &MissingVariablesError{Names: missing}
+	}
+	return b, err
+}
+
+// ExpandStringStrict is like ExpandString but fails if any variables are not defined.
+// See ExpandVariablesStrict for details.
+func (e *Expander) ExpandStringStrict(src string, lookup func(string) (string, bool)) (string, error) {
+	var missing []string
+	s, err := e.expandString(src, mapper{fnLookup: lookup, missing: &missing})
+	if err == nil && len(missing) > 0 {
+		return "", &MissingVariablesError{Names: missing}
+	}
+	return s, err
+}
+
+// ListVariables returns the unique names of all variables referenced in src.
+// See the ListVariables function for details.
+func (e *Expander) ListVariables(src []byte) []string {
+	return listVariables(e, src)
+}
+
+// ListVariablesString returns the unique names of all variables referenced in src.
+// See the ListVariables function for details.
+func (e *Expander) ListVariablesString(src string) []string {
+	return listVariables(e, src)
+}
+
+func listVariables[T ~string | ~[]byte](e *Expander, src T) []string {
+	var names []string
+	for i := 0; i < len(src); {
+		ref, ok := scanVariable(e, src, i)
+		if !ok {
+			break
+		}
+		if !ref.escape {
+			names = appendUnique(names, string(src[ref.nameStart:ref.nameEnd]))
+		}
+		i = ref.end
+	}
+	return names
+}
+
+func (e *Expander) expand(src []byte, m mapper) ([]byte, error) {
 	var buf []byte
 	end := 0
-	for i := 0; i < len(src); i++ {
-		if i+2 > len(src) {
-			// Not enough chars left, can't be a variable
+	for i := 0; i < len(src); {
+		ref, ok := scanVariable(e, src, i)
+		if !ok {
 			break
 		}
-		if !(src[i] == '$' && src[i+1] == '{') {
-			continue
+		v, err := resolveVariable(src, ref, m)
+		if err != nil {
+			return nil, err
 		}
 		// Lazily initialize buf, explicitly allocate an array to save on allocations
 		if buf == nil {
 			buf = make([]byte, 0, 2*len(src))
 		}
-		buf = append(buf, src[end:i]...)
-
-		// Scan until we find a closing brace
-		varStart := i + 2
-		varEnd := -1
-		for j := varStart; j < len(src); j++ {
-			if src[j] == '}' {
-				varEnd = j
-				break
-			}
-		}
-		if varEnd == -1 {
-			// Bad syntax `${`, just ignore
-			i++
-			continue
-		}
-		if varEnd == varStart {
-			// Bad syntax `${}`, just ignore
-			i += 2
-			continue
-		}
-		name := src[varStart:varEnd]
-		buf = append(buf, mapping(string(name))...)
-		i += len(name) + 2
-		end = i + 1
+		buf = append(buf, src[end:ref.start]...)
+		buf = append(buf, v...)
+		i = ref.end
+		end = ref.end
 	}
 	if buf == nil {
-		return src
+		return src, nil
 	}
 	buf = append(buf, src[end:]...)
-	return buf
+	return buf, nil
 }
 
-// ExpandVariablesString replaces ${var} in the string based on the mapping function.
-func ExpandVariablesString(src string, mapping func(string) string) string {
+func (e *Expander) expandString(src string, m mapper) (string, error) {
 	var sb *strings.Builder
 	end := 0
-	for i := 0; i < len(src); i++ {
-		if i+2 > len(src) {
-			// Not enough chars left, can't be a variable
+	for i := 0; i < len(src); {
+		ref, ok := scanVariable(e, src, i)
+		if !ok {
 			break
 		}
-		if !(src[i] == '$' && src[i+1] == '{') {
-			continue
+		v, err := resolveVariable(src, ref, m)
+		if err != nil {
+			return "", err
 		}
 		// Lazily initialize sb, do an explicit grow to save on allocations
 		if sb == nil {
 			sb = &strings.Builder{}
 			sb.Grow(2 * len(src))
 		}
-		sb.WriteString(src[end:i])
+		sb.WriteString(src[end:ref.start])
+		sb.WriteString(v)
+		i = ref.end
+		end = ref.end
+	}
+	if sb == nil {
+		return src, nil
+	}
+	sb.WriteString(src[end:])
+	return sb.String(), nil
+}
+
+// mapper holds the mapping function used to expand variables.
+// Exactly one of fn, fnErr or fnLookup is set.
+type mapper struct {
+	fn       func(string) string
+	fnErr    func(string) (string, error)
+	fnLookup func(string) (string, bool)
+	missing  *[]string // records names that fnLookup did not find
+}
 
-		// Scan until we find a closing brace
-		varStart := i + 2
-		varEnd := -1
-		for j := varStart; j < len(src); j++ {
-			if src[j] == '}' {
-				varEnd = j
-				break
-			}
+// lookup returns the value of the variable name. It reports false if the variable
+// was not found, which is only possible if fnLookup is set.
+func (m mapper) lookup(name string) (string, bool, error) {
+	switch {
+	case m.fn != nil:
+		return m.fn(name), true, nil
+	case m.fnErr != nil:
+		v, err := m.fnErr(name)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to expand variable %q: %w", name, err)
 		}
-		if varEnd == -1 {
-			// Bad syntax `${`, just ignore
-			i++
-			continue
+		return v, true, nil
+	}
+	v, ok := m.fnLookup(name)
+	return v, ok, nil
+}
+
+// addMissing records name as missing if it was not already recorded.
+func (m mapper) addMissing(name string) {
+	*m.missing = appendUnique(*m.missing, name)
+}
+
+// appendUnique appends name to names if it is not already present.
+func appendUnique(names []string, name string) []string {
+	for _, n := range names {
+		if n == name {
+			return names
 		}
-		if varEnd == varStart {
-			// Bad syntax `${}`, just ignore
-			i += 2
+	}
+	return append(names, name)
+}
+
+// variableRef is the location of a variable reference within a template.
+type variableRef struct {
+	start, end         int  // bounds of the whole reference including $, { and }
+	nameStart, nameEnd int  // bounds of the variable name
+	wordStart, wordEnd int  // bounds of the default or alternate value, if op is set
+	op                 byte // '-' for a default value, '+' for an alternate value, or 0 for neither
+	escape             bool // the reference is an escaped $ that expands to a literal $
+}
+
+// scanVariable finds the first valid variable reference in src at or after i
+// using the syntax supported by e. It reports false if there are no more variables.
+func scanVariable[T ~string | ~[]byte](e *Expander, src T, i int) (variableRef, bool) {
+	start, _, _ := e.delimiters()
+	for ; i < len(src); i++ {
+		if src[i] != start[0] {
 			continue
 		}
-		name := src[varStart:varEnd]
-		sb.WriteString(mapping(name))
-		i += len(name) + 2
-		end = i + 1
+		if ref, res := matchVariable(e, src, i, true); res == matched {
+			return ref, true
+		}
 	}
-	if sb == nil {
-		return src
+	return variableRef{}, false
+}
+
+// matchResult is the result of matchVariable.
+type matchResult int
+
+const (
+	noMatch  matchResult = iota
+	matched              // a variable reference starts at i
+	needMore             // more input is needed to determine if there is a match
+)
+
+// matchVariable determines whether a variable reference using the syntax supported by e
+// starts at src[i], which must be the first byte of the start delimiter.
+// If atEOF is false, src may be followed by more input, and needMore is returned if the
+// result depends on it. If atEOF is true, needMore is never returned.
+func matchVariable[T ~string | ~[]byte](e *Expander, src T, i int, atEOF bool) (variableRef, matchResult) {
+	start, end, custom := e.delimiters()
+	if custom {
+		return matchDelimited(src, i, start, end, atEOF)
 	}
-	sb.WriteString(src[end:])
-	return sb.String()
+	if i+1 == len(src) {
+		return variableRef{}, moreOrNoMatch(atEOF)
+	}
+	if src[i+1] == '$' {
+		if i+2 == len(src) {
+			return variableRef{}, moreOrNoMatch(atEOF)
+		}
+		if src[i+2] == '{' || e.Bare && isNameStart(src[i+2]) {
+			// Escaped `$${` or `$$var`, skip past it so the rest isn't treated as a variable
+			return variableRef{start: i, end: i + 2, escape: true}, matched
+		}
+		return variableRef{}, noMatch
+	}
+	if src[i+1] != '{' {
+		if !e.Bare || !isNameStart(src[i+1]) {
+			return variableRef{}, noMatch
+		}
+		j := i + 2
+		for j < len(src) && isNameChar(src[j]) {
+			j++
+		}
+		if j == len(src) && !atEOF {
+			// The name may continue
+			return variableRef{}, needMore
+		}
+		return variableRef{start: i, end: j, nameStart: i + 1, nameEnd: j}, matched
+	}
+	return matchDelimited(src, i, start, end, atEOF)
+}
+
+// matchDelimited determines whether a variable reference of the form start name end,
+// start name:-default end, or start name:+alternate end, starts at src[i]. See matchVariable for details.
+func matchDelimited[T ~string | ~[]byte](src T, i int, start, end string, atEOF bool) (variableRef, matchResult) {
+	if len(src)-i < len(start) {
+		if string(src[i:]) == start[:len(src)-i] {
+			// src ends with part of the start delimiter
+			return variableRef{}, moreOrNoMatch(atEOF)
+		}
+		return variableRef{}, noMatch
+	}
+	if string(src[i:i+len(start)]) != start {
+		return variableRef{}, noMatch
+	}
+
+	// Scan until we find the end delimiter
+	varStart := i + len(start)
+	varEnd := -1
+	for j := varStart; j+len(end) <= len(src); j++ {
+		if string(src[j:j+len(end)]) == end {
+			varEnd = j
+			break
+		}
+	}
+	if varEnd == -1 {
+		// Bad syntax `${`, just ignore unless the end delimiter may still come
+		return variableRef{}, moreOrNoMatch(atEOF)
+	}
+	ref := variableRef{start: i, end: varEnd + len(end), nameStart: varStart, nameEnd: varEnd}
+	for j := varStart; j+1 < varEnd; j++ {
+		if src[j] == ':' && (src[j+1] == '-' || src[j+1] == '+') {
+			ref.nameEnd = j
+			ref.wordStart = j + 2
+			ref.wordEnd = varEnd
+			ref.op = src[j+1]
+			break
+		}
+	}
+	if ref.nameEnd == ref.nameStart {
+		// Bad syntax `${}`, `${:-default}` or `${:+alternate}`, just ignore
+		return variableRef{}, noMatch
+	}
+	return ref, matched
+}
+
+// moreOrNoMatch returns the result of a match that depends on input after the end of src.
+func moreOrNoMatch(atEOF bool) matchResult {
+	if atEOF {
+		return noMatch
+	}
+	return needMore
+}
+
+// isNameStart reports whether c can be the first character of a bare variable name.
+func isNameStart(c byte) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// isNameChar reports whether c can be part of a bare variable name.
+func isNameChar(c byte) bool {
+	return isNameStart(c) || '0' <= c && c <= '9'
+}
+
+// resolveVariable returns the value of the variable referenced by ref using m.
+func resolveVariable[T ~string | ~[]byte](src T, ref variableRef, m mapper) (string, error) {
+	if ref.escape {
+		return "$", nil
+	}
+	return m.resolve(string(src[ref.nameStart:ref.nameEnd]), ref.op, string(src[ref.wordStart:ref.wordEnd]))
+}
+
+// resolve returns the value of the variable name. If op is '-', word is used if the
+// variable is not defined or is empty. If op is '+', word is used if the variable is
+// defined and not empty, otherwise the result is empty.
+func (m mapper) resolve(name string, op byte, word string) (string, error) {
+	v, ok, err := m.lookup(name)
+	if err != nil {
+		return "", err
+	}
+	set := ok && v != ""
+	switch op {
+	case '-':
+		if !set {
+			return word, nil
+		}
+		return v, nil
+	case '+':
+		if set {
+			return word, nil
+		}
+		return "", nil
+	}
+	if !ok {
+		m.addMissing(name)
+	}
+	return v, nil
 }
 
 // VariableMapper can be used to expand variables with ExpandVariables or ExpandVariablesString.
@@ -139,3 +500,84 @@ func (vm *VariableMapper) Map(name string) string {
 	}
 	return ""
 }
+
+// textWriter is implemented by *strings.Builder and *bytesBuilder so that
+// functions can build both string and []byte results.
+type textWriter interface {
+	WriteString(s string) (int, error)
+	Write(p []byte) (int, error)
+	WriteByte(c byte) error
+}
+
+// bytesBuilder is a byte slice that implements textWriter.
+type bytesBuilder []byte
+
+func (b *bytesBuilder) WriteString(s string) (int, error) {
+	*b = append(*b, s...)
+	return len(s), nil
+}
+
+func (b *bytesBuilder) Write(p []byte) (int, error) {
+	*b = append(*b, p...)
+	return len(p), nil
+}
+
+func (b *bytesBuilder) WriteByte(c byte) error {
+	*b = append(*b, c)
+	return nil
+}
+
+// write writes s to w.
+func write[T string | []byte](w textWriter, s T) {
+	switch s := any(s).(type) {
+	case string:
+		w.WriteString(s)
+	case []byte:
+		w.Write(s)
+	}
+}
+
+// textWidth returns the display width of s. See color.Width.
+func textWidth[T string | []byte](s T) int {
+	switch s := any(s).(type) {
+	case string:
+		return color.Width(s)
+	case []byte:
+		return color.WidthBytes(s)
+	}
+	return 0
+}
+
+// escapeLen returns the length of the ANSI escape sequence at the start of s,
+// or 0 if there is none. See color.EscapeLen.
+func escapeLen[T string | []byte](s T) int {
+	switch s := any(s).(type) {
+	case string:
+		return color.EscapeLen(s)
+	case []byte:
+		return color.EscapeLenBytes(s)
+	}
+	return 0
+}
+
+// decodeRune decodes the first rune in s. See utf8.DecodeRune.
+func decodeRune[T string | []byte](s T) (rune, int) {
+	switch s := any(s).(type) {
+	case string:
+		return utf8.DecodeRuneInString(s)
+	case []byte:
+		return utf8.DecodeRune(s)
+	}
+	return utf8.RuneError, 0
+}
+
+// cutLine splits s around the first newline, which is not included in either result.
+// It reports whether a newline was found.
+func cutLine[T string | []byte](s T) (line, rest T, found bool) {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\n' {
+			return s[:i], s[i+1:], true
+		}
+	}
+	return s, s[len(s):], false
+}
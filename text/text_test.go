@@ -1,6 +1,7 @@
 package text_test
 
 import (
+	"errors"
 	"reflect"
 	"strings"
 	"testing"
@@ -25,6 +26,22 @@ var expandVariablesTests = []struct {
 	{"contains not vars", "start $HOME ${first} $$", "start $HOME abc $$"},
 	{"non-alphanum var", "path: ${@env:HOME}", "path: $HOME"},
 	{"side by side", "${first}${second}", "abcdef"},
+	{"default unused", "${HOME:-/root}", "/home/foo"},
+	{"default used", "${empty:-fallback}", "fallback"},
+	{"empty default", "a${empty:-}b", "ab"},
+	{"default with colon", "${empty:-http://localhost:8080}", "http://localhost:8080"},
+	{"default no name", "${:-fallback}", "${:-fallback}"}, // invalid syntax, will ignore
+	{"default then var", "${empty:-x} ${first}", "x abc"},
+	{"alternate used", "cmd ${first:+--flag}", "cmd --flag"},
+	{"alternate unused", "cmd${empty:+ --flag}", "cmd"},
+	{"empty alternate", "a${first:+}b", "ab"},
+	{"alternate with default syntax", "${first:+a:-b}", "a:-b"},
+	{"default with alternate syntax", "${empty:-a:+b}", "a:+b"},
+	{"alternate no name", "${:+alt}", "${:+alt}"}, // invalid syntax, will ignore
+	{"escaped", "$${HOME}", "${HOME}"},
+	{"escaped and var", "$${first} ${first}", "${first} abc"},
+	{"escape bare ignored", "$$HOME", "$$HOME"},
+	{"escaped after $", "$$${first}", "$${first}"},
 }
 
 func testMapping(name string) string {
@@ -32,6 +49,8 @@ func testMapping(name string) string {
 		return "$" + strings.TrimPrefix(name, "@env:")
 	}
 	switch name {
+	case "empty":
+		return ""
 	case "HOME":
 		return "/home/foo"
 	case "first":
@@ -64,6 +83,207 @@ func TestExpandVariablesString(t *testing.T) {
 	}
 }
 
+func testMappingErr(name string) (string, error) {
+	if name == "fail" {
+		return "", errFail
+	}
+	return testMapping(name), nil
+}
+
+var errFail = errors.New("lookup failed")
+
+func TestExpandVariablesErr(t *testing.T) {
+	for _, tt := range expandVariablesTests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := text.ExpandVariablesErr([]byte(tt.in), testMappingErr)
+			if err != nil {
+				t.Fatalf("want nil error, got %v", err)
+			}
+			if string(got) != tt.out {
+				t.Errorf("got %q, want %q", got, tt.out)
+			}
+			gotString, err := text.ExpandVariablesStringErr(tt.in, testMappingErr)
+			if err != nil {
+				t.Fatalf("want nil error, got %v", err)
+			}
+			if gotString != tt.out {
+				t.Errorf("got %q, want %q", gotString, tt.out)
+			}
+		})
+	}
+}
+
+func TestExpandVariablesErrFailed(t *testing.T) {
+	const in = "${first} ${fail} ${second}"
+	const wantErr = `failed to expand variable "fail": lookup failed`
+	got, err := text.ExpandVariablesErr([]byte(in), testMappingErr)
+	if got != nil {
+		t.Errorf("got %q, want nil", got)
+	}
+	if !errors.Is(err, errFail) || err.Error() != wantErr {
+		t.Errorf("got error %v, want %s", err, wantErr)
+	}
+	gotString, err := text.ExpandVariablesStringErr(in, testMappingErr)
+	if gotString != "" {
+		t.Errorf("got %q, want empty string", gotString)
+	}
+	if !errors.Is(err, errFail) || err.Error() != wantErr {
+		t.Errorf("got error %v, want %s", err, wantErr)
+	}
+}
+
+func testLookup(name string) (string, bool) {
+	if v := testMapping(name); v != "UNKNOWN_VAR" {
+		return v, true
+	}
+	return "", false
+}
+
+func TestExpandVariablesStrict(t *testing.T) {
+	tests := []struct {
+		name        string
+		in          string
+		out         string
+		wantMissing []string
+	}{
+		{"all defined", "${HOME} ${first}", "/home/foo abc", nil},
+		{"empty value", "a${empty}b", "ab", nil},
+		{"default", "${nope:-x} ${first}", "x abc", nil},
+		{"alternate", "${nope:+x}${first:+y}", "y", nil},
+		{"one missing", "${first} ${nope}", "", []string{"nope"}},
+		{"multiple missing", "${a} ${first} ${b} ${a}", "", []string{"a", "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := text.ExpandVariablesStrict([]byte(tt.in), testLookup)
+			gotString, errString := text.ExpandVariablesStringStrict(tt.in, testLookup)
+			if tt.wantMissing == nil {
+				if err != nil || errString != nil {
+					t.Fatalf("want nil errors, got %v and %v", err, errString)
+				}
+				if string(got) != tt.out || gotString != tt.out {
+					t.Errorf("got %q and %q, want %q", got, gotString, tt.out)
+				}
+				return
+			}
+			if got != nil || gotString != "" {
+				t.Errorf("got %q and %q, want empty results", got, gotString)
+			}
+			for _, err := range []error{err, errString} {
+				var mErr *text.MissingVariablesError
+				if !errors.As(err, &mErr) {
+					t.Fatalf("got error %v, want *MissingVariablesError", err)
+				}
+				if !reflect.DeepEqual(mErr.Names, tt.wantMissing) {
+					t.Errorf("got missing %v, want %v", mErr.Names, tt.wantMissing)
+				}
+			}
+		})
+	}
+}
+
+func TestMissingVariablesError(t *testing.T) {
+	err := &text.MissingVariablesError{Names: []string{"a"}}
+	if got, want := err.Error(), "missing variable: a"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	err.Names = append(err.Names, "b")
+	if got, want := err.Error(), "missing variables: a, b"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestExpanderBare(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		out  string
+	}{
+		{"bare var", "$HOME", "/home/foo"},
+		{"bare and braced", "$first ${second}", "abc def"},
+		{"name ends at non-name char", "$HOME/bin:$first.txt", "/home/foo/bin:abc.txt"},
+		{"underscore and digits", "$_a1", "UNKNOWN_VAR"},
+		{"digit start", "$1 $$ $", "$1 $$ $"},
+		{"side by side", "$first$second", "abcdef"},
+		{"unclosed brace", "${HOME $first", "${HOME abc"},
+		{"braced default", "$empty ${empty:-x}", " x"},
+		{"escaped bare", "$$HOME $${HOME}", "$HOME ${HOME}"},
+	}
+	e := text.Expander{Bare: true}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := e.ExpandString(tt.in, testMapping); got != tt.out {
+				t.Errorf("got %q, want %q", got, tt.out)
+			}
+			if got := e.Expand([]byte(tt.in), testMapping); string(got) != tt.out {
+				t.Errorf("got %q, want %q", got, tt.out)
+			}
+		})
+	}
+}
+
+func TestExpanderDelimiters(t *testing.T) {
+	tests := []struct {
+		name     string
+		expander text.Expander
+		in       string
+		out      string
+	}{
+		{"braces", text.Expander{Start: "{{", End: "}}"}, "a {{HOME}} {{first}}{{second}} ${HOME}", "a /home/foo abcdef ${HOME}"},
+		{"percent", text.Expander{Start: "%", End: "%"}, "%HOME%\\bin %first%", "/home/foo\\bin abc"},
+		{"default", text.Expander{Start: "{{", End: "}}"}, "{{empty:-x}}", "x"},
+		{"alternate", text.Expander{Start: "{{", End: "}}"}, "{{first:+x}}{{empty:+y}}", "x"},
+		{"unclosed", text.Expander{Start: "{{", End: "}}"}, "{{HOME} {{first}}", "UNKNOWN_VAR"},
+		{"empty name", text.Expander{Start: "%", End: "%"}, "100%% sure", "100%% sure"},
+		{"no escaping", text.Expander{Start: "{{", End: "}}"}, "${{first}}", "$abc"},
+		{"missing end uses defaults", text.Expander{Start: "{{"}, "{{first}} ${first}", "{{first}} abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.expander.ExpandString(tt.in, testMapping); got != tt.out {
+				t.Errorf("got %q, want %q", got, tt.out)
+			}
+			if got := tt.expander.Expand([]byte(tt.in), testMapping); string(got) != tt.out {
+				t.Errorf("got %q, want %q", got, tt.out)
+			}
+		})
+	}
+}
+
+func TestListVariables(t *testing.T) {
+	tests := []struct {
+		name     string
+		expander text.Expander
+		in       string
+		want     []string
+	}{
+		{"none", text.Expander{}, "no vars $HOME", nil},
+		{"unique in order", text.Expander{}, "${b} ${a} ${b} ${c:-x} ${d:+y}", []string{"b", "a", "c", "d"}},
+		{"escaped excluded", text.Expander{}, "$${a} ${b}", []string{"b"}},
+		{"bare", text.Expander{Bare: true}, "$a ${b} $$c", []string{"a", "b"}},
+		{"delimiters", text.Expander{Start: "{{", End: "}}"}, "{{a}} ${b}", []string{"a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.expander.ListVariablesString(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if got := tt.expander.ListVariables([]byte(tt.in)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if got := tt.expander.Parse(tt.in).Variables(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q from template, want %q", got, tt.want)
+			}
+		})
+	}
+	if got, want := text.ListVariables([]byte("${a}")), []string{"a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := text.ListVariablesString("${a}"), []string{"a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
 func TestVariableMapper(t *testing.T) {
 	vm := text.NewVariableMapper(map[string]string{
 		"HOME": "/home/foo",
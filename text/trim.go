@@ -0,0 +1,72 @@
+package text
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// TrimAnyPrefix returns s without the first of prefixes that s starts with,
+// and reports whether a prefix was removed. Prefixes are tried in order, so longer
+// prefixes should come before shorter prefixes they start with. If s does not start
+// with any of prefixes, s is returned unchanged.
+func TrimAnyPrefix(s string, prefixes ...string) (string, bool) {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return s[len(p):], true
+		}
+	}
+	return s, false
+}
+
+// TrimAnySuffix returns s without the first of suffixes that s ends with,
+// and reports whether a suffix was removed. See TrimAnyPrefix for details.
+func TrimAnySuffix(s string, suffixes ...string) (string, bool) {
+	for _, p := range suffixes {
+		if strings.HasSuffix(s, p) {
+			return s[:len(s)-len(p)], true
+		}
+	}
+	return s, false
+}
+
+// CommonPrefix returns the longest prefix shared by all strings in strs.
+// The prefix never ends in the middle of a UTF-8 encoded rune.
+// If strs is empty, an empty string is returned.
+func CommonPrefix(strs []string) string {
+	if len(strs) == 0 {
+		return ""
+	}
+	prefix := strs[0]
+	for _, s := range strs[1:] {
+		prefix = commonPrefix(prefix, s)
+	}
+	// Back up if the prefix ends in the middle of a rune.
+	n := len(prefix)
+	for n > 0 && n < len(strs[0]) && !utf8.RuneStart(strs[0][n]) {
+		n--
+	}
+	return prefix[:n]
+}
+
+// CommonSuffix returns the longest suffix shared by all strings in strs.
+// The suffix never starts in the middle of a UTF-8 encoded rune.
+// If strs is empty, an empty string is returned.
+func CommonSuffix(strs []string) string {
+	if len(strs) == 0 {
+		return ""
+	}
+	suffix := strs[0]
+	for _, s := range strs[1:] {
+		n := min(len(suffix), len(s))
+		i := 0
+		for i < n && suffix[len(suffix)-1-i] == s[len(s)-1-i] {
+			i++
+		}
+		suffix = suffix[len(suffix)-i:]
+	}
+	// Skip forward if the suffix starts in the middle of a rune.
+	for len(suffix) > 0 && !utf8.RuneStart(suffix[0]) {
+		suffix = suffix[1:]
+	}
+	return suffix
+}
@@ -0,0 +1,102 @@
+package text_test
+
+import (
+	"testing"
+
+	"github.com/TouchBistro/goutils/text"
+)
+
+func TestTrimAnyPrefix(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		prefixes []string
+		want     string
+		wantOk   bool
+	}{
+		{"no prefixes", "https://example.com", nil, "https://example.com", false},
+		{"no match", "ftp://example.com", []string{"https://", "http://"}, "ftp://example.com", false},
+		{"match", "http://example.com", []string{"https://", "http://"}, "example.com", true},
+		{"first match wins", "refs/heads/main", []string{"refs/", "refs/heads/"}, "heads/main", true},
+		{"empty prefix", "main", []string{""}, "main", true},
+		{"whole string", "main", []string{"main"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := text.TrimAnyPrefix(tt.in, tt.prefixes...)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("got %q, %t, want %q, %t", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestTrimAnySuffix(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		suffixes []string
+		want     string
+		wantOk   bool
+	}{
+		{"no suffixes", "config.yaml", nil, "config.yaml", false},
+		{"no match", "config.json", []string{".yaml", ".yml"}, "config.json", false},
+		{"match", "config.yml", []string{".yaml", ".yml"}, "config", true},
+		{"first match wins", "archive.tar.gz", []string{".gz", ".tar.gz"}, "archive.tar", true},
+		{"whole string", ".yml", []string{".yml"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := text.TrimAnySuffix(tt.in, tt.suffixes...)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("got %q, %t, want %q, %t", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestCommonPrefix(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []string
+		want string
+	}{
+		{"none", nil, ""},
+		{"one", []string{"foo"}, "foo"},
+		{"paths", []string{"/usr/local/bin", "/usr/local/lib", "/usr/local"}, "/usr/local"},
+		{"nothing shared", []string{"foo", "bar"}, ""},
+		{"empty string", []string{"foo", ""}, ""},
+		{"identical", []string{"foo", "foo"}, "foo"},
+		{"multibyte", []string{"héllo", "hèllo"}, "h"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := text.CommonPrefix(tt.in); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommonSuffix(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []string
+		want string
+	}{
+		{"none", nil, ""},
+		{"one", []string{"foo"}, "foo"},
+		{"names", []string{"api-service", "web-service", "worker-service"}, "-service"},
+		{"nothing shared", []string{"foo", "bar"}, ""},
+		{"empty string", []string{"foo", ""}, ""},
+		{"shorter first", []string{"ce", "piece"}, "ce"},
+		{"multibyte", []string{"xé", "xũ"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := text.CommonSuffix(tt.in); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package text
+
+import (
+	"strings"
+
+	"github.com/TouchBistro/goutils/color"
+)
+
+// Truncate shortens s so that it is at most max columns wide when displayed and
+// appends ellipsis if s was shortened. The width of ellipsis counts towards max,
+// so the result is never wider than max. If ellipsis is wider than max, it is omitted.
+//
+// Width is measured using color.Width and s is never cut in the middle of a rune.
+// ANSI escape sequences do not count towards the width and are kept even if the text
+// around them is removed, so that colors are still reset properly.
+// If s is not wider than max, s is returned as is.
+func Truncate(s string, max int, ellipsis string) string {
+	if color.Width(s) <= max {
+		return s
+	}
+	var sb strings.Builder
+	sb.Grow(len(s))
+	truncate(&sb, s, max, ellipsis)
+	return sb.String()
+}
+
+// TruncateBytes is like Truncate but operates on a byte slice. The returned byte slice is a copy
+// of b with the truncation applied, b is not modified. If b is not wider than max, b is returned as is.
+func TruncateBytes(b []byte, max int, ellipsis string) []byte {
+	if color.WidthBytes(b) <= max {
+		return b
+	}
+	buf := bytesBuilder(make([]byte, 0, len(b)))
+	truncate(&buf, b, max, ellipsis)
+	return buf
+}
+
+func truncate[T string | []byte](w textWriter, s T, max int, ellipsis string) {
+	limit := max - color.Width(ellipsis)
+	if limit < 0 {
+		limit = max
+		ellipsis = ""
+	}
+	width := 0
+	cut := false
+	for i := 0; i < len(s); {
+		if n := escapeLen(s[i:]); n > 0 {
+			write(w, s[i:i+n])
+			i += n
+			continue
+		}
+		r, size := decodeRune(s[i:])
+		i += size
+		if cut {
+			continue
+		}
+		rw := color.RuneWidth(r)
+		if width+rw > limit {
+			// Write the ellipsis immediately so that it is styled the same as the text it replaces.
+			w.WriteString(ellipsis)
+			cut = true
+			continue
+		}
+		write(w, s[i-size:i])
+		width += rw
+	}
+}
@@ -0,0 +1,39 @@
+package text_test
+
+import (
+	"testing"
+
+	"github.com/TouchBistro/goutils/text"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		max      int
+		ellipsis string
+		want     string
+	}{
+		{"empty", "", 5, "...", ""},
+		{"fits", "hello", 5, "...", "hello"},
+		{"truncated", "hello world", 8, "...", "hello..."},
+		{"no ellipsis", "hello world", 5, "", "hello"},
+		{"unicode ellipsis", "hello world", 6, "…", "hello…"},
+		{"multi-byte runes", "héllo wörld", 8, "...", "héllo..."},
+		{"wide runes", "日本語の文章", 7, "...", "日本..."},
+		{"wide rune at boundary", "日本語の文章", 8, "...", "日本..."},
+		{"ellipsis too wide", "hello world", 2, "...", "he"},
+		{"ansi", "\x1b[31mhello world\x1b[39m", 8, "...", "\x1b[31mhello...\x1b[39m"},
+		{"ansi not counted", "\x1b[31mhello\x1b[39m", 5, "...", "\x1b[31mhello\x1b[39m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := text.Truncate(tt.in, tt.max, tt.ellipsis); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if got := text.TruncateBytes([]byte(tt.in), tt.max, tt.ellipsis); string(got) != tt.want {
+				t.Errorf("TruncateBytes: got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
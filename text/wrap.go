@@ -0,0 +1,178 @@
+package text
+
+import (
+	"strings"
+
+	"github.com/TouchBistro/goutils/color"
+)
+
+// Wrap wraps s at word boundaries so that each line is at most width columns wide.
+// Width is measured using color.Width, so ANSI escape sequences are ignored and
+// wide characters are accounted for.
+//
+// Existing newlines are preserved and lines that already fit are left as is.
+// When a line is wrapped, its leading whitespace is kept on the first line and
+// words are separated by a single space. A word that is wider than width is placed
+// on its own line and is not broken. If width is less than 1, s is returned as is.
+func Wrap(s string, width int) string {
+	return WrapIndent(s, width, "")
+}
+
+// WrapIndent is like Wrap but prefixes each line created by wrapping with indent,
+// which creates a hanging indent. The width of indent counts towards width.
+func WrapIndent(s string, width int, indent string) string {
+	if width < 1 || fits(s, width) {
+		return s
+	}
+	var sb strings.Builder
+	sb.Grow(len(s) + len(s)/width*(len(indent)+1))
+	wrap(&sb, s, width, indent)
+	return sb.String()
+}
+
+// WrapBytes is like Wrap but operates on a byte slice. The returned byte slice is a copy of b
+// with the wrapping applied, b is not modified. If no lines need to be wrapped, b is returned as is.
+func WrapBytes(b []byte, width int) []byte {
+	return WrapIndentBytes(b, width, "")
+}
+
+// WrapIndentBytes is like WrapIndent but operates on a byte slice.
+// See WrapBytes for details.
+func WrapIndentBytes(b []byte, width int, indent string) []byte {
+	if width < 1 || fits(b, width) {
+		return b
+	}
+	buf := bytesBuilder(make([]byte, 0, len(b)+len(b)/width*(len(indent)+1)))
+	wrap(&buf, b, width, indent)
+	return buf
+}
+
+// Fill reflows the paragraphs in s so that each line is at most width columns wide,
+// and adds prefix to the beginning of every line. The width of prefix counts towards width.
+// Paragraphs are separated by lines that are empty or consist solely of whitespace.
+// This is useful for rendering long descriptions, for example in help output.
+//
+// Unlike Wrap, the lines within a paragraph are joined before wrapping, so existing line
+// breaks are not preserved and whitespace between words is collapsed to a single space.
+// Blank lines are preserved as is, except that they are written with prefix without any
+// trailing whitespace, so that the result does not contain trailing whitespace.
+// A word that is wider than the available width is placed on its own line and is not broken.
+func Fill(s string, width int, prefix string) string {
+	var sb strings.Builder
+	sb.Grow(len(s) + len(s)/max(width, 1)*(len(prefix)+1))
+	blankPrefix := strings.TrimRight(prefix, " \t")
+	prefixWidth := color.Width(prefix)
+	var words []string // words of the current paragraph
+	flush := func() {
+		col := 0
+		for i, word := range words {
+			wordWidth := color.Width(word)
+			if i == 0 || col+1+wordWidth > width {
+				if i > 0 {
+					sb.WriteByte('\n')
+				}
+				sb.WriteString(prefix)
+				col = prefixWidth
+			} else {
+				sb.WriteByte(' ')
+				col++
+			}
+			sb.WriteString(word)
+			col += wordWidth
+		}
+		if len(words) > 0 {
+			sb.WriteByte('\n')
+		}
+		words = words[:0]
+	}
+	for rest := s; rest != ""; {
+		line, next, _ := cutLine(rest)
+		if strings.TrimSpace(line) == "" {
+			flush()
+			sb.WriteString(blankPrefix)
+			sb.WriteByte('\n')
+		} else {
+			words = append(words, strings.Fields(line)...)
+		}
+		rest = next
+	}
+	flush()
+	out := sb.String()
+	if !strings.HasSuffix(s, "\n") {
+		// Every line written has a newline, remove the last one to match s.
+		out = strings.TrimSuffix(out, "\n")
+	}
+	return out
+}
+
+// fits reports whether every line in s is at most width columns wide.
+func fits[T string | []byte](s T, width int) bool {
+	for len(s) > 0 {
+		line, rest, _ := cutLine(s)
+		if textWidth(line) > width {
+			return false
+		}
+		s = rest
+	}
+	return true
+}
+
+func isBlank(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+func wrap[T string | []byte](w textWriter, s T, width int, indent string) {
+	indentWidth := color.Width(indent)
+	for {
+		line, rest, found := cutLine(s)
+		if textWidth(line) <= width {
+			write(w, line)
+		} else {
+			wrapLine(w, line, width, indent, indentWidth)
+		}
+		if !found {
+			return
+		}
+		w.WriteByte('\n')
+		s = rest
+	}
+}
+
+// wrapLine wraps a single line that contains no newlines.
+func wrapLine[T string | []byte](w textWriter, line T, width int, indent string, indentWidth int) {
+	i := 0
+	for i < len(line) && isBlank(line[i]) {
+		i++
+	}
+	write(w, line[:i])
+	col := textWidth(line[:i])
+	first := true
+	for {
+		for i < len(line) && isBlank(line[i]) {
+			i++
+		}
+		if i == len(line) {
+			return
+		}
+		j := i
+		for j < len(line) && !isBlank(line[j]) {
+			j++
+		}
+		word := line[i:j]
+		wordWidth := textWidth(word)
+		if !first {
+			if col+1+wordWidth > width {
+				w.WriteByte('\n')
+				w.WriteString(indent)
+				col = indentWidth
+			} else {
+				w.WriteByte(' ')
+				col++
+			}
+		}
+		write(w, word)
+		col += wordWidth
+		first = false
+		i = j
+	}
+}
@@ -0,0 +1,78 @@
+package text_test
+
+import (
+	"testing"
+
+	"github.com/TouchBistro/goutils/text"
+)
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		width  int
+		indent string
+		want   string
+	}{
+		{"empty", "", 10, "", ""},
+		{"fits", "hello world", 11, "", "hello world"},
+		{"wrap", "the quick brown fox jumps", 10, "", "the quick\nbrown fox\njumps"},
+		{"preserves newlines", "one two\nthree four five", 9, "", "one two\nthree\nfour five"},
+		{"blank lines", "a b c\n\nd e f", 3, "", "a b\nc\n\nd e\nf"},
+		{"long word", "a verylongword b", 5, "", "a\nverylongword\nb"},
+		{"collapses spaces", "a   b    c   d", 5, "", "a b c\nd"},
+		{"leading whitespace", "  foo bar baz", 9, "", "  foo bar\nbaz"},
+		{"hanging indent", "usage: tool command with many arguments", 16, "    ", "usage: tool\n    command with\n    many\n    arguments"},
+		{"ansi", "\x1b[31mred\x1b[39m word here", 8, "", "\x1b[31mred\x1b[39m word\nhere"},
+		{"wide runes", "日本 語の 文章", 9, "", "日本 語の\n文章"},
+		{"no wrapping", "a b c", 0, "", "a b c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := text.WrapIndent(tt.in, tt.width, tt.indent); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if got := text.WrapIndentBytes([]byte(tt.in), tt.width, tt.indent); string(got) != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if tt.indent == "" {
+				if got := text.Wrap(tt.in, tt.width); got != tt.want {
+					t.Errorf("got %q, want %q", got, tt.want)
+				}
+				if got := text.WrapBytes([]byte(tt.in), tt.width); string(got) != tt.want {
+					t.Errorf("got %q, want %q", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestFill(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		width  int
+		prefix string
+		want   string
+	}{
+		{"empty", "", 10, "  ", ""},
+		{"fits", "hello world", 20, "  ", "  hello world"},
+		{"joins lines", "the quick\nbrown fox\njumps over", 30, "", "the quick brown fox jumps over"},
+		{"reflows", "the quick brown\nfox jumps", 12, "  ", "  the quick\n  brown fox\n  jumps"},
+		{"paragraphs", "one two\nthree\n\nfour five\nsix", 20, "", "one two three\n\nfour five six"},
+		{"multiple blank lines", "a\n\n\nb", 10, "", "a\n\n\nb"},
+		{"whitespace only line", "a\n  \t\nb", 10, "  ", "  a\n\n  b"},
+		{"blank line prefix", "a\n\nb", 10, "# ", "# a\n#\n# b"},
+		{"trailing newline", "a b\nc\n", 10, "", "a b c\n"},
+		{"collapses whitespace", "  a   b\t\tc  ", 10, "", "a b c"},
+		{"long word", "a verylongword b", 8, "> ", "> a\n> verylongword\n> b"},
+		{"ansi", "\x1b[31mred\x1b[39m word\nhere", 10, "", "\x1b[31mred\x1b[39m word\nhere"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := text.Fill(tt.in, tt.width, tt.prefix); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}